@@ -0,0 +1,171 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+const adoptDesc = `
+This command brings existing Kubernetes resources under the management of a
+new release.
+
+The chart is rendered like it would be for an install. Every live object with
+the same kind and name as a rendered resource is annotated as belonging to the
+release, so that later upgrades and deletions of the release manage it.
+Resources that do not exist yet are created by the next 'helm upgrade'; until
+then, the release is recorded as FAILED. Hooks are neither run nor adopted.
+
+Objects that already belong to another release are never adopted. Use
+'--dry-run' to see what would change without touching any object:
+
+	$ helm adopt --chart stable/mariadb --dry-run my-database
+`
+
+type adoptCmd struct {
+	valuesOptions
+
+	name      string
+	chart     string
+	namespace string
+	dryRun    bool
+	version   string
+	repoURL   string
+	out       io.Writer
+	client    helm.Interface
+}
+
+func newAdoptCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	adopt := &adoptCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "adopt [flags] RELEASE",
+		Short:   "bring existing resources under a new release",
+		Long:    adoptDesc,
+		PreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			if adopt.chart == "" {
+				return errors.New("a chart must be specified with --chart")
+			}
+			adopt.name = args[0]
+			adopt.client = ensureHelmClient(adopt.client)
+			return adopt.run()
+		},
+	}
+
+	f := cmd.Flags()
+	adopt.valuesOptions.addFlags(f)
+	f.StringVar(&adopt.chart, "chart", "", "chart describing the resources to adopt")
+	f.StringVar(&adopt.namespace, "namespace", "", "namespace of the resources to adopt")
+	f.BoolVar(&adopt.dryRun, "dry-run", false, "show what would change without adopting any resource")
+	f.StringVar(&adopt.version, "version", "", "specify the exact chart version to use. If this is not specified, the latest version is used")
+	f.StringVar(&adopt.repoURL, "repo", "", "chart repository url where to locate the requested chart")
+
+	return cmd
+}
+
+func (a *adoptCmd) run() error {
+	if a.namespace == "" {
+		a.namespace = defaultNamespace()
+	}
+
+	chartPath, err := locateChartPath(a.repoURL, "", "", false, a.chart, a.version, false, defaultKeyring(), a.certFile, a.keyFile, a.caFile)
+	if err != nil {
+		return err
+	}
+
+	rawVals, err := a.vals()
+	if err != nil {
+		return err
+	}
+
+	ch, err := chartutil.Load(chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	if req, err := chartutil.LoadRequirements(ch); err == nil {
+		if err := checkDependencies(ch, req); err != nil {
+			return prettyError(err)
+		}
+	} else if err != chartutil.ErrRequirementsNotFound {
+		return fmt.Errorf("cannot load requirements: %v", err)
+	}
+
+	res, err := a.client.AdoptReleaseFromChart(
+		a.name,
+		ch,
+		a.namespace,
+		helm.AdoptValueOverrides(rawVals),
+		helm.AdoptDryRun(a.dryRun))
+	if err != nil {
+		return prettyError(err)
+	}
+	a.printResources(res.Resources)
+
+	if a.dryRun {
+		return nil
+	}
+	if res.Release.GetInfo().GetStatus().GetCode() == release.Status_FAILED {
+		fmt.Fprintf(a.out, "Release %q is FAILED: %s\nRun 'helm upgrade' to create the missing resources.\n", a.name, res.Release.Info.Description)
+		return nil
+	}
+	fmt.Fprintf(a.out, "Release %q has adopted its resources. Happy Helming!\n", a.name)
+	return nil
+}
+
+func (a *adoptCmd) printResources(resources []*services.AdoptedResource) {
+	if len(resources) == 0 {
+		return
+	}
+	table := uitable.New()
+	table.MaxColWidth = 60
+	table.AddRow("KIND", "NAME", "STATUS")
+	for _, r := range resources {
+		status := "adopted"
+		switch {
+		case !r.Found:
+			status = "missing, created on upgrade"
+		case a.dryRun:
+			status = "would be adopted"
+		}
+		table.AddRow(r.Kind, r.Name, status)
+	}
+	fmt.Fprintln(a.out, table.String())
+
+	for _, r := range resources {
+		if r.Diff == "" {
+			continue
+		}
+		fmt.Fprintf(a.out, "\n==> %s/%s\n%s\n", r.Kind, r.Name, r.Diff)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestAdoptCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "adopt resources",
+			args:     []string{"legacy"},
+			flags:    strings.Split("--chart testdata/testcharts/alpine", " "),
+			resp:     releaseMock(&releaseOptions{name: "legacy"}),
+			expected: "Release \"legacy\" has adopted its resources. Happy Helming!",
+		},
+		{
+			name:     "adopt with values",
+			args:     []string{"legacy"},
+			flags:    strings.Split("--chart testdata/testcharts/alpine --set foo=bar", " "),
+			resp:     releaseMock(&releaseOptions{name: "legacy"}),
+			expected: "Release \"legacy\" has adopted its resources. Happy Helming!",
+		},
+		{
+			name:     "adopt with typed values",
+			args:     []string{"legacy"},
+			flags:    strings.Split("--chart testdata/testcharts/alpine --set-string foo=0123 --set-json bar=[1]", " "),
+			resp:     releaseMock(&releaseOptions{name: "legacy"}),
+			expected: "Release \"legacy\" has adopted its resources. Happy Helming!",
+		},
+		{
+			name:     "adopt with missing resources",
+			args:     []string{"legacy"},
+			flags:    strings.Split("--chart testdata/testcharts/alpine", " "),
+			resp:     releaseMock(&releaseOptions{name: "legacy", statusCode: release.Status_FAILED}),
+			expected: "Release \"legacy\" is FAILED: Release mock\nRun 'helm upgrade' to create the missing resources.",
+		},
+		{
+			name: "adopt without chart",
+			args: []string{"legacy"},
+			err:  true,
+		},
+		{
+			name:  "adopt without release name",
+			flags: strings.Split("--chart testdata/testcharts/alpine", " "),
+			err:   true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newAdoptCmd(c, out)
+	})
+}
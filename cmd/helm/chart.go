@@ -0,0 +1,76 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/registry"
+)
+
+const chartHelp = `
+This command consists of multiple subcommands to store charts in OCI registries.
+
+Charts are saved to, and pulled into, a local cache in $HELM_HOME, and pushed
+from it. A chart is referenced as REGISTRY/REPOSITORY:TAG, where the tag is
+usually the chart version. Example usage:
+
+    $ helm chart save ./mychart localhost:5000/myrepo/mychart:0.1.0
+    $ helm chart push localhost:5000/myrepo/mychart:0.1.0
+    $ helm chart pull localhost:5000/myrepo/mychart:0.1.0
+
+'helm install', 'helm upgrade' and 'helm fetch' also accept charts in OCI
+registries, as oci://REGISTRY/REPOSITORY:TAG.
+`
+
+func newChartCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chart save|push|pull [ARGS]",
+		Short: "save, push and pull charts in OCI registries",
+		Long:  chartHelp,
+	}
+
+	cmd.AddCommand(newChartSaveCmd(out))
+	cmd.AddCommand(newChartPushCmd(out))
+	cmd.AddCommand(newChartPullCmd(out))
+
+	return cmd
+}
+
+// registryFlags are the flags of the commands that access OCI registries.
+type registryFlags struct {
+	plainHTTP bool
+	username  string
+	password  string
+}
+
+func (r *registryFlags) addFlags(f *pflag.FlagSet) {
+	f.BoolVar(&r.plainHTTP, "plain-http", false, "access the registry over HTTP instead of HTTPS")
+	f.StringVar(&r.username, "username", "", "registry username")
+	f.StringVar(&r.password, "password", "", "registry password")
+}
+
+func (r *registryFlags) client() *registry.Client {
+	c := registry.NewClient()
+	c.PlainHTTP = r.plainHTTP
+	c.Username = r.username
+	c.Password = r.password
+	return c
+}
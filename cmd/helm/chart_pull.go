@@ -0,0 +1,78 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/registry"
+)
+
+const chartPullDesc = `
+This command downloads a chart from an OCI registry to the local registry cache.
+`
+
+type chartPullCmd struct {
+	ref      string
+	registry registryFlags
+	home     helmpath.Home
+	out      io.Writer
+}
+
+func newChartPullCmd(out io.Writer) *cobra.Command {
+	p := &chartPullCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "pull [flags] [REF]",
+		Short: "pull a chart from an OCI registry",
+		Long:  chartPullDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart reference"); err != nil {
+				return err
+			}
+			p.ref = args[0]
+			p.home = settings.Home
+			return p.run()
+		},
+	}
+
+	p.registry.addFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (p *chartPullCmd) run() error {
+	ref, err := registry.ParseReference(p.ref)
+	if err != nil {
+		return err
+	}
+	cache := registry.NewCache(p.home.Registry())
+	digest, err := p.registry.client().Pull(ref, cache)
+	if err != nil {
+		return err
+	}
+	md, _, err := cache.ChartArchive(ref)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "ref:     %s\ndigest:  %s\nname:    %s\nversion: %s\n", ref, digest, md.Name, md.Version)
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/registry"
+)
+
+const chartPushDesc = `
+This command uploads a chart from the local registry cache to an OCI registry.
+The chart must have been saved with 'helm chart save' first.
+`
+
+type chartPushCmd struct {
+	ref      string
+	registry registryFlags
+	home     helmpath.Home
+	out      io.Writer
+}
+
+func newChartPushCmd(out io.Writer) *cobra.Command {
+	p := &chartPushCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "push [flags] [REF]",
+		Short: "push a chart to an OCI registry",
+		Long:  chartPushDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart reference"); err != nil {
+				return err
+			}
+			p.ref = args[0]
+			p.home = settings.Home
+			return p.run()
+		},
+	}
+
+	p.registry.addFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (p *chartPushCmd) run() error {
+	ref, err := registry.ParseReference(p.ref)
+	if err != nil {
+		return err
+	}
+	digest, err := p.registry.client().Push(ref, registry.NewCache(p.home.Registry()))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "ref:     %s\ndigest:  %s\n", ref, digest)
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/registry"
+)
+
+const chartSaveDesc = `
+This command saves a chart directory or archive to the local registry cache,
+so that it can be pushed with 'helm chart push'.
+
+If the reference has no tag, the chart version is used as the tag.
+`
+
+type chartSaveCmd struct {
+	path string
+	ref  string
+	home helmpath.Home
+	out  io.Writer
+}
+
+func newChartSaveCmd(out io.Writer) *cobra.Command {
+	s := &chartSaveCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "save [flags] [PATH] [REF]",
+		Short: "save a chart to the local registry cache",
+		Long:  chartSaveDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "path to the chart", "chart reference"); err != nil {
+				return err
+			}
+			s.path = args[0]
+			s.ref = args[1]
+			s.home = settings.Home
+			return s.run()
+		},
+	}
+
+	return cmd
+}
+
+func (s *chartSaveCmd) run() error {
+	ch, err := chartutil.Load(s.path)
+	if err != nil {
+		return err
+	}
+	ref, err := registry.ParseReference(s.ref)
+	if err != nil {
+		return err
+	}
+	if ref.Tag == "" {
+		ref.Tag = registry.TagForVersion(ch.Metadata.Version)
+		if err := ref.Validate(); err != nil {
+			return err
+		}
+	}
+
+	digest, err := registry.NewCache(s.home.Registry()).SaveChart(ch, ref)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "ref:     %s\ndigest:  %s\n", ref, digest)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/registry/registrytest"
+)
+
+func TestChartSavePushPull(t *testing.T) {
+	srv := registrytest.NewServer()
+	defer srv.Stop()
+
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := settings.Home
+	settings.Home = hh
+	defer func() {
+		settings.Home = old
+		os.RemoveAll(hh.String())
+	}()
+
+	run := func(cmd func(*bytes.Buffer) error) string {
+		buf := bytes.NewBuffer(nil)
+		if err := cmd(buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	ref := srv.Host() + "/charts/alpine"
+
+	out := run(func(buf *bytes.Buffer) error {
+		c := newChartSaveCmd(buf)
+		return c.RunE(c, []string{"testdata/testcharts/alpine", ref})
+	})
+	if !strings.Contains(out, "ref:     "+ref+":0.1.0") {
+		t.Errorf("Expected the chart version as the tag, got %q", out)
+	}
+	run(func(buf *bytes.Buffer) error {
+		c := newChartPushCmd(buf)
+		return c.RunE(c, []string{ref + ":0.1.0"})
+	})
+
+	// Pull into an empty cache.
+	if err := os.RemoveAll(hh.Registry()); err != nil {
+		t.Fatal(err)
+	}
+	out = run(func(buf *bytes.Buffer) error {
+		c := newChartPullCmd(buf)
+		return c.RunE(c, []string{ref + ":0.1.0"})
+	})
+	if !strings.Contains(out, "name:    alpine\nversion: 0.1.0") {
+		t.Errorf("Unexpected output %q", out)
+	}
+
+	// Charts in registries can be fetched like charts in repositories.
+	fetch := newFetchCmd(bytes.NewBuffer(nil))
+	fetch.Flags().Set("destination", hh.String())
+	if err := fetch.RunE(fetch, []string{"oci://" + ref + ":0.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chartutil.Load(filepath.Join(hh.String(), "alpine-0.1.0.tgz")); err != nil {
+		t.Error(err)
+	}
+
+	c := newChartPushCmd(bytes.NewBuffer(nil))
+	if err := c.RunE(c, []string{ref + ":0.2.0"}); err == nil {
+		t.Error("Expected an error for pushing a chart that was not saved")
+	}
+}
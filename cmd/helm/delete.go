@@ -32,6 +32,9 @@ It removes all of the resources associated with the last release of the chart.
 
 Use the '--dry-run' flag to see which releases will be deleted without actually
 deleting them.
+
+Custom resource definitions are kept, since deleting one deletes every custom
+resource of its kind in the cluster. Use '--delete-crds' to delete them too.
 `
 
 type deleteCmd struct {
@@ -39,6 +42,7 @@ type deleteCmd struct {
 	dryRun       bool
 	disableHooks bool
 	purge        bool
+	deleteCRDs   bool
 	timeout      int64
 
 	out    io.Writer
@@ -80,6 +84,7 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&del.dryRun, "dry-run", false, "simulate a delete")
 	f.BoolVar(&del.disableHooks, "no-hooks", false, "prevent hooks from running during deletion")
 	f.BoolVar(&del.purge, "purge", false, "remove the release from the store and make its name free for later use")
+	f.BoolVar(&del.deleteCRDs, "delete-crds", false, "also delete the custom resource definitions of the release")
 	f.Int64Var(&del.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
 
 	return cmd
@@ -90,6 +95,7 @@ func (d *deleteCmd) run() error {
 		helm.DeleteDryRun(d.dryRun),
 		helm.DeleteDisableHooks(d.disableHooks),
 		helm.DeletePurge(d.purge),
+		helm.DeleteCRDs(d.deleteCRDs),
 		helm.DeleteTimeout(d.timeout),
 	}
 	res, err := d.client.DeleteRelease(d.name, opts...)
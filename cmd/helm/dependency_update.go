@@ -32,8 +32,13 @@ This command verifies that the required charts, as expressed in 'requirements.ya
 are present in 'charts/' and are at an acceptable version. It will pull down
 the latest charts that satisfy the dependencies, and clean up old dependencies.
 
+The requirements of the dependencies are resolved as well, so that every chart
+in the dependency graph gets one version that satisfies all requirements on it.
+If there is no such version, the conflicting chains of requirements are listed.
+
 On successful update, this will generate a lock file that can be used to
-rebuild the requirements to an exact version.
+rebuild the requirements to an exact version. The lock file records the whole
+dependency graph, with the digest of each chart archive.
 
 Dependencies are not required to be represented in 'requirements.yaml'. For that
 reason, an update command will not remove charts unless they are (a) present
@@ -46,12 +46,15 @@ result in an error, and the chart will not be saved locally.
 `
 
 type fetchCmd struct {
-	untar    bool
-	untardir string
-	chartRef string
-	destdir  string
-	version  string
-	repoURL  string
+	untar     bool
+	untardir  string
+	chartRef  string
+	destdir   string
+	version   string
+	repoURL   string
+	username  string
+	password  string
+	plainHTTP bool
 
 	verify      bool
 	verifyLater bool
@@ -102,6 +105,9 @@ func newFetchCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&fch.keyring, "keyring", defaultKeyring(), "keyring containing public keys")
 	f.StringVarP(&fch.destdir, "destination", "d", ".", "location to write the chart. If this and tardir are specified, tardir is appended to this")
 	f.StringVar(&fch.repoURL, "repo", "", "chart repository url where to locate the requested chart")
+	f.StringVar(&fch.username, "username", "", "chart repository username")
+	f.StringVar(&fch.password, "password", "", "chart repository password")
+	f.BoolVar(&fch.plainHTTP, "plain-http", false, "access the OCI registry of an oci:// chart reference over HTTP instead of HTTPS")
 	f.StringVar(&fch.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
 	f.StringVar(&fch.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
 	f.StringVar(&fch.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
@@ -117,6 +123,10 @@ func (f *fetchCmd) run() error {
 		Keyring:  f.keyring,
 		Verify:   downloader.VerifyNever,
 		Getters:  getter.All(settings),
+		Username: f.username,
+		Password: f.password,
+		RepoURL:  f.repoURL,
+		Registry: (&registryFlags{plainHTTP: f.plainHTTP, username: f.username, password: f.password}).client(),
 	}
 
 	if f.verify {
@@ -138,7 +148,7 @@ func (f *fetchCmd) run() error {
 	}
 
 	if f.repoURL != "" {
-		chartURL, err := repo.FindChartInRepoURL(f.repoURL, f.chartRef, f.version, f.certFile, f.keyFile, f.caFile, getter.All(settings))
+		chartURL, err := repo.FindChartInAuthRepoURL(f.repoURL, f.username, f.password, f.chartRef, f.version, f.certFile, f.keyFile, f.caFile, getter.All(settings))
 		if err != nil {
 			return err
 		}
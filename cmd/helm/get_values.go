@@ -28,6 +28,9 @@ import (
 
 var getValuesHelp = `
 This command downloads a values file for a given release.
+
+By default, the values are printed as they were supplied. Use '-o json' or
+'-o yaml' to print them re-encoded in that format.
 `
 
 type getValuesCmd struct {
@@ -36,6 +39,7 @@ type getValuesCmd struct {
 	out       io.Writer
 	client    helm.Interface
 	version   int32
+	output    outputFormat
 }
 
 func newGetValuesCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -59,6 +63,7 @@ func newGetValuesCmd(client helm.Interface, out io.Writer) *cobra.Command {
 
 	cmd.Flags().Int32Var(&get.version, "revision", 0, "get the named release with revision")
 	cmd.Flags().BoolVarP(&get.allValues, "all", "a", false, "dump all (computed) values")
+	bindOutputFlag(cmd.Flags(), &get.output, "o")
 	return cmd
 }
 
@@ -75,6 +80,9 @@ func (g *getValuesCmd) run() error {
 		if err != nil {
 			return err
 		}
+		if g.output.structured() {
+			return g.output.write(g.out, cfg)
+		}
 		cfgStr, err := cfg.YAML()
 		if err != nil {
 			return err
@@ -83,6 +91,13 @@ func (g *getValuesCmd) run() error {
 		return nil
 	}
 
+	if g.output.structured() {
+		vals, err := chartutil.ReadValues([]byte(res.Release.Config.Raw))
+		if err != nil {
+			return err
+		}
+		return g.output.write(g.out, vals)
+	}
 	fmt.Fprintln(g.out, res.Release.Config.Raw)
 	return nil
 }
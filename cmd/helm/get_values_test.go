@@ -31,6 +31,13 @@ func TestGetValuesCmd(t *testing.T) {
 			args:     []string{"thomas-guide"},
 			expected: "name: \"value\"",
 		},
+		{
+			name:     "get values as json",
+			resp:     releaseMock(&releaseOptions{name: "thomas-guide"}),
+			args:     []string{"thomas-guide"},
+			flags:    []string{"-o", "json"},
+			expected: "^\\{\n  \"name\": \"value\"\n\\}\n$",
+		},
 		{
 			name: "get values requires release name arg",
 			err:  true,
@@ -130,23 +130,29 @@ func newRootCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		// chart commands
+		newChartCmd(out),
 		newCreateCmd(out),
 		newDependencyCmd(out),
 		newFetchCmd(out),
 		newInspectCmd(out),
 		newLintCmd(out),
+		newTemplateCmd(out),
 		newPackageCmd(out),
+		newPushCmd(out),
 		newRepoCmd(out),
 		newSearchCmd(out),
 		newServeCmd(out),
+		newValuesCmd(out),
 		newVerifyCmd(out),
 
 		// release commands
+		addFlagsTLS(newAdoptCmd(nil, out)),
 		addFlagsTLS(newDeleteCmd(nil, out)),
 		addFlagsTLS(newGetCmd(nil, out)),
 		addFlagsTLS(newHistoryCmd(nil, out)),
 		addFlagsTLS(newInstallCmd(nil, out)),
 		addFlagsTLS(newListCmd(nil, out)),
+		addFlagsTLS(newOutdatedCmd(nil, out)),
 		addFlagsTLS(newRollbackCmd(nil, out)),
 		addFlagsTLS(newStatusCmd(nil, out)),
 		addFlagsTLS(newUpgradeCmd(nil, out)),
@@ -181,10 +187,20 @@ func init() {
 func main() {
 	cmd := newRootCmd()
 	if err := cmd.Execute(); err != nil {
+		if e, ok := err.(exitError); ok {
+			os.Exit(e.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitError is an error that makes helm exit with code instead of 1, for
+// commands whose exit status is meant for scripts.
+type exitError struct {
+	error
+	code int
+}
+
 func markDeprecated(cmd *cobra.Command, notice string) *cobra.Command {
 	cmd.Deprecated = notice
 	return cmd
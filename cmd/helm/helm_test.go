@@ -224,6 +224,12 @@ func (c *fakeReleaseClient) RunReleaseTest(rlsName string, opts ...helm.ReleaseT
 	return results, errc
 }
 
+func (c *fakeReleaseClient) AdoptReleaseFromChart(rlsName string, chart *chart.Chart, ns string, opts ...helm.AdoptOption) (*rls.AdoptReleaseResponse, error) {
+	return &rls.AdoptReleaseResponse{
+		Release: c.rels[0],
+	}, c.err
+}
+
 func (c *fakeReleaseClient) Option(opt ...helm.Option) helm.Interface {
 	return c
 }
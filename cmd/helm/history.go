@@ -43,13 +43,16 @@ The historical release set is printed as a formatted table, e.g:
     2           Mon Oct 3 10:15:13 2016     SUPERSEDED      alpine-0.1.0  Upgraded successfully
     3           Mon Oct 3 10:15:13 2016     SUPERSEDED      alpine-0.1.0  Rolled back to 2
     4           Mon Oct 3 10:15:13 2016     DEPLOYED        alpine-0.1.0  Upgraded successfully
+
+Use '-o json' or '-o yaml' to print the revisions in a format meant for scripts.
 `
 
 type historyCmd struct {
-	max   int32
-	rls   string
-	out   io.Writer
-	helmc helm.Interface
+	max    int32
+	rls    string
+	output outputFormat
+	out    io.Writer
+	helmc  helm.Interface
 }
 
 func newHistoryCmd(c helm.Interface, w io.Writer) *cobra.Command {
@@ -74,6 +77,7 @@ func newHistoryCmd(c helm.Interface, w io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().Int32Var(&his.max, "max", 256, "maximum number of revision to include in history")
+	bindOutputFlag(cmd.Flags(), &his.output, "o")
 
 	return cmd
 }
@@ -83,6 +87,9 @@ func (cmd *historyCmd) run() error {
 	if err != nil {
 		return prettyError(err)
 	}
+	if cmd.output.structured() {
+		return cmd.output.write(cmd.out, toHistoryOutput(r.Releases))
+	}
 	if len(r.Releases) == 0 {
 		return nil
 	}
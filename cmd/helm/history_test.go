@@ -62,6 +62,25 @@ func TestHistoryCmd(t *testing.T) {
 			},
 			xout: "REVISION\tUPDATED                 \tSTATUS    \tCHART           \tDESCRIPTION \n3       \t(.*)\tSUPERSEDED\tfoo-0.1.0-beta.1\tRelease mock\n4       \t(.*)\tDEPLOYED  \tfoo-0.1.0-beta.1\tRelease mock\n",
 		},
+		{
+			cmds: "helm history RELEASE_NAME -o json",
+			desc: "get history as json",
+			args: []string{"-o", "json", "angry-bird"},
+			resp: []*rpb.Release{
+				mk("angry-bird", 2, rpb.Status_DEPLOYED),
+				mk("angry-bird", 1, rpb.Status_SUPERSEDED),
+			},
+			xout: `"revision": 1,\n    "updated": ".*",\n    "status": "SUPERSEDED",\n    "chart": "foo-0.1.0-beta.1",\n    "description": "Release mock"\n  },\n  \{\n    "revision": 2,`,
+		},
+		{
+			cmds: "helm history RELEASE_NAME -o yaml",
+			desc: "get history as yaml",
+			args: []string{"-o", "yaml", "angry-bird"},
+			resp: []*rpb.Release{
+				mk("angry-bird", 1, rpb.Status_DEPLOYED),
+			},
+			xout: "- chart: foo-0.1.0-beta.1\n  description: Release mock\n  revision: 1\n  status: DEPLOYED\n",
+		},
 	}
 
 	var buf bytes.Buffer
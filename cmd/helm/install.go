@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,6 +32,7 @@ import (
 	"github.com/Masterminds/sprig"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/downloader"
@@ -64,12 +66,28 @@ contained a key called 'Test', the value set in override.yaml would take precede
 
 	$ helm install -f myvalues.yaml -f override.yaml ./redis
 
+A values file can also be read from stdin with '-f -', or downloaded from a URL
+whose scheme is supported by Helm or by a downloader plugin. The '--cert-file',
+'--key-file' and '--ca-file' flags apply to these downloads too:
+
+	$ cat myvalues.yaml | helm install -f - ./redis
+	$ helm install -f https://example.com/values/prod.yaml ./redis
+
 You can specify the '--set' flag multiple times. The priority will be given to the
 last (right-most) set specified. For example, if both 'bar' and 'newbar' values are
 set for a key called 'foo', the 'newbar' value would take precedence:
 
 	$ helm install --set foo=bar --set foo=newbar ./redis
 
+To keep a value as a string, use '--set-string'. To set a value to the contents
+of a file, such as a certificate, use '--set-file'. To set a structured value,
+use '--set-json':
+
+	$ helm install --set-string image.tag=1.10 --set-file tls.cert=./tls.crt --set-json 'resources={"limits":{"cpu":"100m"}}' ./redis
+
+The '--set' flags are applied in this order: '--set', '--set-string',
+'--set-file' and '--set-json'.
+
 
 To check the generated manifests of a release without installing the chart,
 the '--debug' and '--dry-run' flags can be combined. This will still require a
@@ -100,9 +118,10 @@ charts in a repository, use 'helm search'.
 `
 
 type installCmd struct {
+	valuesOptions
+
 	name         string
 	namespace    string
-	valueFiles   valueFiles
 	chartPath    string
 	dryRun       bool
 	disableHooks bool
@@ -111,17 +130,42 @@ type installCmd struct {
 	keyring      string
 	out          io.Writer
 	client       helm.Interface
-	values       []string
 	nameTemplate string
 	version      string
 	timeout      int64
 	wait         bool
 	repoURL      string
+	username     string
+	password     string
+	plainHTTP    bool
 	devel        bool
+}
+
+// valuesOptions are the flags of the commands that build values for a chart.
+type valuesOptions struct {
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	jsonValues   []string
 
 	certFile string
 	keyFile  string
 	caFile   string
+
+	secretKeyring string
+}
+
+func (v *valuesOptions) addFlags(f *pflag.FlagSet) {
+	f.VarP(&v.valueFiles, "values", "f", "specify values in a YAML file (can specify multiple)")
+	f.StringArrayVar(&v.values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&v.stringValues, "set-string", []string{}, "set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&v.fileValues, "set-file", []string{}, "set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	f.StringArrayVar(&v.jsonValues, "set-json", []string{}, "set JSON values on the command line (can specify multiple or separate values with commas: key1=jsonval1,key2=jsonval2)")
+	f.StringVar(&v.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
+	f.StringVar(&v.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
+	f.StringVar(&v.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.StringVar(&v.secretKeyring, "secret-keyring", defaultSecretKeyring(), "keyring containing the private keys that decrypt encrypted values files")
 }
 
 type valueFiles []string
@@ -163,7 +207,7 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 				inst.version = ">0.0.0-a"
 			}
 
-			cp, err := locateChartPath(inst.repoURL, args[0], inst.version, inst.verify, inst.keyring,
+			cp, err := locateChartPath(inst.repoURL, inst.username, inst.password, inst.plainHTTP, args[0], inst.version, inst.verify, inst.keyring,
 				inst.certFile, inst.keyFile, inst.caFile)
 			if err != nil {
 				return err
@@ -175,13 +219,12 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.VarP(&inst.valueFiles, "values", "f", "specify values in a YAML file (can specify multiple)")
+	inst.valuesOptions.addFlags(f)
 	f.StringVarP(&inst.name, "name", "n", "", "release name. If unspecified, it will autogenerate one for you")
 	f.StringVar(&inst.namespace, "namespace", "", "namespace to install the release into")
 	f.BoolVar(&inst.dryRun, "dry-run", false, "simulate an install")
 	f.BoolVar(&inst.disableHooks, "no-hooks", false, "prevent hooks from running during install")
 	f.BoolVar(&inst.replace, "replace", false, "re-use the given name, even if that name is already used. This is unsafe in production")
-	f.StringArrayVar(&inst.values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.StringVar(&inst.nameTemplate, "name-template", "", "specify template used to name the release")
 	f.BoolVar(&inst.verify, "verify", false, "verify the package before installing it")
 	f.StringVar(&inst.keyring, "keyring", defaultKeyring(), "location of public keys used for verification")
@@ -189,9 +232,9 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.Int64Var(&inst.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
 	f.BoolVar(&inst.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&inst.repoURL, "repo", "", "chart repository url where to locate the requested chart")
-	f.StringVar(&inst.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
-	f.StringVar(&inst.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
-	f.StringVar(&inst.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.StringVar(&inst.username, "username", "", "chart repository username where to locate the requested chart")
+	f.StringVar(&inst.password, "password", "", "chart repository password where to locate the requested chart")
+	f.BoolVar(&inst.plainHTTP, "plain-http", false, "access the OCI registry of an oci:// chart reference over HTTP instead of HTTPS")
 	f.BoolVar(&inst.devel, "devel", false, "use development versions, too. Equivalent to version '>0.0.0-a'. If --version is set, this is ignored.")
 
 	return cmd
@@ -225,6 +268,10 @@ func (i *installCmd) run() error {
 		return prettyError(err)
 	}
 
+	if chartutil.IsLibraryChart(chartRequested) {
+		return fmt.Errorf("%s is a library chart, which cannot be installed", chartRequested.Metadata.Name)
+	}
+
 	if req, err := chartutil.LoadRequirements(chartRequested); err == nil {
 		// If checkDependencies returns an error, we have unfullfilled dependencies.
 		// As of Helm 2.4.0, this is treated as a stopping condition:
@@ -302,34 +349,131 @@ func mergeValues(dest map[string]interface{}, src map[string]interface{}) map[st
 	return dest
 }
 
-func (i *installCmd) vals() ([]byte, error) {
+// vals merges values from files specified via -f/--values and directly via
+// --set, --set-string, --set-file and --set-json, in that order, marshaling
+// them to YAML
+//
+// Values files may be read from stdin or from URLs, see readValuesFile.
+func (v *valuesOptions) vals() ([]byte, error) {
 	base := map[string]interface{}{}
+	dec := &valuesDecrypter{keyring: v.secretKeyring}
 
 	// User specified a values files via -f/--values
-	for _, filePath := range i.valueFiles {
+	for _, filePath := range v.valueFiles {
 		currentMap := map[string]interface{}{}
-		bytes, err := ioutil.ReadFile(filePath)
+		bytes, err := readValuesFile(filePath, v.certFile, v.keyFile, v.caFile)
 		if err != nil {
 			return []byte{}, err
 		}
 
+		if bytes, err = dec.decryptFile(bytes); err != nil {
+			return []byte{}, fmt.Errorf("failed to decrypt %s: %s", filePath, err)
+		}
 		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
 			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
 		}
+		if _, err := dec.decryptValues(currentMap); err != nil {
+			return []byte{}, fmt.Errorf("failed to decrypt %s: %s", filePath, err)
+		}
 		// Merge with the previous map
 		base = mergeValues(base, currentMap)
 	}
 
 	// User specified a value via --set
-	for _, value := range i.values {
+	for _, value := range v.values {
 		if err := strvals.ParseInto(value, base); err != nil {
 			return []byte{}, fmt.Errorf("failed parsing --set data: %s", err)
 		}
 	}
 
+	// User specified a value via --set-string
+	for _, value := range v.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
+	// User specified a value via --set-file
+	for _, value := range v.fileValues {
+		if err := strvals.ParseIntoFile(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-file data: %s", err)
+		}
+	}
+
+	// User specified a value via --set-json
+	for _, value := range v.jsonValues {
+		if err := strvals.ParseIntoJSON(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-json data: %s", err)
+		}
+	}
+
 	return yaml.Marshal(base)
 }
 
+// stdin is where a values file named "-" is read from.
+var stdin io.Reader = os.Stdin
+
+// readValuesFile reads a values file from stdin if filePath is "-", from a URL
+// if filePath has a scheme served by a getter, like http or the scheme of a
+// downloader plugin, and from the local filesystem otherwise.
+//
+// A URL inside a configured chart repository is downloaded with the settings
+// of that repository. The certificate files, if given, take precedence.
+func readValuesFile(filePath, certFile, keyFile, caFile string) ([]byte, error) {
+	if strings.TrimSpace(filePath) == "-" {
+		return ioutil.ReadAll(stdin)
+	}
+
+	// Local files win, so that a relative path containing a colon is not
+	// mistaken for a URL. Single letter schemes are Windows drive letters.
+	u, err := url.Parse(filePath)
+	if err != nil || len(u.Scheme) < 2 {
+		return ioutil.ReadFile(filePath)
+	}
+	if _, err := os.Stat(filePath); err == nil {
+		return ioutil.ReadFile(filePath)
+	}
+
+	newGetter, err := getter.All(settings).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read values file %s: %s", filePath, err)
+	}
+	g, err := newGetter(valuesGetterOptions(filePath, certFile, keyFile, caFile))
+	if err != nil {
+		return nil, err
+	}
+	data, err := g.Get(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read values file %s: %s", filePath, err)
+	}
+	return data.Bytes(), nil
+}
+
+// valuesGetterOptions returns the getter options to download the values file at
+// href with.
+func valuesGetterOptions(href, certFile, keyFile, caFile string) getter.Options {
+	o := getter.Options{}
+	if f, err := repo.LoadRepositoriesFile(settings.Home.RepositoryFile()); err == nil {
+		for _, e := range f.Repositories {
+			if strings.HasPrefix(href, strings.TrimSuffix(e.URL, "/")+"/") {
+				o = e.GetterOptions()
+				break
+			}
+		}
+	}
+	o.URL = href
+	if certFile != "" {
+		o.CertFile = certFile
+	}
+	if keyFile != "" {
+		o.KeyFile = keyFile
+	}
+	if caFile != "" {
+		o.CAFile = caFile
+	}
+	return o
+}
+
 // printRelease prints info about a release if the Debug is true.
 func (i *installCmd) printRelease(rel *release.Release) {
 	if rel == nil {
@@ -353,7 +497,10 @@ func (i *installCmd) printRelease(rel *release.Release) {
 // - URL
 //
 // If 'verify' is true, this will attempt to also verify the chart.
-func locateChartPath(repoURL, name, version string, verify bool, keyring,
+//
+// Charts in OCI registries are pulled with the username and password, over
+// plain HTTP if plainHTTP is set.
+func locateChartPath(repoURL, username, password string, plainHTTP bool, name, version string, verify bool, keyring,
 	certFile, keyFile, caFile string) (string, error) {
 	name = strings.TrimSpace(name)
 	version = strings.TrimSpace(version)
@@ -386,12 +533,16 @@ func locateChartPath(repoURL, name, version string, verify bool, keyring,
 		Out:      os.Stdout,
 		Keyring:  keyring,
 		Getters:  getter.All(settings),
+		Username: username,
+		Password: password,
+		RepoURL:  repoURL,
+		Registry: (&registryFlags{plainHTTP: plainHTTP, username: username, password: password}).client(),
 	}
 	if verify {
 		dl.Verify = downloader.VerifyAlways
 	}
 	if repoURL != "" {
-		chartURL, err := repo.FindChartInRepoURL(repoURL, name, version,
+		chartURL, err := repo.FindChartInAuthRepoURL(repoURL, username, password, name, version,
 			certFile, keyFile, caFile, getter.All(settings))
 		if err != nil {
 			return "", err
@@ -17,13 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/repo"
 )
 
 func TestInstall(t *testing.T) {
@@ -138,6 +146,12 @@ func TestInstall(t *testing.T) {
 			args: []string{"testdata/testcharts/chart-missing-deps"},
 			err:  true,
 		},
+		// Install, library chart
+		{
+			name: "install library chart",
+			args: []string{"testdata/testcharts/lib-chart"},
+			err:  true,
+		},
 		// Install, chart with bad requirements.yaml in /charts
 		{
 			name: "install chart with bad requirements.yaml",
@@ -280,3 +294,138 @@ func TestMergeValues(t *testing.T) {
 		t.Errorf("Expected a map with different keys to merge properly with another map. Expected: %v, got %v", expectedMap, testMap)
 	}
 }
+
+func TestVals(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-vals-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert := filepath.Join(dir, "tls.crt")
+	if err := ioutil.WriteFile(cert, []byte("certdata"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &valuesOptions{
+		values:       []string{"foo=1,version=0123"},
+		stringValues: []string{"foo=2,version=0123,image.tag=1.10"},
+		fileValues:   []string{"tls.cert=" + cert},
+		jsonValues:   []string{`foo=3,resources={"limits": {"cpu": "100m"}}`},
+	}
+	b, err := opts.vals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `foo: 3
+image:
+  tag: "1.10"
+resources:
+  limits:
+    cpu: 100m
+tls:
+  cert: certdata
+version: "0123"
+`
+	if string(b) != expect {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expect, b)
+	}
+
+	opts = &valuesOptions{fileValues: []string{"tls.cert=" + filepath.Join(dir, "missing")}}
+	if _, err := opts.vals(); err == nil {
+		t.Error("Expected an error for a missing --set-file file")
+	}
+	opts = &valuesOptions{jsonValues: []string{"foo=bar"}}
+	if _, err := opts.vals(); err == nil {
+		t.Error("Expected an error for an invalid --set-json value")
+	}
+}
+
+func TestReadValuesFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/values.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "from: url\n")
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	local := filepath.Join(dir, "values.yaml")
+	if err := ioutil.WriteFile(local, []byte("from: file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdin := stdin
+	stdin = strings.NewReader("from: stdin\n")
+	defer func() { stdin = oldStdin }()
+
+	tests := []struct {
+		name   string
+		path   string
+		expect string
+		err    bool
+	}{
+		{"stdin", "-", "from: stdin\n", false},
+		{"local file", local, "from: file\n", false},
+		{"url", srv.URL + "/values.yaml", "from: url\n", false},
+		{"missing url", srv.URL + "/missing.yaml", "", true},
+		{"unsupported scheme", "nope://bucket/values.yaml", "", true},
+		{"missing file", filepath.Join(dir, "missing.yaml"), "", true},
+	}
+
+	for _, tt := range tests {
+		b, err := readValuesFile(tt.path, "", "", "")
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+		}
+		if string(b) != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, b)
+		}
+	}
+}
+
+func TestReadValuesFile_RepositorySettings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "from: repo\n")
+	}))
+	defer srv.Close()
+
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := settings.Home
+	settings.Home = hh
+	defer func() {
+		settings.Home = old
+		os.RemoveAll(hh.String())
+	}()
+
+	rf := repo.NewRepoFile()
+	rf.Add(&repo.Entry{Name: "keyed", URL: srv.URL + "/charts", Headers: map[string]string{"X-Api-Key": "secret"}})
+	if err := rf.WriteFile(hh.RepositoryFile(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := readValuesFile(srv.URL+"/charts/values.yaml", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "from: repo\n" {
+		t.Errorf("Expected the values of the repository, got %q", b)
+	}
+	if _, err := readValuesFile(srv.URL+"/other/values.yaml", "", "", ""); err == nil {
+		t.Error("Expected a URL outside the repository to be fetched without its headers")
+	}
+}
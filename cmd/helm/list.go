@@ -51,6 +51,9 @@ Only items that match the filter will be returned.
 If no results are found, 'helm list' will exit 0, but with no output (or in
 the case of no '-q' flag, only headers).
 
+Use '--output json' or '--output yaml' to print the releases in a format meant
+for scripts. The short flag '-o' is taken by '--offset'.
+
 By default, up to 256 items may be returned. To limit this, use the '--max' flag.
 Setting '--max' to 0 will not return all results. Rather, it will return the
 server's default, which may be much higher than 256. Pairing the '--max'
@@ -72,6 +75,7 @@ type listCmd struct {
 	failed     bool
 	namespace  string
 	superseded bool
+	output     outputFormat
 	client     helm.Interface
 }
 
@@ -110,6 +114,7 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&list.deployed, "deployed", false, "show deployed releases. If no other is specified, this will be automatically enabled")
 	f.BoolVar(&list.failed, "failed", false, "show failed releases")
 	f.StringVar(&list.namespace, "namespace", "", "show releases within a specific namespace")
+	bindOutputFlag(f, &list.output, "")
 
 	// TODO: Do we want this as a feature of 'helm list'?
 	//f.BoolVar(&list.superseded, "history", true, "show historical releases")
@@ -144,6 +149,10 @@ func (l *listCmd) run() error {
 		return prettyError(err)
 	}
 
+	if l.output.structured() {
+		return l.output.write(l.out, toReleaseListOutput(res.Next, res.Releases))
+	}
+
 	if len(res.Releases) == 0 {
 		return nil
 	}
@@ -97,6 +97,32 @@ func TestListCmd(t *testing.T) {
 			// See note on previous test.
 			expected: "thomas-guide",
 		},
+		{
+			name: "list as json",
+			args: []string{"--output", "json"},
+			resp: []*release.Release{
+				releaseMock(&releaseOptions{name: "atlas"}),
+			},
+			expected: `"releases": \[\n    \{\n      "name": "atlas",\n      "revision": 1,\n      "updated": ".*",\n      "status": "DEPLOYED",\n      "chart": "foo-0.1.0-beta.1",\n      "namespace": "default"\n    \}\n  \]`,
+		},
+		{
+			name:     "list as json, no releases",
+			args:     []string{"--output", "json"},
+			expected: `^\{\n  "releases": \[\]\n\}\n$`,
+		},
+		{
+			name: "list as yaml",
+			args: []string{"--output", "yaml"},
+			resp: []*release.Release{
+				releaseMock(&releaseOptions{name: "atlas"}),
+			},
+			expected: "releases:\n- chart: foo-0.1.0-beta.1\n  name: atlas\n  namespace: default\n  revision: 1\n",
+		},
+		{
+			name: "list with an unknown output format",
+			args: []string{"--output", "xml"},
+			err:  true,
+		},
 	}
 
 	var buf bytes.Buffer
@@ -105,8 +131,10 @@ func TestListCmd(t *testing.T) {
 			rels: tt.resp,
 		}
 		cmd := newListCmd(c, &buf)
-		cmd.ParseFlags(tt.args)
-		err := cmd.RunE(cmd, tt.args)
+		err := cmd.ParseFlags(tt.args)
+		if err == nil {
+			err = cmd.RunE(cmd, tt.args)
+		}
 		if (err != nil) != tt.err {
 			t.Errorf("%q. expected error: %v, got %v", tt.name, tt.err, err)
 		}
@@ -0,0 +1,260 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Masterminds/semver"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/repo"
+)
+
+const outdatedDesc = `
+This command lists the deployed releases whose chart has a newer version in
+the configured repositories, or whose chart is deprecated.
+
+The charts of the releases are looked up by name in the repository indexes
+that were last fetched with 'helm repo update'. If several repositories have a
+chart of the same name, the one with the newest version is reported.
+
+Use '--version' to only consider versions that satisfy a semver constraint,
+for example '--version "~1.2"' to stay within a minor version.
+
+Use '--exit-code' in scripts to exit with status 2 when any release is
+outdated or deprecated. Other failures exit with status 1.
+`
+
+// outdatedExitCode is the exit code of 'helm outdated --exit-code' when it
+// finds outdated releases.
+const outdatedExitCode = 2
+
+type outdatedCmd struct {
+	out      io.Writer
+	client   helm.Interface
+	helmhome helmpath.Home
+
+	namespace string
+	version   string
+	devel     bool
+	all       bool
+	exitCode  bool
+	output    outputFormat
+}
+
+// outdatedRelease compares the chart of a release to the latest version in a
+// repository.
+type outdatedRelease struct {
+	release *release.Release
+	// repo is the name of the repository the latest chart was found in.
+	repo   string
+	latest *repo.ChartVersion
+}
+
+func newOutdatedCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	o := &outdatedCmd{out: out, client: client}
+
+	cmd := &cobra.Command{
+		Use:     "outdated [flags]",
+		Short:   "list releases whose chart has a newer version",
+		Long:    outdatedDesc,
+		PreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.client = ensureHelmClient(o.client)
+			o.helmhome = settings.Home
+			return o.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&o.namespace, "namespace", "", "show releases within a specific namespace")
+	f.StringVar(&o.version, "version", "", "only consider chart versions that satisfy this semver constraint")
+	f.BoolVar(&o.devel, "devel", false, "use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
+	f.BoolVar(&o.all, "all", false, "show all releases whose chart was found, not just the outdated ones")
+	f.BoolVar(&o.exitCode, "exit-code", false, fmt.Sprintf("exit with status %d if any release is outdated or deprecated", outdatedExitCode))
+	bindOutputFlag(f, &o.output, "o")
+
+	return cmd
+}
+
+func (o *outdatedCmd) run() error {
+	version := o.version
+	if version == "" && o.devel {
+		version = ">0.0.0-0"
+	}
+	if version != "" {
+		if _, err := semver.NewConstraint(version); err != nil {
+			return fmt.Errorf("invalid version constraint %q: %s", version, err)
+		}
+	}
+
+	rels, err := o.listReleases()
+	if err != nil {
+		return prettyError(err)
+	}
+	indexes, err := o.loadIndexes()
+	if err != nil {
+		return err
+	}
+
+	res := []*outdatedRelease{}
+	stale := 0
+	for _, r := range rels {
+		if r.Chart == nil || r.Chart.Metadata == nil {
+			continue
+		}
+		od := findLatest(r, indexes, version)
+		if od == nil {
+			continue
+		}
+		if od.outdated() || od.latest.Deprecated {
+			stale++
+		} else if !o.all {
+			continue
+		}
+		res = append(res, od)
+	}
+
+	if o.output.structured() {
+		err = o.output.write(o.out, toOutdatedOutput(res))
+	} else {
+		fmt.Fprintln(o.out, formatOutdated(res))
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.exitCode && stale > 0 {
+		return exitError{
+			error: fmt.Errorf("%d release(s) are outdated or deprecated", stale),
+			code:  outdatedExitCode,
+		}
+	}
+	return nil
+}
+
+// listReleases lists all deployed releases, a page at a time.
+func (o *outdatedCmd) listReleases() ([]*release.Release, error) {
+	var rels []*release.Release
+	offset := ""
+	for {
+		res, err := o.client.ListReleases(
+			helm.ReleaseListLimit(256),
+			helm.ReleaseListOffset(offset),
+			helm.ReleaseListSort(int32(services.ListSort_NAME)),
+			helm.ReleaseListStatuses([]release.Status_Code{release.Status_DEPLOYED}),
+			helm.ReleaseListNamespace(o.namespace),
+		)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, res.Releases...)
+		if res.Next == "" {
+			return rels, nil
+		}
+		offset = res.Next
+	}
+}
+
+// repoIndex is the cached index of a configured repository.
+type repoIndex struct {
+	name  string
+	index *repo.IndexFile
+}
+
+// loadIndexes loads the cached indexes of the configured repositories, in the
+// order of the repositories file.
+func (o *outdatedCmd) loadIndexes() ([]repoIndex, error) {
+	rf, err := repo.LoadRepositoriesFile(o.helmhome.RepositoryFile())
+	if err != nil {
+		return nil, err
+	}
+	indexes := []repoIndex{}
+	for _, re := range rf.Repositories {
+		ind, err := repo.LoadCachedIndexFile(o.helmhome.CacheIndex(re.Name))
+		if err != nil {
+			fmt.Fprintf(o.out, "WARNING: Repo %q is corrupt or missing. Try 'helm repo update'.\n", re.Name)
+			continue
+		}
+		indexes = append(indexes, repoIndex{name: re.Name, index: ind})
+	}
+	return indexes, nil
+}
+
+// findLatest finds the newest version of the chart of r that satisfies the
+// version constraint in any of the indexes. It returns nil if no repository
+// has the chart.
+func findLatest(r *release.Release, indexes []repoIndex, version string) *outdatedRelease {
+	var (
+		found  *outdatedRelease
+		newest *semver.Version
+	)
+	for _, ri := range indexes {
+		cv, err := ri.index.Get(r.Chart.Metadata.Name, version)
+		if err != nil {
+			continue
+		}
+		v, err := semver.NewVersion(cv.Version)
+		if err != nil {
+			continue
+		}
+		if found == nil || v.GreaterThan(newest) {
+			found = &outdatedRelease{release: r, repo: ri.name, latest: cv}
+			newest = v
+		}
+	}
+	return found
+}
+
+// outdated returns whether the latest chart is newer than the installed one.
+func (od *outdatedRelease) outdated() bool {
+	installed := od.release.Chart.Metadata.Version
+	iv, err := semver.NewVersion(installed)
+	if err != nil {
+		return installed != od.latest.Version
+	}
+	lv, err := semver.NewVersion(od.latest.Version)
+	if err != nil {
+		return installed != od.latest.Version
+	}
+	return lv.GreaterThan(iv)
+}
+
+func formatOutdated(res []*outdatedRelease) string {
+	if len(res) == 0 {
+		return "No outdated releases found"
+	}
+	table := uitable.New()
+	table.MaxColWidth = 60
+	table.AddRow("NAME", "NAMESPACE", "CHART", "INSTALLED", "LATEST", "APP VERSION", "DEPRECATED")
+	for _, od := range res {
+		r := od.release
+		app := r.Chart.Metadata.AppVersion
+		if latest := od.latest.AppVersion; latest != app {
+			app = fmt.Sprintf("%s -> %s", app, latest)
+		}
+		table.AddRow(r.Name, r.Namespace, od.repo+"/"+r.Chart.Metadata.Name, r.Chart.Metadata.Version, od.latest.Version, app, od.latest.Deprecated)
+	}
+	return table.String()
+}
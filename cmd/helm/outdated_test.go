@@ -0,0 +1,110 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestOutdatedCmd(t *testing.T) {
+	chartMock := func(name, version string) *chart.Chart {
+		return &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version}}
+	}
+	rels := []*release.Release{
+		releaseMock(&releaseOptions{name: "old-alpine", chart: chartMock("alpine", "0.1.0")}),
+		releaseMock(&releaseOptions{name: "new-alpine", chart: chartMock("alpine", "0.2.0")}),
+		releaseMock(&releaseOptions{name: "mariadb", chart: chartMock("mariadb", "0.3.0")}),
+		releaseMock(&releaseOptions{name: "unknown", chart: chartMock("foo", "0.1.0")}),
+	}
+
+	tests := []struct {
+		name     string
+		flags    []string
+		expected string
+		code     int
+		err      bool
+	}{
+		{
+			name:     "outdated releases",
+			expected: `^NAME\s+\tNAMESPACE\tCHART\s+\tINSTALLED\tLATEST\tAPP VERSION\tDEPRECATED\nold-alpine\tdefault\s+\ttesting/alpine\t0.1.0\s+\t0.2.0\s+\t\s+\tfalse\s+\n$`,
+		},
+		{
+			name:     "all releases",
+			flags:    []string{"--all"},
+			expected: `old-alpine.*\nnew-alpine.*\nmariadb.*\n$`,
+		},
+		{
+			name:     "version constraint",
+			flags:    []string{"--version", "<0.2.0"},
+			expected: "No outdated releases found",
+		},
+		{
+			name:     "invalid version constraint",
+			flags:    []string{"--version", "banana"},
+			expected: "^$",
+			err:      true,
+		},
+		{
+			name:     "json",
+			flags:    []string{"-o", "json"},
+			expected: `^\[\n  \{\n    "name": "old-alpine",\n    "namespace": "default",\n    "chart": "alpine",\n    "repository": "testing",\n    "installedVersion": "0.1.0",\n    "latestVersion": "0.2.0",\n    "outdated": true,\n    "deprecated": false\n  \}\n\]\n$`,
+		},
+		{
+			name:     "exit code",
+			flags:    []string{"--exit-code"},
+			expected: "old-alpine",
+			code:     outdatedExitCode,
+			err:      true,
+		},
+		{
+			name:     "exit code without outdated releases",
+			flags:    []string{"--exit-code", "--version", "<0.2.0"},
+			expected: "No outdated releases found",
+		},
+	}
+
+	oldhome := settings.Home
+	settings.Home = "testdata/helmhome"
+	defer func() { settings.Home = oldhome }()
+
+	var buf bytes.Buffer
+	for _, tt := range tests {
+		cmd := newOutdatedCmd(&fakeReleaseClient{rels: rels}, &buf)
+		if err := cmd.ParseFlags(tt.flags); err != nil {
+			t.Fatal(err)
+		}
+		err := cmd.RunE(cmd, nil)
+		if (err != nil) != tt.err {
+			t.Errorf("%q. expected error: %v, got %v", tt.name, tt.err, err)
+		}
+		if tt.code != 0 {
+			if e, ok := err.(exitError); !ok || e.code != tt.code {
+				t.Errorf("%q. expected exit code %d, got %v", tt.name, tt.code, err)
+			}
+		}
+		re := regexp.MustCompile(tt.expected)
+		if !re.Match(buf.Bytes()) {
+			t.Errorf("%q. expected\n%q\ngot\n%q", tt.name, tt.expected, buf.String())
+		}
+		buf.Reset()
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/cmd/helm/search"
+	"k8s.io/helm/pkg/plugin"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	pb "k8s.io/helm/pkg/proto/hapi/version"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// outputFormat is the format of the output of a command.
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+const outputFlagUsage = "print the output in the specified format. Allowed values: table, json, yaml"
+
+// String implements pflag.Value.
+func (o *outputFormat) String() string {
+	return string(*o)
+}
+
+// Set implements pflag.Value, rejecting unknown formats.
+func (o *outputFormat) Set(s string) error {
+	switch f := outputFormat(s); f {
+	case outputTable, outputJSON, outputYAML:
+		*o = f
+		return nil
+	}
+	return fmt.Errorf("invalid format %q, must be one of: table, json, yaml", s)
+}
+
+// Type implements pflag.Value.
+func (o *outputFormat) Type() string {
+	return "format"
+}
+
+// bindOutputFlag adds the --output flag to f, with an optional shorthand.
+func bindOutputFlag(f *pflag.FlagSet, o *outputFormat, shorthand string) {
+	*o = outputTable
+	f.VarP(o, "output", shorthand, outputFlagUsage)
+}
+
+// structured returns whether the format is machine readable.
+func (o outputFormat) structured() bool {
+	return o == outputJSON || o == outputYAML
+}
+
+// write encodes v to out in the format, which must be structured.
+//
+// Both formats share the field names of the JSON encoding, so that a YAML
+// document has the same schema as its JSON counterpart.
+func (o outputFormat) write(out io.Writer, v interface{}) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch o {
+	case outputJSON:
+		b, err = json.MarshalIndent(v, "", "  ")
+		b = append(b, '\n')
+	case outputYAML:
+		b, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("format %q cannot encode data", o)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// The types below define the schemas of the json and yaml outputs. They are
+// part of the interface of the helm CLI: fields may be added, but must never be
+// renamed or removed. Times are formatted as RFC 3339 strings.
+
+// releaseListOutput is the output of 'helm list'.
+type releaseListOutput struct {
+	// Next is the name of the next release when more are available.
+	Next     string                 `json:"next,omitempty"`
+	Releases []releaseElementOutput `json:"releases"`
+}
+
+// releaseElementOutput describes a release in 'helm list'.
+type releaseElementOutput struct {
+	Name       string `json:"name"`
+	Revision   int32  `json:"revision"`
+	Updated    string `json:"updated"`
+	Status     string `json:"status"`
+	Chart      string `json:"chart"`
+	AppVersion string `json:"appVersion,omitempty"`
+	Namespace  string `json:"namespace"`
+}
+
+// releaseStatusOutput is the output of 'helm status'.
+type releaseStatusOutput struct {
+	Name         string              `json:"name"`
+	Namespace    string              `json:"namespace"`
+	LastDeployed string              `json:"lastDeployed,omitempty"`
+	Status       string              `json:"status"`
+	Resources    string              `json:"resources,omitempty"`
+	Notes        string              `json:"notes,omitempty"`
+	TestSuite    *testSuiteRunOutput `json:"lastTestSuiteRun,omitempty"`
+}
+
+// testSuiteRunOutput describes the last run of 'helm test' in 'helm status'.
+type testSuiteRunOutput struct {
+	StartedAt   string          `json:"startedAt"`
+	CompletedAt string          `json:"completedAt"`
+	Results     []testRunOutput `json:"results"`
+}
+
+// testRunOutput describes the run of a single test in 'helm status'.
+type testRunOutput struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Info        string `json:"info,omitempty"`
+	StartedAt   string `json:"startedAt"`
+	CompletedAt string `json:"completedAt"`
+}
+
+// releaseRevisionOutput describes a revision in 'helm history'.
+type releaseRevisionOutput struct {
+	Revision    int32  `json:"revision"`
+	Updated     string `json:"updated"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	Description string `json:"description"`
+}
+
+// searchResultOutput describes a chart version in 'helm search'.
+type searchResultOutput struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	URLs        []string `json:"urls,omitempty"`
+	Digest      string   `json:"digest,omitempty"`
+}
+
+// outdatedReleaseOutput describes a release in 'helm outdated'.
+type outdatedReleaseOutput struct {
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace"`
+	Chart               string `json:"chart"`
+	Repository          string `json:"repository"`
+	InstalledVersion    string `json:"installedVersion"`
+	LatestVersion       string `json:"latestVersion"`
+	InstalledAppVersion string `json:"installedAppVersion,omitempty"`
+	LatestAppVersion    string `json:"latestAppVersion,omitempty"`
+	Outdated            bool   `json:"outdated"`
+	Deprecated          bool   `json:"deprecated"`
+}
+
+// repositoryOutput describes a repository in 'helm repo list'.
+type repositoryOutput struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// pluginOutput describes a plugin in 'helm plugin list'.
+type pluginOutput struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// versionOutput is the output of 'helm version'. A side that was not asked
+// for is omitted.
+type versionOutput struct {
+	Client *versionInfoOutput `json:"client,omitempty"`
+	Server *versionInfoOutput `json:"server,omitempty"`
+}
+
+// versionInfoOutput describes the version of a Helm binary.
+type versionInfoOutput struct {
+	SemVer       string `json:"semVer"`
+	GitCommit    string `json:"gitCommit"`
+	GitTreeState string `json:"gitTreeState"`
+}
+
+func formatTime(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return timeconv.Format(ts, time.RFC3339)
+}
+
+func toReleaseListOutput(next string, rels []*release.Release) *releaseListOutput {
+	out := &releaseListOutput{Next: next, Releases: []releaseElementOutput{}}
+	for _, r := range rels {
+		e := releaseElementOutput{
+			Name:      r.Name,
+			Revision:  r.Version,
+			Updated:   formatTime(r.Info.LastDeployed),
+			Status:    r.Info.Status.Code.String(),
+			Chart:     formatChartname(r.Chart),
+			Namespace: r.Namespace,
+		}
+		if r.Chart != nil && r.Chart.Metadata != nil {
+			e.AppVersion = r.Chart.Metadata.AppVersion
+		}
+		out.Releases = append(out.Releases, e)
+	}
+	return out
+}
+
+func toReleaseStatusOutput(res *services.GetReleaseStatusResponse) *releaseStatusOutput {
+	out := &releaseStatusOutput{
+		Name:         res.Name,
+		Namespace:    res.Namespace,
+		LastDeployed: formatTime(res.Info.LastDeployed),
+		Status:       res.Info.Status.Code.String(),
+		Resources:    res.Info.Status.Resources,
+		Notes:        res.Info.Status.Notes,
+	}
+	if run := res.Info.Status.LastTestSuiteRun; run != nil {
+		out.TestSuite = &testSuiteRunOutput{
+			StartedAt:   formatTime(run.StartedAt),
+			CompletedAt: formatTime(run.CompletedAt),
+			Results:     []testRunOutput{},
+		}
+		for _, r := range run.Results {
+			out.TestSuite.Results = append(out.TestSuite.Results, testRunOutput{
+				Name:        r.Name,
+				Status:      r.Status.String(),
+				Info:        r.Info,
+				StartedAt:   formatTime(r.StartedAt),
+				CompletedAt: formatTime(r.CompletedAt),
+			})
+		}
+	}
+	return out
+}
+
+// toHistoryOutput lists revisions from the oldest to the newest, like the
+// table does.
+func toHistoryOutput(rls []*release.Release) []releaseRevisionOutput {
+	out := []releaseRevisionOutput{}
+	for i := len(rls) - 1; i >= 0; i-- {
+		r := rls[i]
+		out = append(out, releaseRevisionOutput{
+			Revision:    r.Version,
+			Updated:     formatTime(r.Info.LastDeployed),
+			Status:      r.Info.Status.Code.String(),
+			Chart:       formatChartname(r.Chart),
+			Description: r.Info.Description,
+		})
+	}
+	return out
+}
+
+func toSearchOutput(res []*search.Result) []searchResultOutput {
+	out := []searchResultOutput{}
+	for _, r := range res {
+		out = append(out, searchResultOutput{
+			Name:        r.Name,
+			Version:     r.Chart.Version,
+			AppVersion:  r.Chart.AppVersion,
+			Description: r.Chart.Description,
+			Deprecated:  r.Chart.Deprecated,
+			URLs:        r.Chart.URLs,
+			Digest:      r.Chart.Digest,
+		})
+	}
+	return out
+}
+
+func toOutdatedOutput(res []*outdatedRelease) []outdatedReleaseOutput {
+	out := []outdatedReleaseOutput{}
+	for _, od := range res {
+		r := od.release
+		out = append(out, outdatedReleaseOutput{
+			Name:                r.Name,
+			Namespace:           r.Namespace,
+			Chart:               r.Chart.Metadata.Name,
+			Repository:          od.repo,
+			InstalledVersion:    r.Chart.Metadata.Version,
+			LatestVersion:       od.latest.Version,
+			InstalledAppVersion: r.Chart.Metadata.AppVersion,
+			LatestAppVersion:    od.latest.AppVersion,
+			Outdated:            od.outdated(),
+			Deprecated:          od.latest.Deprecated,
+		})
+	}
+	return out
+}
+
+func toRepositoriesOutput(entries []*repo.Entry) []repositoryOutput {
+	out := []repositoryOutput{}
+	for _, e := range entries {
+		out = append(out, repositoryOutput{Name: e.Name, URL: e.URL})
+	}
+	return out
+}
+
+func toPluginsOutput(plugins []*plugin.Plugin) []pluginOutput {
+	out := []pluginOutput{}
+	for _, p := range plugins {
+		out = append(out, pluginOutput{
+			Name:        p.Metadata.Name,
+			Version:     p.Metadata.Version,
+			Description: p.Metadata.Description,
+		})
+	}
+	return out
+}
+
+func toVersionInfoOutput(v *pb.Version) *versionInfoOutput {
+	return &versionInfoOutput{
+		SemVer:       v.SemVer,
+		GitCommit:    v.GitCommit,
+		GitTreeState: v.GitTreeState,
+	}
+}
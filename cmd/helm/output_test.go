@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOutputFormatSet(t *testing.T) {
+	tests := []struct {
+		in  string
+		err bool
+	}{
+		{"table", false},
+		{"json", false},
+		{"yaml", false},
+		{"JSON", true},
+		{"xml", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		o := outputTable
+		err := o.Set(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error %v, got %v", tt.in, tt.err, err)
+		}
+		if err == nil && string(o) != tt.in {
+			t.Errorf("%q: expected format to be set, got %q", tt.in, o)
+		}
+	}
+}
+
+func TestOutputFormatWrite(t *testing.T) {
+	v := []repositoryOutput{{Name: "stable", URL: "https://example.com/charts"}}
+
+	tests := []struct {
+		format   outputFormat
+		expected string
+		err      bool
+	}{
+		{outputJSON, "[\n  {\n    \"name\": \"stable\",\n    \"url\": \"https://example.com/charts\"\n  }\n]\n", false},
+		{outputYAML, "- name: stable\n  url: https://example.com/charts\n", false},
+		{outputTable, "", true},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		err := tt.format.write(&buf, v)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %v, got %v", tt.format, tt.err, err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.format, tt.expected, buf.String())
+		}
+	}
+}
@@ -203,24 +203,30 @@ func setVersion(ch *chart.Chart, ver string) error {
 }
 
 func (p *packageCmd) clearsign(filename string) error {
-	// Load keyring
-	signer, err := provenance.NewFromKeyring(p.keyring, p.key)
+	sig, err := clearsign(filename, p.keyring, p.key)
 	if err != nil {
 		return err
 	}
 
-	if err := signer.DecryptKey(promptUser); err != nil {
-		return err
-	}
+	debug(sig)
+
+	return ioutil.WriteFile(filename+".prov", []byte(sig), 0755)
+}
 
-	sig, err := signer.ClearSign(filename)
+// clearsign signs the chart archive at filename with the key in keyring, and
+// returns its provenance file.
+func clearsign(filename, keyring, key string) (string, error) {
+	// Load keyring
+	signer, err := provenance.NewFromKeyring(keyring, key)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	debug(sig)
+	if err := signer.DecryptKey(promptUser); err != nil {
+		return "", err
+	}
 
-	return ioutil.WriteFile(filename+".prov", []byte(sig), 0755)
+	return signer.ClearSign(filename)
 }
 
 // promptUser implements provenance.PassphraseFetcher
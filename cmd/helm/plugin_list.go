@@ -26,8 +26,9 @@ import (
 )
 
 type pluginListCmd struct {
-	home helmpath.Home
-	out  io.Writer
+	home   helmpath.Home
+	output outputFormat
+	out    io.Writer
 }
 
 func newPluginListCmd(out io.Writer) *cobra.Command {
@@ -40,6 +41,7 @@ func newPluginListCmd(out io.Writer) *cobra.Command {
 			return pcmd.run()
 		},
 	}
+	bindOutputFlag(cmd.Flags(), &pcmd.output, "o")
 	return cmd
 }
 
@@ -50,6 +52,10 @@ func (pcmd *pluginListCmd) run() error {
 		return err
 	}
 
+	if pcmd.output.structured() {
+		return pcmd.output.write(pcmd.out, toPluginsOutput(plugins))
+	}
+
 	table := uitable.New()
 	table.AddRow("NAME", "VERSION", "DESCRIPTION")
 	for _, p := range plugins {
@@ -0,0 +1,193 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/repo"
+)
+
+const pushDesc = `
+This command uploads a chart to a chart repository that accepts uploads, such
+as one served by 'helm serve' with credentials.
+
+The chart is either a chart directory, which is packaged first, or a chart
+archive. The repository is given by the name it was added with 'helm repo add',
+and the credentials and certificates it was added with are used for the upload.
+
+With '--sign', the chart is signed, and its provenance file is uploaded along
+with it. Otherwise, a provenance file next to a chart archive is uploaded, if
+there is one.
+
+Plugins can push charts to repositories with other URL schemes, by declaring a
+'pushCommand' for the protocols of their downloaders.
+`
+
+type pushCmd struct {
+	chartPath string
+	repoName  string
+	sign      bool
+	key       string
+	keyring   string
+
+	out  io.Writer
+	home helmpath.Home
+}
+
+func newPushCmd(out io.Writer) *cobra.Command {
+	p := &pushCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "push [flags] CHART REPO_NAME",
+		Short: "upload a chart to a chart repository",
+		Long:  pushDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "path to the chart", "name of the chart repository"); err != nil {
+				return err
+			}
+			if p.sign && p.key == "" {
+				return errors.New("--key is required for signing a package")
+			}
+			p.chartPath = args[0]
+			p.repoName = args[1]
+			p.home = settings.Home
+			return p.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&p.sign, "sign", false, "use a PGP private key to sign the chart")
+	f.StringVar(&p.key, "key", "", "name of the key to use when signing. Used if --sign is true")
+	f.StringVar(&p.keyring, "keyring", defaultKeyring(), "location of a public keyring")
+
+	return cmd
+}
+
+func (p *pushCmd) run() error {
+	entry, err := p.repository()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempDir("", "helm-push-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	archive, err := p.archive(tmp)
+	if err != nil {
+		return err
+	}
+
+	var prov string
+	if p.sign {
+		sig, err := clearsign(archive, p.keyring, p.key)
+		if err != nil {
+			return err
+		}
+		prov = filepath.Join(tmp, filepath.Base(archive)+".prov")
+		if err := ioutil.WriteFile(prov, []byte(sig), 0644); err != nil {
+			return err
+		}
+	} else if _, err := os.Stat(archive + ".prov"); err == nil {
+		prov = archive + ".prov"
+	}
+
+	pusher, err := newPusher(entry)
+	if err != nil {
+		return err
+	}
+	if err := pusher.Push(entry.URL, archive, prov); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "Successfully pushed %s to %q\n", filepath.Base(archive), p.repoName)
+	return nil
+}
+
+// repository returns the repository that the chart is pushed to.
+func (p *pushCmd) repository() (*repo.Entry, error) {
+	f, err := repo.LoadRepositoriesFile(p.home.RepositoryFile())
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range f.Repositories {
+		if r.Name == p.repoName {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("no repo named %q found", p.repoName)
+}
+
+// archive returns the chart archive to push, which is packaged into dir if the
+// chart is a directory.
+func (p *pushCmd) archive(dir string) (string, error) {
+	path, err := filepath.Abs(p.chartPath)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		if _, err := chartutil.Load(path); err != nil {
+			return "", fmt.Errorf("%s is not a valid chart archive: %s", p.chartPath, err)
+		}
+		return path, nil
+	}
+
+	ch, err := chartutil.LoadDir(path)
+	if err != nil {
+		return "", err
+	}
+	return chartutil.Save(ch, dir)
+}
+
+// newPusher returns the pusher for the repository, with its credentials and
+// certificates.
+func newPusher(entry *repo.Entry) (getter.Pusher, error) {
+	u, err := url.Parse(entry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart URL format: %s", entry.URL)
+	}
+	newGetter, err := getter.All(settings).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGetter(entry.GetterOptions())
+	if err != nil {
+		return nil, err
+	}
+	pusher, ok := g.(getter.Pusher)
+	if !ok {
+		return nil, fmt.Errorf("pushing charts to %s repositories is not supported", u.Scheme)
+	}
+	return pusher, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/repo"
+)
+
+func TestPushCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-serve-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srv := httptest.NewServer(&repo.RepositoryServer{RepoPath: dir, Username: "user", Password: "pass"})
+	defer srv.Close()
+
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := settings.Home
+	settings.Home = hh
+	defer func() {
+		settings.Home = old
+		os.RemoveAll(hh.String())
+	}()
+
+	rf := repo.NewRepoFile()
+	rf.Add(&repo.Entry{Name: "team", URL: srv.URL, Username: "user", Password: "pass"})
+	rf.Add(&repo.Entry{Name: "other", URL: srv.URL, Username: "user", Password: "wrong"})
+	rf.Add(&repo.Entry{Name: "local", URL: srv.URL + "/charts", Username: "user", Password: "pass"})
+	if err := rf.WriteFile(hh.RepositoryFile(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect string
+		err    bool
+	}{
+		{
+			name:   "push a chart directory",
+			args:   []string{"testdata/testcharts/alpine", "team"},
+			expect: "Successfully pushed alpine-0.1.0.tgz to \"team\"",
+		},
+		{
+			name:   "push a chart archive with its provenance file",
+			args:   []string{"testdata/testcharts/signtest-0.1.0.tgz", "team"},
+			expect: "Successfully pushed signtest-0.1.0.tgz to \"team\"",
+		},
+		{
+			name:   "push to a repository served below /charts",
+			args:   []string{"testdata/testcharts/compressedchart-0.1.0.tgz", "local"},
+			expect: "Successfully pushed compressedchart-0.1.0.tgz to \"local\"",
+		},
+		{
+			name:   "push a chart version again",
+			args:   []string{"testdata/testcharts/alpine", "team"},
+			expect: "already exists",
+			err:    true,
+		},
+		{
+			name:   "push with the wrong credentials",
+			args:   []string{"testdata/testcharts/compressedchart-0.1.0.tgz", "other"},
+			expect: "unauthorized",
+			err:    true,
+		},
+		{
+			name:   "push to an unknown repository",
+			args:   []string{"testdata/testcharts/alpine", "nosuchrepo"},
+			expect: "no repo named \"nosuchrepo\" found",
+			err:    true,
+		},
+		{
+			name:   "push an invalid chart",
+			args:   []string{"testdata/testcharts/signtest-0.1.0.tgz.prov", "team"},
+			expect: "not a valid chart archive",
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		buf := bytes.NewBuffer(nil)
+		cmd := newPushCmd(buf)
+		err := cmd.RunE(cmd, tt.args)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.err, err)
+			continue
+		}
+		got := buf.String()
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.expect) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+
+	for _, f := range []string{"alpine-0.1.0.tgz", "signtest-0.1.0.tgz", "signtest-0.1.0.tgz.prov", "compressedchart-0.1.0.tgz"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("expected %s to be pushed: %s", f, err)
+		}
+	}
+}
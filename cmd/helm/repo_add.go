@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -32,11 +33,21 @@ type repoAddCmd struct {
 	url      string
 	home     helmpath.Home
 	noupdate bool
+	username string
+	password string
 
 	certFile string
 	keyFile  string
 	caFile   string
 
+	insecureSkipTLSVerify bool
+	connectTimeout        int64
+	readTimeout           int64
+	proxy                 string
+	headers               []string
+	userAgent             string
+	retries               int
+
 	out io.Writer
 }
 
@@ -63,51 +74,89 @@ func newRepoAddCmd(out io.Writer) *cobra.Command {
 
 	f := cmd.Flags()
 	f.BoolVar(&add.noupdate, "no-update", false, "raise error if repo is already registered")
+	f.StringVar(&add.username, "username", "", "chart repository username")
+	f.StringVar(&add.password, "password", "", "chart repository password")
 	f.StringVar(&add.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
 	f.StringVar(&add.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
 	f.StringVar(&add.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.BoolVar(&add.insecureSkipTLSVerify, "insecure-skip-tls-verify", false, "skip verifying the certificate of the chart repository")
+	f.Int64Var(&add.connectTimeout, "connect-timeout", 0, "time in seconds to wait for a connection to the chart repository (default 30)")
+	f.Int64Var(&add.readTimeout, "read-timeout", 0, "time in seconds to wait for data from the chart repository (default 120)")
+	f.StringVar(&add.proxy, "proxy", "", "URL of the proxy to reach the chart repository through, instead of the one in HTTP_PROXY or HTTPS_PROXY")
+	f.StringArrayVar(&add.headers, "header", []string{}, "header sent with every request to the chart repository, as 'Name: value' (can specify multiple)")
+	f.StringVar(&add.userAgent, "user-agent", "", "User-Agent of requests to the chart repository (default Helm/<version>)")
+	f.IntVar(&add.retries, "retries", 0, "number of times a failed download is retried, or -1 to never retry (default 3)")
 
 	return cmd
 }
 
 func (a *repoAddCmd) run() error {
-	if err := addRepository(a.name, a.url, a.home, a.certFile, a.keyFile, a.caFile, a.noupdate); err != nil {
+	c := &repo.Entry{
+		Name:                  a.name,
+		URL:                   a.url,
+		Username:              a.username,
+		Password:              a.password,
+		CertFile:              a.certFile,
+		KeyFile:               a.keyFile,
+		CAFile:                a.caFile,
+		InsecureSkipTLSVerify: a.insecureSkipTLSVerify,
+		ConnectTimeout:        a.connectTimeout,
+		ReadTimeout:           a.readTimeout,
+		Proxy:                 a.proxy,
+		UserAgent:             a.userAgent,
+		Retries:               a.retries,
+	}
+	headers, err := parseHeaders(a.headers)
+	if err != nil {
+		return err
+	}
+	c.Headers = headers
+
+	if err := addRepository(c, a.home, a.noupdate); err != nil {
 		return err
 	}
 	fmt.Fprintf(a.out, "%q has been added to your repositories\n", a.name)
 	return nil
 }
 
-func addRepository(name, url string, home helmpath.Home, certFile, keyFile, caFile string, noUpdate bool) error {
+// parseHeaders parses headers given as 'Name: value'.
+func parseHeaders(headers []string) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	m := map[string]string{}
+	for _, h := range headers {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", h)
+		}
+		m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return m, nil
+}
+
+func addRepository(c *repo.Entry, home helmpath.Home, noUpdate bool) error {
 	f, err := repo.LoadRepositoriesFile(home.RepositoryFile())
 	if err != nil {
 		return err
 	}
 
-	if noUpdate && f.Has(name) {
-		return fmt.Errorf("repository name (%s) already exists, please specify a different name", name)
+	if noUpdate && f.Has(c.Name) {
+		return fmt.Errorf("repository name (%s) already exists, please specify a different name", c.Name)
 	}
 
-	cif := home.CacheIndex(name)
-	c := repo.Entry{
-		Name:     name,
-		Cache:    cif,
-		URL:      url,
-		CertFile: certFile,
-		KeyFile:  keyFile,
-		CAFile:   caFile,
-	}
+	c.Cache = home.CacheIndex(c.Name)
 
-	r, err := repo.NewChartRepository(&c, getter.All(settings))
+	r, err := repo.NewChartRepository(c, getter.All(settings))
 	if err != nil {
 		return err
 	}
 
 	if err := r.DownloadIndexFile(home.Cache()); err != nil {
-		return fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", url, err.Error())
+		return fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", c.URL, err.Error())
 	}
 
-	f.Update(&c)
+	f.Update(c)
 
 	return f.WriteFile(home.RepositoryFile(), 0644)
 }
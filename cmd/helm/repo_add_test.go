@@ -79,7 +79,7 @@ func TestRepoAdd(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := addRepository(testName, ts.URL(), hh, "", "", "", true); err != nil {
+	if err := addRepository(&repo.Entry{Name: testName, URL: ts.URL()}, hh, true); err != nil {
 		t.Error(err)
 	}
 
@@ -92,11 +92,24 @@ func TestRepoAdd(t *testing.T) {
 		t.Errorf("%s was not successfully inserted into %s", testName, hh.RepositoryFile())
 	}
 
-	if err := addRepository(testName, ts.URL(), hh, "", "", "", false); err != nil {
+	if err := addRepository(&repo.Entry{Name: testName, URL: ts.URL()}, hh, false); err != nil {
 		t.Errorf("Repository was not updated: %s", err)
 	}
 
-	if err := addRepository(testName, ts.URL(), hh, "", "", "", false); err != nil {
+	if err := addRepository(&repo.Entry{Name: testName, URL: ts.URL()}, hh, false); err != nil {
 		t.Errorf("Duplicate repository name was added")
 	}
 }
+
+func TestParseHeaders(t *testing.T) {
+	h, err := parseHeaders([]string{"X-Api-Key: secret", "Accept:text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 2 || h["X-Api-Key"] != "secret" || h["Accept"] != "text/plain" {
+		t.Errorf("Unexpected headers: %v", h)
+	}
+	if _, err := parseHeaders([]string{"no colon"}); err == nil {
+		t.Error("Expected an error for a header without a value")
+	}
+}
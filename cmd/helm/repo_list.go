@@ -29,8 +29,9 @@ import (
 )
 
 type repoListCmd struct {
-	out  io.Writer
-	home helmpath.Home
+	out    io.Writer
+	home   helmpath.Home
+	output outputFormat
 }
 
 func newRepoListCmd(out io.Writer) *cobra.Command {
@@ -46,6 +47,7 @@ func newRepoListCmd(out io.Writer) *cobra.Command {
 			return list.run()
 		},
 	}
+	bindOutputFlag(cmd.Flags(), &list.output, "o")
 
 	return cmd
 }
@@ -55,6 +57,9 @@ func (a *repoListCmd) run() error {
 	if err != nil {
 		return err
 	}
+	if a.output.structured() {
+		return a.output.write(a.out, toRepositoriesOutput(f.Repositories))
+	}
 	if len(f.Repositories) == 0 {
 		return errors.New("no repositories to show")
 	}
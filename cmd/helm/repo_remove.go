@@ -19,7 +19,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/spf13/cobra"
 
@@ -84,11 +83,5 @@ func removeRepoLine(out io.Writer, name string, home helmpath.Home) error {
 }
 
 func removeRepoCache(name string, home helmpath.Home) error {
-	if _, err := os.Stat(home.CacheIndex(name)); err == nil {
-		err = os.Remove(home.CacheIndex(name))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return repo.RemoveCachedIndexFile(home.CacheIndex(name))
 }
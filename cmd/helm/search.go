@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/cmd/helm/search"
+	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/repo"
 )
@@ -34,7 +35,29 @@ const searchDesc = `
 Search reads through all of the repositories configured on the system, and
 looks for matches.
 
-Repositories are managed with 'helm repo' commands.
+Repositories are managed with 'helm repo' commands. Use '--repo' to search
+a repository by its URL instead, without adding it.
+
+The keyword is matched against the names, keywords and descriptions of the
+charts. Matches in the name rank highest, then matches in the keywords, then
+matches in the description.
+
+Terms of the form FIELD:VALUE only show charts whose field matches the value:
+
+	name:VALUE         the name contains VALUE
+	description:VALUE  the description contains VALUE
+	keyword:VALUE      one of the keywords is VALUE
+	maintainer:VALUE   the name or email of a maintainer contains VALUE
+	version:VALUE      the version satisfies the semver constraint VALUE
+	appVersion:VALUE   the app version satisfies the semver constraint VALUE,
+	                   or is VALUE if that is not a constraint
+
+For example:
+
+	$ helm search keyword:database maintainer:bitnami
+	$ helm search mysql 'appVersion:>=5.7'
+
+Use '-o json' or '-o yaml' to print the results in a format meant for scripts.
 `
 
 // searchMaxScore suggests that any score higher than this is not considered a match.
@@ -44,9 +67,18 @@ type searchCmd struct {
 	out      io.Writer
 	helmhome helmpath.Home
 
-	versions bool
-	regexp   bool
-	version  string
+	versions          bool
+	regexp            bool
+	version           string
+	excludeDeprecated bool
+	output            outputFormat
+
+	repoURL  string
+	username string
+	password string
+	certFile string
+	keyFile  string
+	caFile   string
 }
 
 func newSearchCmd(out io.Writer) *cobra.Command {
@@ -65,6 +97,14 @@ func newSearchCmd(out io.Writer) *cobra.Command {
 	f.BoolVarP(&sc.regexp, "regexp", "r", false, "use regular expressions for searching")
 	f.BoolVarP(&sc.versions, "versions", "l", false, "show the long listing, with each version of each chart on its own line")
 	f.StringVarP(&sc.version, "version", "v", "", "search using semantic versioning constraints")
+	f.BoolVar(&sc.excludeDeprecated, "exclude-deprecated", false, "do not show deprecated charts")
+	f.StringVar(&sc.repoURL, "repo", "", "chart repository url to search instead of the configured repositories")
+	f.StringVar(&sc.username, "username", "", "chart repository username")
+	f.StringVar(&sc.password, "password", "", "chart repository password")
+	f.StringVar(&sc.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
+	f.StringVar(&sc.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
+	f.StringVar(&sc.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	bindOutputFlag(f, &sc.output, "o")
 
 	return cmd
 }
@@ -75,15 +115,14 @@ func (s *searchCmd) run(args []string) error {
 		return err
 	}
 
-	var res []*search.Result
-	if len(args) == 0 {
-		res = index.All()
-	} else {
-		q := strings.Join(args, " ")
-		res, err = index.Search(q, searchMaxScore, s.regexp)
-		if err != nil {
-			return nil
-		}
+	q, err := search.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	q.ExcludeDeprecated = s.excludeDeprecated
+	res, err := index.SearchQuery(q, searchMaxScore, s.regexp)
+	if err != nil {
+		return err
 	}
 
 	search.SortScore(res)
@@ -92,6 +131,9 @@ func (s *searchCmd) run(args []string) error {
 		return err
 	}
 
+	if s.output.structured() {
+		return s.output.write(s.out, toSearchOutput(data))
+	}
 	fmt.Fprintln(s.out, s.formatSearchResults(data))
 
 	return nil
@@ -132,6 +174,10 @@ func (s *searchCmd) formatSearchResults(res []*search.Result) string {
 }
 
 func (s *searchCmd) buildIndex() (*search.Index, error) {
+	if s.repoURL != "" {
+		return s.buildRepoURLIndex()
+	}
+
 	// Load the repositories.yaml
 	rf, err := repo.LoadRepositoriesFile(s.helmhome.RepositoryFile())
 	if err != nil {
@@ -142,7 +188,7 @@ func (s *searchCmd) buildIndex() (*search.Index, error) {
 	for _, re := range rf.Repositories {
 		n := re.Name
 		f := s.helmhome.CacheIndex(n)
-		ind, err := repo.LoadIndexFile(f)
+		ind, err := repo.LoadCachedIndexFile(f)
 		if err != nil {
 			fmt.Fprintf(s.out, "WARNING: Repo %q is corrupt or missing. Try 'helm repo update'.", n)
 			continue
@@ -152,3 +198,24 @@ func (s *searchCmd) buildIndex() (*search.Index, error) {
 	}
 	return i, nil
 }
+
+// buildRepoURLIndex builds the index of the repository at repoURL, which is
+// not added to the configured repositories. Its charts are listed by their
+// names alone, as 'helm fetch --repo' and 'helm install --repo' take them.
+func (s *searchCmd) buildRepoURLIndex() (*search.Index, error) {
+	ind, err := repo.FetchIndexFile(&repo.Entry{
+		URL:      s.repoURL,
+		Username: s.username,
+		Password: s.password,
+		CertFile: s.certFile,
+		KeyFile:  s.keyFile,
+		CAFile:   s.caFile,
+	}, getter.All(settings))
+	if err != nil {
+		return nil, err
+	}
+
+	i := search.NewIndex()
+	i.AddRepo("", ind, s.versions)
+	return i, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+
+	"k8s.io/helm/pkg/repo"
+)
+
+// Query is a parsed search query.
+type Query struct {
+	// Text is searched for in the names, keywords and descriptions of charts.
+	Text string
+	// Filters are the field-qualified terms, which every result must match.
+	Filters []*Filter
+	// ExcludeDeprecated excludes deprecated charts from the results.
+	ExcludeDeprecated bool
+}
+
+// Filter is a field-qualified term of a query, like "keyword:database".
+type Filter struct {
+	Field string
+	Value string
+	// constraint is the version constraint of the version and appVersion
+	// fields, if Value is one.
+	constraint *semver.Constraints
+}
+
+// filterFields are the fields that terms can be qualified with, by their lower
+// case names, and how a chart is matched against a term.
+var filterFields = map[string]func(f *Filter, ch *repo.ChartVersion) bool{
+	"name": func(f *Filter, ch *repo.ChartVersion) bool {
+		return containsFold(ch.Name, f.Value)
+	},
+	"description": func(f *Filter, ch *repo.ChartVersion) bool {
+		return containsFold(ch.Description, f.Value)
+	},
+	"keyword": func(f *Filter, ch *repo.ChartVersion) bool {
+		for _, k := range ch.Keywords {
+			if strings.EqualFold(k, f.Value) {
+				return true
+			}
+		}
+		return false
+	},
+	"maintainer": func(f *Filter, ch *repo.ChartVersion) bool {
+		for _, m := range ch.Maintainers {
+			if containsFold(m.Name, f.Value) || containsFold(m.Email, f.Value) {
+				return true
+			}
+		}
+		return false
+	},
+	"version": func(f *Filter, ch *repo.ChartVersion) bool {
+		return f.checkVersion(ch.Version)
+	},
+	"appversion": func(f *Filter, ch *repo.ChartVersion) bool {
+		return f.checkVersion(ch.AppVersion)
+	},
+}
+
+// ParseQuery parses a search query.
+//
+// Terms of the form FIELD:VALUE filter the results by a field of the charts.
+// The fields are:
+//
+//	name:VALUE          the name contains VALUE
+//	description:VALUE   the description contains VALUE
+//	keyword:VALUE       one of the keywords is VALUE
+//	maintainer:VALUE    the name or email of a maintainer contains VALUE
+//	version:VALUE       the version satisfies the semver constraint VALUE
+//	appVersion:VALUE    the app version satisfies the semver constraint VALUE,
+//	                    or is VALUE if that is not a constraint
+//
+// Field names are not case sensitive, and values may be quoted, as in
+// maintainer:"Jane Doe". All other terms are the text of the query.
+func ParseQuery(q string) (*Query, error) {
+	query := &Query{}
+	var text []string
+	for _, term := range splitTerms(q) {
+		j := strings.Index(term, ":")
+		if j <= 0 {
+			text = append(text, term)
+			continue
+		}
+		field := strings.ToLower(term[:j])
+		if _, ok := filterFields[field]; !ok {
+			text = append(text, term)
+			continue
+		}
+		f := &Filter{Field: field, Value: strings.Trim(term[j+1:], `"`)}
+		if f.Value == "" {
+			return nil, fmt.Errorf("no value for the search field %q", term[:j])
+		}
+		if field == "version" || field == "appversion" {
+			c, err := semver.NewConstraint(f.Value)
+			if err != nil && field == "version" {
+				return nil, fmt.Errorf("invalid version constraint %q: %s", f.Value, err)
+			}
+			f.constraint = c
+		}
+		query.Filters = append(query.Filters, f)
+	}
+	query.Text = strings.Join(text, " ")
+	return query, nil
+}
+
+// Matches returns true if a chart matches all filters of the query.
+func (q *Query) Matches(ch *repo.ChartVersion) bool {
+	if ch.Metadata == nil {
+		return false
+	}
+	if q.ExcludeDeprecated && ch.Deprecated {
+		return false
+	}
+	for _, f := range q.Filters {
+		if !filterFields[f.Field](f, ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkVersion returns true if version satisfies the constraint of the filter,
+// or is its value if it has no constraint.
+func (f *Filter) checkVersion(version string) bool {
+	if f.constraint == nil {
+		return strings.EqualFold(version, f.Value)
+	}
+	v, err := semver.NewVersion(version)
+	return err == nil && f.constraint.Check(v)
+}
+
+// splitTerms splits a query at white space that is not quoted.
+func splitTerms(q string) []string {
+	quoted := false
+	return strings.FieldsFunc(q, func(r rune) bool {
+		if r == '"' {
+			quoted = !quoted
+		}
+		return !quoted && unicode.IsSpace(r)
+	})
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
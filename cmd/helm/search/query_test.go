@@ -0,0 +1,158 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/repo"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		text    string
+		filters []Filter
+		fail    bool
+	}{
+		{query: "mysql", text: "mysql"},
+		{query: "  my   sql ", text: "my sql"},
+		{query: "testing/pinta", text: "testing/pinta"},
+		{query: "http://example.com", text: "http://example.com"},
+		{
+			query:   "keyword:database mysql",
+			text:    "mysql",
+			filters: []Filter{{Field: "keyword", Value: "database"}},
+		},
+		{
+			query:   `Maintainer:"Jane Doe" appVersion:>=5`,
+			filters: []Filter{{Field: "maintainer", Value: "Jane Doe"}, {Field: "appversion", Value: ">=5"}},
+		},
+		{query: "keyword:", fail: true},
+		{query: "version:banana", fail: true},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", tt.query, err)
+			continue
+		}
+		if q.Text != tt.text {
+			t.Errorf("%q: expected text %q, got %q", tt.query, tt.text, q.Text)
+		}
+		var filters []Filter
+		for _, f := range q.Filters {
+			filters = append(filters, Filter{Field: f.Field, Value: f.Value})
+		}
+		if !reflect.DeepEqual(filters, tt.filters) {
+			t.Errorf("%q: expected filters %v, got %v", tt.query, tt.filters, filters)
+		}
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	ch := &repo.ChartVersion{Metadata: &chart.Metadata{
+		Name:        "mysql",
+		Version:     "0.3.0",
+		AppVersion:  "5.7.14",
+		Keywords:    []string{"mysql", "database"},
+		Description: "Fast, reliable, scalable, and easy to use open-source relational database system.",
+		Maintainers: []*chart.Maintainer{{Name: "Alice", Email: "alice@example.com"}},
+	}}
+	deprecated := &repo.ChartVersion{Metadata: &chart.Metadata{
+		Name:       "mysql",
+		Version:    "0.3.0",
+		AppVersion: "latest",
+		Deprecated: true,
+	}}
+
+	tests := []struct {
+		query  string
+		chart  *repo.ChartVersion
+		expect bool
+	}{
+		{"mysql", ch, true},
+		{"name:sql", ch, true},
+		{"name:postgres", ch, false},
+		{"keyword:DATABASE", ch, true},
+		{"keyword:data", ch, false},
+		{"maintainer:alice", ch, true},
+		{"maintainer:example.com", ch, true},
+		{"maintainer:bob", ch, false},
+		{"description:relational", ch, true},
+		{"version:>=0.2", ch, true},
+		{"version:^1", ch, false},
+		{"appVersion:>=5", ch, true},
+		{"appVersion:<5.0.0", ch, false},
+		{"appVersion:latest", deprecated, true},
+		{"appVersion:>=5", deprecated, false},
+		{"keyword:database maintainer:bob", ch, false},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.Matches(tt.chart); got != tt.expect {
+			t.Errorf("%q: expected %t, got %t", tt.query, tt.expect, got)
+		}
+	}
+
+	q := &Query{ExcludeDeprecated: true}
+	if !q.Matches(ch) {
+		t.Error("expected a chart that is not deprecated to match")
+	}
+	if q.Matches(deprecated) {
+		t.Error("expected a deprecated chart not to match")
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	i := loadTestIndex(t, false)
+
+	q, err := ParseQuery("version:>=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := i.SearchQuery(q, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SortScore(res)
+	if len(res) != 2 || res[0].Name != "testing/santa-maria" || res[1].Name != "ztesting/pinta" {
+		t.Errorf("expected testing/santa-maria and ztesting/pinta, got %v", res)
+	}
+	for _, r := range res {
+		if r.Score != 0 {
+			t.Errorf("expected a score of 0 for %s without search text, got %d", r.Name, r.Score)
+		}
+	}
+
+	if res, _ = i.Search("pinta version:>=1", 100, false); len(res) != 1 || res[0].Name != "ztesting/pinta" {
+		t.Errorf("expected ztesting/pinta, got %v", res)
+	}
+}
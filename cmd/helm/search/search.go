@@ -24,6 +24,7 @@ package search
 
 import (
 	"errors"
+	"math"
 	"path"
 	"regexp"
 	"sort"
@@ -46,15 +47,12 @@ type Result struct {
 
 // Index is a searchable index of chart information.
 type Index struct {
-	lines  map[string]string
 	charts map[string]*repo.ChartVersion
 }
 
-const sep = "\v"
-
 // NewIndex creats a new Index.
 func NewIndex() *Index {
-	return &Index{lines: map[string]string{}, charts: map[string]*repo.ChartVersion{}}
+	return &Index{charts: map[string]*repo.ChartVersion{}}
 }
 
 // verSep is a separator for version fields in map keys.
@@ -75,7 +73,6 @@ func (i *Index) AddRepo(rname string, ind *repo.IndexFile, all bool) {
 		//       which results in a repo name that cannot be understood.
 		fname := path.Join(rname, name)
 		if !all {
-			i.lines[fname] = indstr(rname, ref[0])
 			i.charts[fname] = ref[0]
 			continue
 		}
@@ -84,7 +81,6 @@ func (i *Index) AddRepo(rname string, ind *repo.IndexFile, all bool) {
 		// to the index. This will generate a lot of near-duplicate entries.
 		for _, rr := range ref {
 			versionedName := fname + verSep + rr.Version
-			i.lines[versionedName] = indstr(rname, rr)
 			i.charts[versionedName] = rr
 		}
 	}
@@ -114,68 +110,142 @@ func (i *Index) All() []*Result {
 //
 // If regexp is true, the term is treated as a regular expression. Otherwise,
 // term is treated as a literal string.
+//
+// The term may also contain field-qualified terms, as described by ParseQuery.
 func (i *Index) Search(term string, threshold int, regexp bool) ([]*Result, error) {
-	if regexp {
-		return i.SearchRegexp(term, threshold)
-	}
-	return i.SearchLiteral(term, threshold), nil
-}
-
-// calcScore calculates a score for a match.
-func (i *Index) calcScore(index int, matchline string) int {
-
-	// This is currently tied to the fact that sep is a single char.
-	splits := []int{}
-	s := rune(sep[0])
-	for i, ch := range matchline {
-		if ch == s {
-			splits = append(splits, i)
-		}
-	}
-
-	for i, pos := range splits {
-		if index > pos {
-			continue
-		}
-		return i
+	q, err := ParseQuery(term)
+	if err != nil {
+		return []*Result{}, err
 	}
-	return len(splits)
+	return i.SearchQuery(q, threshold, regexp)
 }
 
 // SearchLiteral does a literal string search (no regexp).
 func (i *Index) SearchLiteral(term string, threshold int) []*Result {
-	term = strings.ToLower(term)
-	buf := []*Result{}
-	for k, v := range i.lines {
-		res := strings.Index(v, term)
-		if score := i.calcScore(res, v); res != -1 && score < threshold {
-			parts := strings.Split(k, verSep) // Remove version, if it is there.
-			buf = append(buf, &Result{Name: parts[0], Score: score, Chart: i.charts[k]})
-		}
-	}
-	return buf
+	res, _ := i.SearchQuery(&Query{Text: term}, threshold, false)
+	return res
 }
 
 // SearchRegexp searches using a regular expression.
 func (i *Index) SearchRegexp(re string, threshold int) ([]*Result, error) {
-	matcher, err := regexp.Compile(re)
+	return i.SearchQuery(&Query{Text: re}, threshold, true)
+}
+
+// SearchQuery searches an index for the charts that match all filters of q,
+// and whose fields contain its text.
+//
+// Every result is scored by the field that the text matched best, so that a
+// match in the name ranks above a match in the keywords, which ranks above a
+// match in the description. If q has no text, every chart that matches the
+// filters is returned with a score of 0.
+func (i *Index) SearchQuery(q *Query, threshold int, regexp bool) ([]*Result, error) {
+	match, err := newMatcher(q.Text, regexp)
 	if err != nil {
 		return []*Result{}, err
 	}
 	buf := []*Result{}
-	for k, v := range i.lines {
-		ind := matcher.FindStringIndex(v)
-		if len(ind) == 0 {
+	for k, ch := range i.charts {
+		if !q.Matches(ch) {
 			continue
 		}
-		if score := i.calcScore(ind[0], v); ind[0] >= 0 && score < threshold {
-			parts := strings.Split(k, verSep) // Remove version, if it is there.
-			buf = append(buf, &Result{Name: parts[0], Score: score, Chart: i.charts[k]})
+		parts := strings.Split(k, verSep) // Remove version, if it is there.
+		score := 0
+		if q.Text != "" {
+			score = calcScore(match, parts[0], ch)
+		}
+		if score < threshold {
+			buf = append(buf, &Result{Name: parts[0], Score: score, Chart: ch})
 		}
 	}
 	return buf, nil
 }
 
+// The scores of a match of the search text in the fields of a chart.
+const (
+	scoreExactName    = 0
+	scoreNamePrefix   = 1
+	scoreName         = 2
+	scoreRepoName     = 3
+	scoreExactKeyword = 4
+	scoreKeyword      = 5
+	scoreDescription  = 6
+	scoreNoMatch      = math.MaxInt32
+)
+
+// The kinds of matches of the search text in a field.
+const (
+	noMatch = iota
+	partialMatch
+	prefixMatch
+	exactMatch
+)
+
+// matcher returns how the search text matches a field.
+type matcher func(field string) int
+
+func newMatcher(text string, useRegexp bool) (matcher, error) {
+	var find func(string) []int
+	if useRegexp {
+		re, err := regexp.Compile(text)
+		if err != nil {
+			return nil, err
+		}
+		// Fields are matched in lower case, as they always have been.
+		find = func(field string) []int {
+			return re.FindStringIndex(strings.ToLower(field))
+		}
+	} else {
+		text = strings.ToLower(text)
+		find = func(field string) []int {
+			if j := strings.Index(strings.ToLower(field), text); j >= 0 {
+				return []int{j, j + len(text)}
+			}
+			return nil
+		}
+	}
+	return func(field string) int {
+		loc := find(field)
+		switch {
+		case loc == nil:
+			return noMatch
+		case loc[0] == 0 && loc[1] == len(field):
+			return exactMatch
+		case loc[0] == 0:
+			return prefixMatch
+		}
+		return partialMatch
+	}, nil
+}
+
+// calcScore calculates the score of the best match of a chart, which is named
+// fullname in the index.
+func calcScore(match matcher, fullname string, ch *repo.ChartVersion) int {
+	switch match(ch.Name) {
+	case exactMatch:
+		return scoreExactName
+	case prefixMatch:
+		return scoreNamePrefix
+	case partialMatch:
+		return scoreName
+	}
+	if match(fullname) != noMatch {
+		return scoreRepoName
+	}
+	score := scoreNoMatch
+	for _, k := range ch.Keywords {
+		switch match(k) {
+		case exactMatch:
+			return scoreExactKeyword
+		case prefixMatch, partialMatch:
+			score = scoreKeyword
+		}
+	}
+	if score == scoreNoMatch && match(ch.Description) != noMatch {
+		score = scoreDescription
+	}
+	return score
+}
+
 // Chart returns the ChartVersion for a particular name.
 func (i *Index) Chart(name string) (*repo.ChartVersion, error) {
 	c, ok := i.charts[name]
@@ -227,9 +297,3 @@ func (s scoreSorter) Less(a, b int) bool {
 	}
 	return first.Name < second.Name
 }
-
-func indstr(name string, ref *repo.ChartVersion) string {
-	i := ref.Name + sep + name + "/" + ref.Name + sep +
-		ref.Description + sep + strings.Join(ref.Keywords, " ")
-	return strings.ToLower(i)
-}
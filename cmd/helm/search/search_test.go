@@ -274,20 +274,67 @@ func TestSearchByNameAll(t *testing.T) {
 }
 
 func TestCalcScore(t *testing.T) {
-	i := NewIndex()
+	ch := &repo.ChartVersion{Metadata: &chart.Metadata{
+		Name:        "mysql",
+		Keywords:    []string{"database", "sql"},
+		Description: "Fast, reliable, scalable, and easy to use open-source relational database system.",
+	}}
 
-	fields := []string{"aaa", "bbb", "ccc", "ddd"}
-	matchline := strings.Join(fields, sep)
-	if r := i.calcScore(2, matchline); r != 0 {
-		t.Errorf("Expected 0, got %d", r)
+	tests := []struct {
+		text   string
+		regexp bool
+		expect int
+	}{
+		{"mysql", false, scoreExactName},
+		{"MySQL", false, scoreExactName},
+		{"my", false, scoreNamePrefix},
+		{"sql", false, scoreName},
+		{"stable/mysql", false, scoreRepoName},
+		{"stable/", false, scoreRepoName},
+		{"database", false, scoreExactKeyword},
+		{"data", false, scoreKeyword},
+		{"relational", false, scoreDescription},
+		{"postgres", false, scoreNoMatch},
+		{"^my.*l$", true, scoreExactName},
+		{"^data", true, scoreKeyword},
+		{"open-?source", true, scoreDescription},
 	}
-	if r := i.calcScore(5, matchline); r != 1 {
-		t.Errorf("Expected 1, got %d", r)
+
+	for _, tt := range tests {
+		match, err := newMatcher(tt.text, tt.regexp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if score := calcScore(match, "stable/mysql", ch); score != tt.expect {
+			t.Errorf("%q: expected score %d, got %d", tt.text, tt.expect, score)
+		}
 	}
-	if r := i.calcScore(10, matchline); r != 2 {
-		t.Errorf("Expected 2, got %d", r)
+}
+
+func TestSearchRanking(t *testing.T) {
+	i := NewIndex()
+	i.AddRepo("stable", &repo.IndexFile{Entries: map[string]repo.ChartVersions{
+		"mariadb":      {{Metadata: &chart.Metadata{Name: "mariadb", Version: "1.0.0", Keywords: []string{"mysql"}, Description: "MariaDB server"}}},
+		"mysql":        {{Metadata: &chart.Metadata{Name: "mysql", Version: "1.0.0", Description: "MySQL server"}}},
+		"mysqldump":    {{Metadata: &chart.Metadata{Name: "mysqldump", Version: "1.0.0", Description: "Backups"}}},
+		"phpmyadmin":   {{Metadata: &chart.Metadata{Name: "phpmyadmin", Version: "1.0.0", Description: "Administers mysql servers"}}},
+		"wordpress":    {{Metadata: &chart.Metadata{Name: "wordpress", Version: "1.0.0", Keywords: []string{"blog"}, Description: "Blog engine"}}},
+		"zabbix-mysql": {{Metadata: &chart.Metadata{Name: "zabbix-mysql", Version: "1.0.0", Description: "Monitoring"}}},
+	}}, false)
+
+	res, err := i.Search("mysql", 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SortScore(res)
+
+	expect := []string{"stable/mysql", "stable/mysqldump", "stable/zabbix-mysql", "stable/mariadb", "stable/phpmyadmin"}
+	if len(res) != len(expect) {
+		t.Fatalf("expected %d results, got %d", len(expect), len(res))
 	}
-	if r := i.calcScore(14, matchline); r != 3 {
-		t.Errorf("Expected 3, got %d", r)
+	for j, r := range res {
+		if r.Name != expect[j] {
+			t.Errorf("expected result %d to be %q, got %q", j, expect[j], r.Name)
+		}
 	}
 }
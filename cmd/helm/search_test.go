@@ -18,8 +18,11 @@ package main
 
 import (
 	"bytes"
+	"os"
 	"strings"
 	"testing"
+
+	"k8s.io/helm/pkg/repo/repotest"
 )
 
 func TestSearchCmd(t *testing.T) {
@@ -59,6 +62,33 @@ func TestSearchCmd(t *testing.T) {
 			expect: "NAME          \tVERSION\tDESCRIPTION                    \ntesting/alpine\t0.2.0  \tDeploy a basic Alpine Linux pod",
 			regexp: true,
 		},
+		{
+			name:   "search for 'maria' as json",
+			args:   []string{"maria"},
+			flags:  []string{"-o", "json"},
+			expect: "[\n  {\n    \"name\": \"testing/mariadb\",\n    \"version\": \"0.3.0\",\n    \"description\": \"Chart for MariaDB\"\n  }\n]",
+		},
+		{
+			name:   "search for 'syzygy' as yaml, expect no matches",
+			args:   []string{"syzygy"},
+			flags:  []string{"-o", "yaml"},
+			expect: "[]",
+		},
+		{
+			name:   "search for 'keyword:database', expect one match",
+			args:   []string{"keyword:database"},
+			expect: "NAME           \tVERSION\tDESCRIPTION      \ntesting/mariadb\t0.3.0  \tChart for MariaDB",
+		},
+		{
+			name:   "search for 'maintainer:bitnami' and 'alpine', expect no matches",
+			args:   []string{"maintainer:bitnami", "alpine"},
+			expect: "No results found",
+		},
+		{
+			name: "search for 'version:banana', expect failure to parse the constraint",
+			args: []string{"version:banana"},
+			fail: true,
+		},
 		{
 			name:   "search for 'alp[', expect failure to compile regexp",
 			args:   []string{"alp["},
@@ -88,3 +118,38 @@ func TestSearchCmd(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchRepoURL(t *testing.T) {
+	srv, thome, err := repotest.NewTempServer("testdata/testcharts/*.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldhome := settings.Home
+	settings.Home = thome
+	defer func() {
+		srv.Stop()
+		settings.Home = oldhome
+		os.RemoveAll(thome.String())
+	}()
+	if err := ensureTestHome(thome, t); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	cmd := newSearchCmd(buf)
+	cmd.ParseFlags([]string{"--repo", srv.URL()})
+	if err := cmd.RunE(cmd, []string{"signtest"}); err != nil {
+		t.Fatal(err)
+	}
+	expect := "NAME    \tVERSION\tDESCRIPTION                \nsigntest\t0.1.0  \tA Helm chart for Kubernetes"
+	if got := strings.TrimSpace(buf.String()); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	cmd = newSearchCmd(buf)
+	cmd.ParseFlags([]string{"--repo", srv.URL() + "/nonexistent"})
+	if err := cmd.RunE(cmd, []string{"signtest"}); err == nil {
+		t.Error("expected an error for a repository without an index")
+	}
+}
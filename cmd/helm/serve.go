@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
@@ -34,6 +35,13 @@ The new server will provide HTTP access to a repository. By default, it will
 scan all of the charts in '$HELM_HOME/repository/local' and serve those over
 the local IPv4 TCP port (default '127.0.0.1:8879').
 
+With '--username' and '--password', or '--token', the server also accepts
+charts that are uploaded to '/api/charts', and deletes chart versions on
+'DELETE /api/charts/NAME/VERSION'. Requests must carry the same credentials,
+as basic auth or a bearer token. '/api/charts' lists the charts as JSON. The
+API is also served below '/charts', so 'helm push' works with the default
+'local' repository.
+
 This command is intended to be used for educational and testing purposes only.
 It is best to rely on a dedicated web server or a cloud-hosted solution like
 Google Cloud Storage for production use.
@@ -47,6 +55,9 @@ type serveCmd struct {
 	url      string
 	address  string
 	repoPath string
+	username string
+	password string
+	token    string
 }
 
 func newServeCmd(out io.Writer) *cobra.Command {
@@ -67,6 +78,9 @@ func newServeCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&srv.repoPath, "repo-path", "", "local directory path from which to serve charts")
 	f.StringVar(&srv.address, "address", "127.0.0.1:8879", "address to listen on")
 	f.StringVar(&srv.url, "url", "", "external URL of chart repository")
+	f.StringVar(&srv.username, "username", "", "username required to upload and delete charts")
+	f.StringVar(&srv.password, "password", "", "password required to upload and delete charts")
+	f.StringVar(&srv.token, "token", "", "bearer token required to upload and delete charts")
 
 	return cmd
 }
@@ -87,16 +101,21 @@ func (s *serveCmd) run() error {
 		return err
 	}
 
-	fmt.Fprintln(s.out, "Regenerating index. This may take a moment.")
-	if len(s.url) > 0 {
-		err = index(repoPath, s.url, "")
-	} else {
-		err = index(repoPath, "http://"+s.address, "")
+	url := s.url
+	if len(url) == 0 {
+		url = "http://" + s.address
 	}
-	if err != nil {
+	fmt.Fprintln(s.out, "Regenerating index. This may take a moment.")
+	if err := index(repoPath, url, ""); err != nil {
 		return err
 	}
 
 	fmt.Fprintf(s.out, "Now serving you on %s\n", s.address)
-	return repo.StartLocalRepo(repoPath, s.address)
+	return http.ListenAndServe(s.address, &repo.RepositoryServer{
+		RepoPath: repoPath,
+		URL:      url,
+		Username: s.username,
+		Password: s.password,
+		Token:    s.token,
+	})
 }
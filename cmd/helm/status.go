@@ -41,6 +41,8 @@ The status consists of:
 - list of resources that this release consists of, sorted by kind
 - details on last test suite run, if applicable
 - additional notes provided by the chart
+
+Use '-o json' or '-o yaml' to print the status in a format meant for scripts.
 `
 
 type statusCmd struct {
@@ -48,6 +50,7 @@ type statusCmd struct {
 	out     io.Writer
 	client  helm.Interface
 	version int32
+	output  outputFormat
 }
 
 func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -74,6 +77,7 @@ func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	cmd.PersistentFlags().Int32Var(&status.version, "revision", 0, "if set, display the status of the named release with revision")
+	bindOutputFlag(cmd.Flags(), &status.output, "o")
 
 	return cmd
 }
@@ -84,6 +88,9 @@ func (s *statusCmd) run() error {
 		return prettyError(err)
 	}
 
+	if s.output.structured() {
+		return s.output.write(s.out, toReleaseStatusOutput(res))
+	}
 	PrintStatus(s.out, res)
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/spf13/cobra"
@@ -104,6 +105,17 @@ func TestStatusCmd(t *testing.T) {
 				},
 			}),
 		},
+		{
+			name:  "get status of a deployed release as json",
+			args:  []string{"flummoxed-chickadee"},
+			flags: []string{"-o", "json"},
+			expected: fmt.Sprintf("{\n\"name\":\"flummoxed-chickadee\",\n\"namespace\":\"\",\n\"lastDeployed\":\"%s\",\n\"status\":\"DEPLOYED\",\n\"notes\":\"releasenotes\"\n}\n",
+				timeconv.Format(&date, time.RFC3339)),
+			rel: releaseMockWithStatus(&release.Status{
+				Code:  release.Status_DEPLOYED,
+				Notes: "release notes",
+			}),
+		},
 	}
 
 	scmd := func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
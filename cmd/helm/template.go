@@ -0,0 +1,274 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/timeconv"
+	tversion "k8s.io/helm/pkg/version"
+)
+
+const templateDesc = `
+This command renders the templates of a chart locally and displays the output.
+
+No connection to Tiller or to a Kubernetes cluster is needed. Values are
+merged exactly like they are for 'helm install', and the rendered manifests
+are sorted in the order Tiller would install them. Hooks follow the manifests.
+
+Since no cluster is available, the Kubernetes version and the API versions
+seen by the chart can be set with '--kube-version' and '--api-versions'.
+
+To render only some templates, use '-x' with their path in the chart:
+
+	$ helm template ./redis -x templates/deployment.yaml
+
+To write every rendered template to a file instead, use '--output-dir':
+
+	$ helm template ./redis --output-dir ./manifests
+
+Values are set like they are for 'helm install', with '-f', '--set',
+'--set-string', '--set-file' and '--set-json'. Values files can be read from
+stdin or from URLs; the certificate flags apply to HTTPS URLs.
+`
+
+// defaultAPIVersions are the API versions served by a Kubernetes 1.7 cluster.
+var defaultAPIVersions = []string{
+	"v1",
+	"apiextensions.k8s.io/v1beta1",
+	"apiregistration.k8s.io/v1beta1",
+	"apps/v1beta1",
+	"authentication.k8s.io/v1",
+	"authentication.k8s.io/v1beta1",
+	"authorization.k8s.io/v1",
+	"authorization.k8s.io/v1beta1",
+	"autoscaling/v1",
+	"batch/v1",
+	"certificates.k8s.io/v1beta1",
+	"extensions/v1beta1",
+	"networking.k8s.io/v1",
+	"policy/v1beta1",
+	"rbac.authorization.k8s.io/v1alpha1",
+	"rbac.authorization.k8s.io/v1beta1",
+	"storage.k8s.io/v1",
+	"storage.k8s.io/v1beta1",
+}
+
+type templateCmd struct {
+	valuesOptions
+
+	chartPath   string
+	name        string
+	namespace   string
+	kubeVersion string
+	apiVersions []string
+	renderFiles []string
+	outputDir   string
+	out         io.Writer
+
+	// lookup answers the 'lookup' template function. If nil, lookup finds no
+	// objects, since templates are rendered without a cluster.
+	lookup engine.LookupFunc
+}
+
+func newTemplateCmd(out io.Writer) *cobra.Command {
+	t := &templateCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "template [flags] CHART",
+		Short: "locally render templates",
+		Long:  templateDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart path"); err != nil {
+				return err
+			}
+			t.chartPath = args[0]
+			return t.run()
+		},
+	}
+
+	f := cmd.Flags()
+	t.valuesOptions.addFlags(f)
+	f.StringVarP(&t.name, "name", "n", "RELEASE-NAME", "release name")
+	f.StringVar(&t.namespace, "namespace", "", "namespace to render the templates for")
+	f.StringVar(&t.kubeVersion, "kube-version", fmt.Sprintf("%s.%s", chartutil.DefaultKubeVersion.Major, chartutil.DefaultKubeVersion.Minor), "Kubernetes version used as Capabilities.KubeVersion")
+	f.StringArrayVar(&t.apiVersions, "api-versions", []string{}, "Kubernetes API versions used as Capabilities.APIVersions, instead of those of Kubernetes 1.7 (can specify multiple)")
+	f.StringArrayVarP(&t.renderFiles, "execute", "x", []string{}, "only render the given templates, relative to the chart (can specify multiple)")
+	f.StringVar(&t.outputDir, "output-dir", "", "write the rendered templates to files in this directory instead of stdout")
+
+	return cmd
+}
+
+func (t *templateCmd) run() error {
+	if t.namespace == "" {
+		t.namespace = defaultNamespace()
+	}
+
+	ch, err := chartutil.Load(t.chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	if req, err := chartutil.LoadRequirements(ch); err == nil {
+		if err := checkDependencies(ch, req); err != nil {
+			return prettyError(err)
+		}
+	} else if err != chartutil.ErrRequirementsNotFound {
+		return fmt.Errorf("cannot load requirements: %v", err)
+	}
+
+	rawVals, err := t.vals()
+	if err != nil {
+		return err
+	}
+	config := &chart.Config{Raw: string(rawVals), Values: map[string]*chart.Value{}}
+
+	if err := chartutil.ProcessRequirementsEnabled(ch, config); err != nil {
+		return err
+	}
+	if err := chartutil.ProcessRequirementsImportValues(ch); err != nil {
+		return err
+	}
+
+	caps, err := t.capabilities()
+	if err != nil {
+		return err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      t.name,
+		Time:      timeconv.Now(),
+		Namespace: t.namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderVals, err := chartutil.ToRenderValuesCaps(ch, config, options, caps)
+	if err != nil {
+		return err
+	}
+
+	e := engine.New()
+	e.Lookup = t.lookup
+	files, err := e.Render(ch, renderVals)
+	if err != nil {
+		return err
+	}
+	for k := range files {
+		if strings.HasSuffix(k, "NOTES.txt") {
+			delete(files, k)
+		}
+	}
+
+	hooks, manifests, err := tiller.SortTemplates(ch, files, caps.APIVersions)
+	if err != nil {
+		return err
+	}
+
+	// Hooks are printed after the manifests, in the order of their templates.
+	rendered := manifests
+	for _, h := range hooks {
+		rendered = append(rendered, tiller.Manifest{Name: h.Path, Content: h.Manifest})
+	}
+
+	selected, err := t.selected(ch.Metadata.Name, files)
+	if err != nil {
+		return err
+	}
+	written := map[string]bool{}
+	for _, m := range rendered {
+		if len(selected) > 0 && !selected[m.Name] {
+			continue
+		}
+		if t.outputDir == "" {
+			fmt.Fprintf(t.out, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+			continue
+		}
+		if err := writeManifest(t.outputDir, m, !written[m.Name]); err != nil {
+			return err
+		}
+		written[m.Name] = true
+	}
+	return nil
+}
+
+// capabilities builds the capabilities of the cluster the chart is rendered for.
+func (t *templateCmd) capabilities() (*chartutil.Capabilities, error) {
+	kv, err := semver.NewVersion(t.kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse a Kubernetes version: %s", err)
+	}
+	kubeVersion := *chartutil.DefaultKubeVersion
+	kubeVersion.Major = fmt.Sprint(kv.Major())
+	kubeVersion.Minor = fmt.Sprint(kv.Minor())
+	kubeVersion.GitVersion = fmt.Sprintf("v%d.%d.%d", kv.Major(), kv.Minor(), kv.Patch())
+
+	apiVersions := defaultAPIVersions
+	if len(t.apiVersions) > 0 {
+		apiVersions = t.apiVersions
+	}
+
+	return &chartutil.Capabilities{
+		APIVersions:   chartutil.NewVersionSet(apiVersions...),
+		KubeVersion:   &kubeVersion,
+		TillerVersion: tversion.GetVersionProto(),
+	}, nil
+}
+
+// selected returns the rendered names of the templates given with -x.
+func (t *templateCmd) selected(chartName string, files map[string]string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	for _, f := range t.renderFiles {
+		name := path.Join(chartName, filepath.ToSlash(filepath.Clean(f)))
+		if _, ok := files[name]; !ok {
+			return nil, fmt.Errorf("could not find template %s in chart", f)
+		}
+		selected[name] = true
+	}
+	return selected, nil
+}
+
+// writeManifest writes a rendered manifest under dir, truncating the file if
+// create is true and appending to it otherwise.
+func writeManifest(dir string, m tiller.Manifest, create bool) error {
+	name := filepath.Join(dir, filepath.FromSlash(m.Name))
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if create {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(name, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	return err
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateCmd(t *testing.T) {
+	chartPath := "testdata/testcharts/alpine"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		err      bool
+	}{
+		{
+			name: "render with values",
+			args: []string{chartPath, "--set", "test.Name=value"},
+			expected: []string{
+				"# Source: alpine/templates/alpine-pod.yaml",
+				`name: "RELEASE-NAME-my-alpine"`,
+				"values: value",
+			},
+		},
+		{
+			name:     "render with string values",
+			args:     []string{chartPath, "--set-string", "test.Name=0123"},
+			expected: []string{"values: 0123"},
+		},
+		{
+			name:     "render with release name",
+			args:     []string{chartPath, "--set", "test.Name=value", "--name", "foobar"},
+			expected: []string{`release: "foobar"`},
+		},
+		{
+			name:     "render a single template",
+			args:     []string{chartPath, "--set", "test.Name=value", "-x", "templates/alpine-pod.yaml"},
+			expected: []string{"# Source: alpine/templates/alpine-pod.yaml"},
+		},
+		{
+			name: "render a missing template",
+			args: []string{chartPath, "--set", "test.Name=value", "-x", "templates/missing.yaml"},
+			err:  true,
+		},
+		{
+			name: "bad kube version",
+			args: []string{chartPath, "--set", "test.Name=value", "--kube-version", "one"},
+			err:  true,
+		},
+		{
+			name: "no chart",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		cmd := newTemplateCmd(&buf)
+		cmd.SetArgs(tt.args)
+		err := cmd.Execute()
+		if (err != nil) != tt.err {
+			t.Errorf("%q. expected error, got '%v'", tt.name, err)
+		}
+		for _, e := range tt.expected {
+			if !strings.Contains(buf.String(), e) {
+				t.Errorf("%q. expected\n%q\nto contain\n%q", tt.name, buf.String(), e)
+			}
+		}
+	}
+}
+
+func TestTemplateCmd_OutputDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-template-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	cmd := newTemplateCmd(&buf)
+	cmd.SetArgs([]string{"testdata/testcharts/alpine", "--set", "test.Name=value", "--output-dir", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "alpine", "templates", "alpine-pod.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "kind: Pod") {
+		t.Errorf("unexpected rendered template: %s", b)
+	}
+}
+
+func TestTemplateCmd_Lookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-template-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chartDir := filepath.Join(dir, "db")
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("name: db\nversion: 0.1.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tpl := `password: {{ with lookup "v1" "Secret" .Release.Namespace "db" }}{{ .data.password }}{{ else }}generated{{ end }}`
+	if err := ioutil.WriteFile(filepath.Join(chartDir, "templates", "secret.yaml"), []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tc := &templateCmd{out: &buf, chartPath: chartDir, name: "RELEASE-NAME", namespace: "default", kubeVersion: "1.7"}
+	if err := tc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "password: generated") {
+		t.Errorf("expected lookup to find nothing, got %q", buf.String())
+	}
+
+	buf.Reset()
+	tc.lookup = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		return map[string]interface{}{"data": map[string]interface{}{"password": "c3R1Yg=="}}, nil
+	}
+	if err := tc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "password: c3R1Yg==") {
+		t.Errorf("expected the stubbed lookup result, got %q", buf.String())
+	}
+}
@@ -0,0 +1,3 @@
+{{- define "lib-chart.fullname" -}}
+{{ .Release.Name }}-{{ .Chart.Name }}
+{{- end -}}
@@ -19,16 +19,13 @@ package main
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/storage/driver"
-	"k8s.io/helm/pkg/strvals"
 )
 
 const upgradeDesc = `
@@ -48,14 +45,29 @@ contained a key called 'Test', the value set in override.yaml would take precede
 
 	$ helm upgrade -f myvalues.yaml -f override.yaml redis ./redis
 
+A values file can also be read from stdin with '-f -', or downloaded from a URL
+whose scheme is supported by Helm or by a downloader plugin, like for
+'helm install'.
+
 You can specify the '--set' flag multiple times. The priority will be given to the
 last (right-most) set specified. For example, if both 'bar' and 'newbar' values are
 set for a key called 'foo', the 'newbar' value would take precedence:
 
 	$ helm upgrade --set foo=bar --set foo=newbar redis ./redis
+
+To keep a value as a string, use '--set-string'. To set a value to the contents
+of a file, such as a certificate, use '--set-file'. To set a structured value,
+use '--set-json':
+
+	$ helm upgrade --set-string image.tag=1.10 --set-file tls.cert=./tls.crt --set-json 'resources={"limits":{"cpu":"100m"}}' redis ./redis
+
+The '--set' flags are applied in this order: '--set', '--set-string',
+'--set-file' and '--set-json'.
 `
 
 type upgradeCmd struct {
+	valuesOptions
+
 	release      string
 	chart        string
 	out          io.Writer
@@ -64,8 +76,6 @@ type upgradeCmd struct {
 	recreate     bool
 	force        bool
 	disableHooks bool
-	valueFiles   valueFiles
-	values       []string
 	verify       bool
 	keyring      string
 	install      bool
@@ -76,11 +86,10 @@ type upgradeCmd struct {
 	reuseValues  bool
 	wait         bool
 	repoURL      string
+	username     string
+	password     string
+	plainHTTP    bool
 	devel        bool
-
-	certFile string
-	keyFile  string
-	caFile   string
 }
 
 func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -114,11 +123,10 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.VarP(&upgrade.valueFiles, "values", "f", "specify values in a YAML file (can specify multiple)")
+	upgrade.valuesOptions.addFlags(f)
 	f.BoolVar(&upgrade.dryRun, "dry-run", false, "simulate an upgrade")
 	f.BoolVar(&upgrade.recreate, "recreate-pods", false, "performs pods restart for the resource if applicable")
 	f.BoolVar(&upgrade.force, "force", false, "force resource update through delete/recreate if needed")
-	f.StringArrayVar(&upgrade.values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.BoolVar(&upgrade.disableHooks, "disable-hooks", false, "disable pre/post upgrade hooks. DEPRECATED. Use no-hooks")
 	f.BoolVar(&upgrade.disableHooks, "no-hooks", false, "disable pre/post upgrade hooks")
 	f.BoolVar(&upgrade.verify, "verify", false, "verify the provenance of the chart before upgrading")
@@ -131,9 +139,9 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&upgrade.reuseValues, "reuse-values", false, "when upgrading, reuse the last release's values, and merge in any new values. If '--reset-values' is specified, this is ignored.")
 	f.BoolVar(&upgrade.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&upgrade.repoURL, "repo", "", "chart repository url where to locate the requested chart")
-	f.StringVar(&upgrade.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
-	f.StringVar(&upgrade.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
-	f.StringVar(&upgrade.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.StringVar(&upgrade.username, "username", "", "chart repository username where to locate the requested chart")
+	f.StringVar(&upgrade.password, "password", "", "chart repository password where to locate the requested chart")
+	f.BoolVar(&upgrade.plainHTTP, "plain-http", false, "access the OCI registry of an oci:// chart reference over HTTP instead of HTTPS")
 	f.BoolVar(&upgrade.devel, "devel", false, "use development versions, too. Equivalent to version '>0.0.0-a'. If --version is set, this is ignored.")
 
 	f.MarkDeprecated("disable-hooks", "use --no-hooks instead")
@@ -142,7 +150,7 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (u *upgradeCmd) run() error {
-	chartPath, err := locateChartPath(u.repoURL, u.chart, u.version, u.verify, u.keyring, u.certFile, u.keyFile, u.caFile)
+	chartPath, err := locateChartPath(u.repoURL, u.username, u.password, u.plainHTTP, u.chart, u.version, u.verify, u.keyring, u.certFile, u.keyFile, u.caFile)
 	if err != nil {
 		return err
 	}
@@ -158,19 +166,18 @@ func (u *upgradeCmd) run() error {
 		if err != nil && strings.Contains(err.Error(), driver.ErrReleaseNotFound(u.release).Error()) {
 			fmt.Fprintf(u.out, "Release %q does not exist. Installing it now.\n", u.release)
 			ic := &installCmd{
-				chartPath:    chartPath,
-				client:       u.client,
-				out:          u.out,
-				name:         u.release,
-				valueFiles:   u.valueFiles,
-				dryRun:       u.dryRun,
-				verify:       u.verify,
-				disableHooks: u.disableHooks,
-				keyring:      u.keyring,
-				values:       u.values,
-				namespace:    u.namespace,
-				timeout:      u.timeout,
-				wait:         u.wait,
+				valuesOptions: u.valuesOptions,
+				chartPath:     chartPath,
+				client:        u.client,
+				out:           u.out,
+				name:          u.release,
+				dryRun:        u.dryRun,
+				verify:        u.verify,
+				disableHooks:  u.disableHooks,
+				keyring:       u.keyring,
+				namespace:     u.namespace,
+				timeout:       u.timeout,
+				wait:          u.wait,
 			}
 			return ic.run()
 		}
@@ -225,31 +232,3 @@ func (u *upgradeCmd) run() error {
 
 	return nil
 }
-
-func (u *upgradeCmd) vals() ([]byte, error) {
-	base := map[string]interface{}{}
-
-	// User specified a values files via -f/--values
-	for _, filePath := range u.valueFiles {
-		currentMap := map[string]interface{}{}
-		bytes, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			return []byte{}, err
-		}
-
-		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
-			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
-		}
-		// Merge with the previous map
-		base = mergeValues(base, currentMap)
-	}
-
-	// User specified a value via --set
-	for _, value := range u.values {
-		if err := strvals.ParseInto(value, base); err != nil {
-			return []byte{}, fmt.Errorf("failed parsing --set data: %s", err)
-		}
-	}
-
-	return yaml.Marshal(base)
-}
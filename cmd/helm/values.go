@@ -0,0 +1,148 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/provenance"
+)
+
+var valuesHelp = `
+This command consists of multiple subcommands to manage PGP-encrypted values files.
+
+A values file may be encrypted as a whole, in which case it is an ASCII armored
+PGP message (conventionally named like 'values.prod.yaml.asc'), or per key, in
+which case only selected values are PGP messages, marked with the '!encrypted'
+tag:
+
+    password: !encrypted |
+      -----BEGIN PGP MESSAGE-----
+      ...
+      -----END PGP MESSAGE-----
+
+'helm install', 'helm upgrade', 'helm template' and 'helm adopt' decrypt such
+files with the private keys in --secret-keyring before merging them.
+
+Example usage:
+    $ helm values encrypt -r ops@example.com values.prod.yaml > values.prod.yaml.asc
+    $ helm values encrypt -r ops@example.com --key db.password values.yaml
+    $ helm values decrypt values.prod.yaml.asc
+`
+
+// passphraseFetcher asks for the passphrase of the private keys that decrypt values.
+var passphraseFetcher provenance.PassphraseFetcher = promptUser
+
+// encryptedTag matches encrypted values as they are written by yaml.Marshal, so
+// that they can be marked with the '!encrypted' tag.
+var encryptedTag = regexp.MustCompile(`(:|-) (\|[-+]?\n\s*-----BEGIN PGP MESSAGE-----|"-----BEGIN PGP MESSAGE-----)`)
+
+func newValuesCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "values [FLAGS] encrypt|decrypt [ARGS]",
+		Short: "encrypt and decrypt values files",
+		Long:  valuesHelp,
+	}
+
+	cmd.AddCommand(newValuesEncryptCmd(out))
+	cmd.AddCommand(newValuesDecryptCmd(out))
+
+	return cmd
+}
+
+// defaultSecretKeyring returns the expanded path to the default secret keyring.
+func defaultSecretKeyring() string {
+	return os.ExpandEnv("$HOME/.gnupg/secring.gpg")
+}
+
+// valuesDecrypter decrypts encrypted values files and values.
+//
+// The keyring is only loaded once something needs to be decrypted.
+type valuesDecrypter struct {
+	keyring   string
+	signatory *provenance.Signatory
+}
+
+func (d *valuesDecrypter) decrypt(message []byte) ([]byte, error) {
+	if d.signatory == nil {
+		if d.keyring == "" {
+			return nil, fmt.Errorf("cannot decrypt values: no secret keyring given")
+		}
+		s, err := provenance.NewFromKeyring(d.keyring, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot decrypt values: %s", err)
+		}
+		d.signatory = s
+	}
+	return d.signatory.Decrypt(message, passphraseFetcher)
+}
+
+// decryptFile decrypts data if the whole file is encrypted.
+func (d *valuesDecrypter) decryptFile(data []byte) ([]byte, error) {
+	if !provenance.IsEncrypted(data) {
+		return data, nil
+	}
+	return d.decrypt(data)
+}
+
+// decryptValues replaces the encrypted values in v with their decrypted
+// content, parsed as YAML.
+func (d *valuesDecrypter) decryptValues(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, e := range val {
+			dv, err := d.decryptValues(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", k, err)
+			}
+			val[k] = dv
+		}
+	case []interface{}:
+		for i, e := range val {
+			dv, err := d.decryptValues(e)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %s", i, err)
+			}
+			val[i] = dv
+		}
+	case string:
+		if !provenance.IsEncrypted([]byte(val)) {
+			return val, nil
+		}
+		b, err := d.decrypt([]byte(val))
+		if err != nil {
+			return nil, err
+		}
+		var dv interface{}
+		if err := yaml.Unmarshal(b, &dv); err != nil {
+			return nil, err
+		}
+		return dv, nil
+	}
+	return v, nil
+}
+
+// markEncrypted tags the encrypted values in a marshaled values file with '!encrypted'.
+func markEncrypted(data []byte) []byte {
+	return encryptedTag.ReplaceAll(data, []byte("$1 !encrypted $2"))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/provenance"
+)
+
+const valuesDecryptDesc = `
+This command decrypts a values file with the private keys in --secret-keyring
+and writes the plain text values to standard output.
+
+Both files that are encrypted as a whole and files with encrypted values are
+supported. Passphrase protected keys are prompted for.
+`
+
+type valuesDecryptCmd struct {
+	file          string
+	secretKeyring string
+
+	out io.Writer
+}
+
+func newValuesDecryptCmd(out io.Writer) *cobra.Command {
+	dec := &valuesDecryptCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "decrypt [flags] FILE",
+		Short: "decrypt a values file",
+		Long:  valuesDecryptDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "values file"); err != nil {
+				return err
+			}
+			dec.file = args[0]
+			return dec.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&dec.secretKeyring, "secret-keyring", defaultSecretKeyring(), "keyring containing the private keys that decrypt the values")
+
+	return cmd
+}
+
+func (d *valuesDecryptCmd) run() error {
+	data, err := readValuesFile(d.file, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	dec := &valuesDecrypter{keyring: d.secretKeyring}
+	if provenance.IsEncrypted(data) {
+		out, err := dec.decrypt(data)
+		if err != nil {
+			return err
+		}
+		_, err = d.out.Write(out)
+		return err
+	}
+
+	vals := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &vals); err != nil {
+		return fmt.Errorf("failed to parse %s: %s", d.file, err)
+	}
+	if _, err := dec.decryptValues(vals); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(vals)
+	if err != nil {
+		return err
+	}
+	_, err = d.out.Write(out)
+	return err
+}
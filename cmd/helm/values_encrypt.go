@@ -0,0 +1,146 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/provenance"
+)
+
+const valuesEncryptDesc = `
+This command encrypts a values file for one or more recipients, whose public
+keys are read from --keyring. The result is written to standard output.
+
+By default the whole file is encrypted. With --key, only the values at the
+given dotted paths are encrypted, and the rest of the file stays readable.
+`
+
+type valuesEncryptCmd struct {
+	file       string
+	keyring    string
+	recipients []string
+	keys       []string
+
+	out io.Writer
+}
+
+func newValuesEncryptCmd(out io.Writer) *cobra.Command {
+	enc := &valuesEncryptCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "encrypt [flags] FILE",
+		Short: "encrypt a values file",
+		Long:  valuesEncryptDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "values file"); err != nil {
+				return err
+			}
+			enc.file = args[0]
+			return enc.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&enc.keyring, "keyring", defaultKeyring(), "keyring containing the public keys of the recipients")
+	f.StringArrayVarP(&enc.recipients, "recipient", "r", []string{}, "encrypt for the key matching this name (can specify multiple)")
+	f.StringArrayVar(&enc.keys, "key", []string{}, "encrypt only the value at this dotted path, like db.password (can specify multiple)")
+
+	return cmd
+}
+
+func (e *valuesEncryptCmd) run() error {
+	if len(e.recipients) == 0 {
+		return errors.New("at least one --recipient is required")
+	}
+
+	s, err := provenance.NewFromKeyring(e.keyring, "")
+	if err != nil {
+		return err
+	}
+
+	data, err := readValuesFile(e.file, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	if len(e.keys) == 0 {
+		msg, err := s.Encrypt(data, e.recipients...)
+		if err != nil {
+			return err
+		}
+		_, err = e.out.Write(msg)
+		return err
+	}
+
+	vals := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &vals); err != nil {
+		return fmt.Errorf("failed to parse %s: %s", e.file, err)
+	}
+	for _, key := range e.keys {
+		if err := encryptValue(s, vals, key, e.recipients); err != nil {
+			return err
+		}
+	}
+
+	out, err := yaml.Marshal(vals)
+	if err != nil {
+		return err
+	}
+	_, err = e.out.Write(markEncrypted(out))
+	return err
+}
+
+// encryptValue replaces the value at the dotted path key with a PGP message
+// holding its YAML encoding.
+func encryptValue(s *provenance.Signatory, vals map[string]interface{}, key string, recipients []string) error {
+	parts := strings.Split(key, ".")
+	m := vals
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("no value found for key %q", key)
+		}
+		m = next
+	}
+
+	last := parts[len(parts)-1]
+	v, ok := m[last]
+	if !ok {
+		return fmt.Errorf("no value found for key %q", key)
+	}
+	if str, ok := v.(string); ok && provenance.IsEncrypted([]byte(str)) {
+		return fmt.Errorf("value for key %q is already encrypted", key)
+	}
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	msg, err := s.Encrypt(data, recipients...)
+	if err != nil {
+		return err
+	}
+	m[last] = string(msg)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testValues = `db:
+  password: hunter2
+  port: 5432
+name: web
+`
+
+func encryptValuesFile(t *testing.T, dir string, flags ...string) string {
+	in := filepath.Join(dir, "values.yaml")
+	if err := ioutil.WriteFile(in, []byte(testValues), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	cmd := newValuesEncryptCmd(buf)
+	cmd.ParseFlags(append([]string{"--keyring", "testdata/helm-test-key.pub", "-r", "helm-testing@helm.sh"}, flags...))
+	if err := cmd.RunE(cmd, []string{in}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "values.enc.yaml")
+	if err := ioutil.WriteFile(out, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func decryptValuesFile(t *testing.T, file string) string {
+	buf := bytes.NewBuffer(nil)
+	cmd := newValuesDecryptCmd(buf)
+	cmd.ParseFlags([]string{"--secret-keyring", "testdata/helm-test-key.secret"})
+	if err := cmd.RunE(cmd, []string{file}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestValuesEncryptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := encryptValuesFile(t, dir)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "-----BEGIN PGP MESSAGE-----") {
+		t.Fatalf("Expected an encrypted file, got:\n%s", data)
+	}
+
+	if got := decryptValuesFile(t, file); got != testValues {
+		t.Errorf("Expected:\n%s\nGot:\n%s", testValues, got)
+	}
+
+	opts := &valuesOptions{valueFiles: valueFiles{file}, secretKeyring: "testdata/helm-test-key.secret"}
+	b, err := opts.vals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != testValues {
+		t.Errorf("Expected:\n%s\nGot:\n%s", testValues, b)
+	}
+}
+
+func TestValuesEncryptKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := encryptValuesFile(t, dir, "--key", "db.password", "--key", "db.port")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("Expected db.password to be encrypted, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "name: web") {
+		t.Errorf("Expected name to stay readable, got:\n%s", data)
+	}
+	if n := strings.Count(string(data), "!encrypted"); n != 2 {
+		t.Errorf("Expected 2 values tagged !encrypted, got %d in:\n%s", n, data)
+	}
+
+	if got := decryptValuesFile(t, file); got != testValues {
+		t.Errorf("Expected:\n%s\nGot:\n%s", testValues, got)
+	}
+
+	opts := &valuesOptions{valueFiles: valueFiles{file}, values: []string{"name=api"}, secretKeyring: "testdata/helm-test-key.secret"}
+	b, err := opts.vals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := strings.Replace(testValues, "web", "api", 1)
+	if string(b) != expect {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expect, b)
+	}
+
+	opts = &valuesOptions{valueFiles: valueFiles{file}}
+	if _, err := opts.vals(); err == nil {
+		t.Error("Expected an error without a secret keyring")
+	}
+}
+
+func TestValuesEncryptErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "values.yaml")
+	if err := ioutil.WriteFile(in, []byte(testValues), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		flags  []string
+		expect string
+	}{
+		{
+			name:   "requires a recipient",
+			flags:  []string{"--keyring", "testdata/helm-test-key.pub"},
+			expect: "at least one --recipient is required",
+		},
+		{
+			name:   "requires an existing key",
+			flags:  []string{"--keyring", "testdata/helm-test-key.pub", "-r", "helm-testing", "--key", "db.user"},
+			expect: `no value found for key "db.user"`,
+		},
+	}
+
+	for _, tt := range tests {
+		cmd := newValuesEncryptCmd(ioutil.Discard)
+		cmd.ParseFlags(tt.flags)
+		err := cmd.RunE(cmd, []string{in})
+		if err == nil || err.Error() != tt.expect {
+			t.Errorf("%q: expected error %q, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
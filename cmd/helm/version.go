@@ -45,7 +45,8 @@ Server: &version.Version{SemVer:"v2.0.0", GitCommit:"b0c113dfb9f612a9add796549da
   built, and "dirty" if the binary was built from locally modified code.
 
 To print just the client version, use '--client'. To print just the server version,
-use '--server'.
+use '--server'. Use '-o json' or '-o yaml' to print the versions in a format meant
+for scripts.
 `
 
 type versionCmd struct {
@@ -54,6 +55,7 @@ type versionCmd struct {
 	showClient bool
 	showServer bool
 	short      bool
+	output     outputFormat
 }
 
 func newVersionCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -84,19 +86,24 @@ func newVersionCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVarP(&version.showClient, "client", "c", false, "client version only")
 	f.BoolVarP(&version.showServer, "server", "s", false, "server version only")
 	f.BoolVar(&version.short, "short", false, "print the version number")
+	bindOutputFlag(f, &version.output, "o")
 
 	return cmd
 }
 
 func (v *versionCmd) run() error {
+	res := &versionOutput{}
 
 	if v.showClient {
 		cv := version.GetVersionProto()
-		fmt.Fprintf(v.out, "Client: %s\n", formatVersion(cv, v.short))
+		if !v.output.structured() {
+			fmt.Fprintf(v.out, "Client: %s\n", formatVersion(cv, v.short))
+		}
+		res.Client = toVersionInfoOutput(cv)
 	}
 
 	if !v.showServer {
-		return nil
+		return v.writeStructured(res)
 	}
 
 	resp, err := v.client.GetVersion()
@@ -107,8 +114,19 @@ func (v *versionCmd) run() error {
 		debug("%s", err)
 		return errors.New("cannot connect to Tiller")
 	}
-	fmt.Fprintf(v.out, "Server: %s\n", formatVersion(resp.Version, v.short))
-	return nil
+	if !v.output.structured() {
+		fmt.Fprintf(v.out, "Server: %s\n", formatVersion(resp.Version, v.short))
+	}
+	res.Server = toVersionInfoOutput(resp.Version)
+	return v.writeStructured(res)
+}
+
+// writeStructured writes res if a json or yaml output was asked for.
+func (v *versionCmd) writeStructured(res *versionOutput) error {
+	if !v.output.structured() {
+		return nil
+	}
+	return v.output.write(v.out, res)
 }
 
 func formatVersion(v *pb.Version, short bool) string {
@@ -37,6 +37,8 @@ func TestVersion(t *testing.T) {
 		{"default", true, true, []string{}, false},
 		{"client", true, false, []string{"-c"}, false},
 		{"server", false, true, []string{"-s"}, false},
+		{"json", true, true, []string{"-o", "json"}, false},
+		{"yaml, client", true, false, []string{"-c", "-o", "yaml"}, false},
 	}
 
 	settings.TillerHost = "fake-localhost"
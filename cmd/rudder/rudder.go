@@ -68,11 +68,10 @@ func (r *ReleaseModuleServiceServer) Version(ctx context.Context, in *rudderAPI.
 	}, nil
 }
 
-// InstallRelease creates a release using kubeClient.Create
+// InstallRelease creates a release, with its custom resource definitions first
 func (r *ReleaseModuleServiceServer) InstallRelease(ctx context.Context, in *rudderAPI.InstallReleaseRequest) (*rudderAPI.InstallReleaseResponse, error) {
 	grpclog.Print("install")
-	b := bytes.NewBufferString(in.Release.Manifest)
-	err := kubeClient.Create(in.Release.Namespace, b, 500, false)
+	err := tiller.CreateRelease(in.Release, 500, false, kubeClient)
 	if err != nil {
 		grpclog.Printf("error when creating release: %v", err)
 	}
@@ -90,7 +89,7 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 		return resp, fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)
 	}
 
-	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient)
+	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient, in.DeleteCrds)
 	rel.Manifest = kept
 
 	allErrors := ""
@@ -110,18 +109,14 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 // RollbackRelease rolls back the release
 func (r *ReleaseModuleServiceServer) RollbackRelease(ctx context.Context, in *rudderAPI.RollbackReleaseRequest) (*rudderAPI.RollbackReleaseResponse, error) {
 	grpclog.Print("rollback")
-	c := bytes.NewBufferString(in.Current.Manifest)
-	t := bytes.NewBufferString(in.Target.Manifest)
-	err := kubeClient.Update(in.Target.Namespace, c, t, in.Force, in.Recreate, in.Timeout, in.Wait)
+	err := tiller.UpdateRelease(in.Current, in.Target, in.Force, in.Recreate, in.Timeout, in.Wait, kubeClient)
 	return &rudderAPI.RollbackReleaseResponse{}, err
 }
 
 // UpgradeRelease upgrades manifests using kubernetes client
 func (r *ReleaseModuleServiceServer) UpgradeRelease(ctx context.Context, in *rudderAPI.UpgradeReleaseRequest) (*rudderAPI.UpgradeReleaseResponse, error) {
 	grpclog.Print("upgrade")
-	c := bytes.NewBufferString(in.Current.Manifest)
-	t := bytes.NewBufferString(in.Target.Manifest)
-	err := kubeClient.Update(in.Target.Namespace, c, t, in.Force, in.Recreate, in.Timeout, in.Wait)
+	err := tiller.UpdateRelease(in.Current, in.Target, in.Force, in.Recreate, in.Timeout, in.Wait, kubeClient)
 	// upgrade response object should be changed to include status
 	return &rudderAPI.UpgradeReleaseResponse{}, err
 }
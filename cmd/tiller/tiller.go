@@ -32,6 +32,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
@@ -69,6 +70,12 @@ var (
 	keyFile              = flag.String("tls-key", tlsDefaultsFromEnv("tls-key"), "path to TLS private key file")
 	certFile             = flag.String("tls-cert", tlsDefaultsFromEnv("tls-cert"), "path to TLS certificate file")
 	caCertFile           = flag.String("tls-ca-cert", tlsDefaultsFromEnv("tls-ca-cert"), "trust certificates signed by this CA")
+	applyParallelism     = flag.Int("apply-parallelism", kube.DefaultParallelism, "maximum number of resources of the same kind applied to Kubernetes concurrently")
+	renderTimeout        = flag.Duration("render-timeout", engine.DefaultTimeout, "maximum time to render the templates of a chart. 0 disables the limit")
+	maxTemplateSize      = flag.Int("max-template-size", engine.DefaultMaxTemplateSize, "maximum size in bytes of a rendered template. 0 disables the limit")
+	maxRenderSize        = flag.Int("max-render-size", engine.DefaultMaxOutputSize, "maximum size in bytes of all rendered templates of a chart. 0 disables the limit")
+	maxIncludeDepth      = flag.Int("max-include-depth", engine.DefaultMaxIncludeDepth, "maximum nesting depth of include and tpl calls. 0 disables the limit")
+	disableLookup        = flag.Bool("disable-lookup", false, "make the lookup template function return an empty map instead of reading live objects")
 
 	// rootServer is the root gRPC server.
 	//
@@ -114,8 +121,18 @@ func start() {
 
 	kubeClient := kube.New(nil)
 	kubeClient.Log = newLogger("kube").Printf
+	kubeClient.Parallelism = *applyParallelism
 	env.KubeClient = kubeClient
 
+	if e, ok := env.EngineYard.Get(environment.GoTplEngine); ok {
+		if gotpl, ok := e.(*engine.Engine); ok {
+			gotpl.Timeout = *renderTimeout
+			gotpl.MaxTemplateSize = *maxTemplateSize
+			gotpl.MaxOutputSize = *maxRenderSize
+			gotpl.MaxIncludeDepth = *maxIncludeDepth
+		}
+	}
+
 	if *tlsEnable || *tlsVerify {
 		opts := tlsutil.Options{CertFile: *certFile, KeyFile: *keyFile}
 		if *tlsVerify {
@@ -153,6 +170,7 @@ func start() {
 	go func() {
 		svc := tiller.NewReleaseServer(env, clientset, *remoteReleaseModules)
 		svc.Log = newLogger("tiller").Printf
+		svc.DisableLookup = *disableLookup
 		services.RegisterReleaseServiceServer(rootServer, svc)
 		if err := rootServer.Serve(lstn); err != nil {
 			srvErrCh <- err
@@ -33,6 +33,20 @@ import (
 // This is ApiVersionV1 instead of APIVersionV1 to match the protobuf-generated name.
 const ApiVersionV1 = "v1"
 
+const (
+	// ApplicationChartType is the type of a chart that can be installed. Charts
+	// without a type are application charts.
+	ApplicationChartType = "application"
+	// LibraryChartType is the type of a chart that only provides named templates
+	// to the charts that depend on it.
+	LibraryChartType = "library"
+)
+
+// IsLibraryChart returns true if the chart is a library chart.
+func IsLibraryChart(c *chart.Chart) bool {
+	return c.GetMetadata().GetType() == LibraryChartType
+}
+
 // UnmarshalChartfile takes raw Chart.yaml data and unmarshals it.
 func UnmarshalChartfile(data []byte) (*chart.Metadata, error) {
 	y := &chart.Metadata{}
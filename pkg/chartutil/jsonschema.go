@@ -0,0 +1,102 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// ValidateAgainstSchema checks that coalesced values conform to the JSON
+// schema of the chart, and that the values of every dependency, scoped under
+// its name, conform to the schema of the dependency. Global values are not
+// checked against any schema.
+//
+// Charts without a schema accept any values. Every violation is reported in
+// the returned error, along with the path of the offending value.
+func ValidateAgainstSchema(chrt *chart.Chart, values Values) error {
+	errs := validateAgainstSchema(chrt, values, "")
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("values don't meet the specifications of the schema(s), %d error(s):\n\t%s", len(errs), strings.Join(errs, "\n\t"))
+}
+
+func validateAgainstSchema(chrt *chart.Chart, values Values, prefix string) []string {
+	errs := []string{}
+	if len(chrt.Schema) > 0 {
+		violations, err := ValidateAgainstSingleSchema(ownValues(chrt, values), chrt.Schema)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid schema in chart %s: %s", valuesPath(prefix, ""), chrt.Metadata.Name, err))
+		}
+		for _, v := range violations {
+			errs = append(errs, fmt.Sprintf("%s: %s", valuesPath(prefix, v.Field()), v.Description()))
+		}
+	}
+
+	for _, dep := range chrt.Dependencies {
+		name := dep.Metadata.Name
+		sub, err := values.Table(name)
+		if err != nil {
+			// A dependency without values is validated against an empty table,
+			// so that required fields are still reported.
+			sub = Values{}
+		}
+		errs = append(errs, validateAgainstSchema(dep, sub, prefix+name+".")...)
+	}
+	return errs
+}
+
+// ownValues returns the values of the chart itself. The global values are
+// shared by all charts, and the values of each dependency are checked against
+// the schema of the dependency, so neither is described by the schema of the
+// chart.
+func ownValues(chrt *chart.Chart, values Values) Values {
+	own := Values(copyMap(values))
+	delete(own, GlobalKey)
+	for _, dep := range chrt.Dependencies {
+		delete(own, dep.Metadata.Name)
+	}
+	return own
+}
+
+// ValidateAgainstSingleSchema checks values against a single JSON schema,
+// and returns the violations it finds. An error is returned if the schema
+// cannot be used.
+func ValidateAgainstSingleSchema(values Values, schema []byte) ([]gojsonschema.ResultError, error) {
+	res, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(values.AsMap()))
+	if err != nil {
+		return nil, err
+	}
+	return res.Errors(), nil
+}
+
+// valuesPath joins the path of a table of values with the path of a field in
+// it, as reported by the schema validator.
+func valuesPath(prefix, field string) string {
+	if field == "" || field == "(root)" {
+		if prefix == "" {
+			return "(root)"
+		}
+		return strings.TrimSuffix(prefix, ".")
+	}
+	return prefix + field
+}
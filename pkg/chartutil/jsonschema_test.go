@@ -0,0 +1,168 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "properties": {"tag": {"type": "string"}}
+    }
+  },
+  "additionalProperties": false
+}`
+
+const testSubchartSchema = `{
+  "type": "object",
+  "required": ["port"],
+  "properties": {"port": {"type": "integer"}}
+}`
+
+func schemaChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "parent"},
+		Schema:   []byte(testSchema),
+		Values:   &chart.Config{Raw: "replicaCount: 1\nimage:\n  tag: stable\n"},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "sub"},
+				Schema:   []byte(testSubchartSchema),
+				Values:   &chart.Config{Raw: "port: 80\n"},
+			},
+		},
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		values string
+		errs   []string
+	}{
+		{
+			name:   "defaults",
+			values: "",
+		},
+		{
+			name:   "valid overrides",
+			values: "replicaCount: 3\nsub:\n  port: 8080\n",
+		},
+		{
+			name:   "typo",
+			values: "replicaCont: 3\n",
+			errs:   []string{"(root): Additional property replicaCont is not allowed"},
+		},
+		{
+			name:   "nested type",
+			values: "image:\n  tag: 1\n",
+			errs:   []string{"image.tag: Invalid type"},
+		},
+		{
+			name:   "subchart",
+			values: "sub:\n  port: http\n",
+			errs:   []string{"sub.port: Invalid type"},
+		},
+		{
+			name:   "every violation",
+			values: "replicaCount: 0\nsub:\n  port: http\n",
+			errs:   []string{"replicaCount: Must be greater than or equal to 1", "sub.port: Invalid type"},
+		},
+	}
+
+	for _, tt := range tests {
+		ch := schemaChart()
+		vals, err := CoalesceValues(ch, &chart.Config{Raw: tt.values})
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		err = ValidateAgainstSchema(ch, vals)
+		if len(tt.errs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		for _, e := range tt.errs {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("%s: expected %q to contain %q", tt.name, err, e)
+			}
+		}
+	}
+}
+
+func TestValidateAgainstSchema_MissingSubchartValues(t *testing.T) {
+	ch := schemaChart()
+	ch.Dependencies[0].Values = nil
+
+	vals, err := CoalesceValues(ch, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ValidateAgainstSchema(ch, vals)
+	if err == nil || !strings.Contains(err.Error(), "sub: port is required") {
+		t.Errorf("expected the missing port to be reported, got %v", err)
+	}
+}
+
+func TestValidateAgainstSchema_Globals(t *testing.T) {
+	ch := schemaChart()
+	ch.Dependencies[0].Schema = []byte(`{
+  "type": "object",
+  "properties": {"port": {"type": "integer"}},
+  "additionalProperties": false
+}`)
+
+	vals, err := CoalesceValues(ch, &chart.Config{Raw: "global:\n  region: eu\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAgainstSchema(ch, vals); err != nil {
+		t.Errorf("expected global values to be allowed by strict schemas, got %s", err)
+	}
+}
+
+func TestValidateAgainstSchema_NoSchema(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: "free"}}
+	if err := ValidateAgainstSchema(ch, Values{"anything": "goes"}); err != nil {
+		t.Errorf("expected a chart without a schema to accept any values, got %s", err)
+	}
+}
+
+func TestValidateAgainstSchema_InvalidSchema(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "broken"},
+		Schema:   []byte(`{"type": `),
+	}
+	err := ValidateAgainstSchema(ch, Values{})
+	if err == nil || !strings.Contains(err.Error(), "invalid schema in chart broken") {
+		t.Errorf("expected an invalid schema error, got %v", err)
+	}
+}
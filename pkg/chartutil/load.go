@@ -135,6 +135,8 @@ func LoadFiles(files []*BufferedFile) (*chart.Chart, error) {
 			return c, errors.New("values.toml is illegal as of 2.0.0-alpha.2")
 		} else if f.Name == "values.yaml" {
 			c.Values = &chart.Config{Raw: string(f.Data)}
+		} else if f.Name == SchemafileName {
+			c.Schema = f.Data
 		} else if strings.HasPrefix(f.Name, "templates/") {
 			c.Templates = append(c.Templates, &chart.Template{Name: f.Name, Data: f.Data})
 		} else if strings.HasPrefix(f.Name, "charts/") {
@@ -67,6 +67,12 @@ type Dependency struct {
 	ImportValues []interface{} `json:"import-values"`
 	// Alias usable alias to be used for the chart
 	Alias string `json:"alias"`
+	// Digest is the digest of the chart archive, as listed in the index of its
+	// repository. It is only set in a lock file.
+	Digest string `json:"digest,omitempty"`
+	// Dependencies are the locked dependencies of this chart, which make up the
+	// full dependency graph. They are only set in a lock file.
+	Dependencies []*Dependency `json:"dependencies,omitempty"`
 }
 
 // ErrNoRequirementsFile to detect error condition
@@ -53,6 +53,13 @@ func SaveDir(c *chart.Chart, dest string) error {
 		}
 	}
 
+	// Save values.schema.json
+	if len(c.Schema) > 0 {
+		if err := ioutil.WriteFile(filepath.Join(outdir, SchemafileName), c.Schema, 0755); err != nil {
+			return err
+		}
+	}
+
 	for _, d := range []string{TemplatesDir, ChartsDir} {
 		if err := os.MkdirAll(filepath.Join(outdir, d), 0755); err != nil {
 			return err
@@ -162,6 +169,13 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 		}
 	}
 
+	// Save values.schema.json
+	if len(c.Schema) > 0 {
+		if err := writeToTar(out, base+"/"+SchemafileName, c.Schema); err != nil {
+			return err
+		}
+	}
+
 	// Save templates
 	for _, f := range c.Templates {
 		n := filepath.Join(base, f.Name)
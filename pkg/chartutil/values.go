@@ -348,6 +348,8 @@ func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOp
 // ToRenderValuesCaps composes the struct from the data coming from the Releases, Charts and Values files
 //
 // This takes both ReleaseOptions and Capabilities to merge into the render values.
+// The coalesced values are validated against the schemas of the chart and of
+// its dependencies.
 func ToRenderValuesCaps(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOptions, caps *Capabilities) (Values, error) {
 
 	top := map[string]interface{}{
@@ -370,6 +372,10 @@ func ToRenderValuesCaps(chrt *chart.Chart, chrtVals *chart.Config, options Relea
 		return top, err
 	}
 
+	if err := ValidateAgainstSchema(chrt, vals); err != nil {
+		return top, err
+	}
+
 	top["Values"] = vals
 	return top, nil
 }
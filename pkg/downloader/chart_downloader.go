@@ -28,6 +28,7 @@ import (
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/registry"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/urlutil"
 )
@@ -67,6 +68,17 @@ type ChartDownloader struct {
 	HelmHome helmpath.Home
 	// Getter collection for the operation
 	Getters getter.Providers
+	// Username and Password are the credentials for chart URLs that do not
+	// belong to a configured repository.
+	Username string
+	Password string
+	// RepoURL is the URL of the repository that Username and Password belong
+	// to. They are sent only to its host, or to the host of the chart URL if
+	// it is empty.
+	RepoURL string
+	// Registry is the client for charts in OCI registries. If it is nil, a
+	// client with the default settings is used.
+	Registry *registry.Client
 }
 
 // DownloadTo retrieves a chart. Depending on the settings, it may also download a provenance file.
@@ -80,7 +92,14 @@ type ChartDownloader struct {
 //
 // Returns a string path to the location where the file was downloaded and a verification
 // (if provenance was verified), or an error if something bad happened.
+//
+// A reference of the form 'oci://REGISTRY/REPOSITORY[:TAG]' is pulled from an
+// OCI registry. If it has no tag, the version is used as the tag.
 func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *provenance.Verification, error) {
+	if strings.HasPrefix(ref, registry.Scheme+"://") {
+		return c.downloadFromRegistry(ref, version, dest)
+	}
+
 	u, g, err := c.ResolveChartVersion(ref, version)
 	if err != nil {
 		return "", nil, err
@@ -125,6 +144,52 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 	return destfile, ver, nil
 }
 
+// downloadFromRegistry pulls a chart from an OCI registry into the registry
+// cache, and writes its archive to dest.
+//
+// Charts in OCI registries have no provenance files, so they are never verified.
+func (c *ChartDownloader) downloadFromRegistry(ref, version, dest string) (string, *provenance.Verification, error) {
+	r, err := registry.ParseReference(ref)
+	if err != nil {
+		return "", nil, err
+	}
+	if r.Tag == "" {
+		if version == "" {
+			return "", nil, fmt.Errorf("a version is required for %s", ref)
+		}
+		r.Tag = registry.TagForVersion(version)
+		if err := r.Validate(); err != nil {
+			return "", nil, err
+		}
+	}
+	if c.Verify == VerifyAlways {
+		return "", nil, fmt.Errorf("charts in OCI registries cannot be verified: %s", ref)
+	}
+
+	client := c.Registry
+	if client == nil {
+		client = registry.NewClient()
+	}
+	cache := registry.NewCache(c.HelmHome.Registry())
+	if _, err := client.Pull(r, cache); err != nil {
+		return "", nil, err
+	}
+	md, data, err := cache.ChartArchive(r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := filepath.Base(fmt.Sprintf("%s-%s.tgz", md.Name, md.Version))
+	destfile := filepath.Join(dest, name)
+	if err := ioutil.WriteFile(destfile, data, 0655); err != nil {
+		return destfile, nil, err
+	}
+	if c.Verify == VerifyIfPossible {
+		fmt.Fprintf(c.Out, "WARNING: Verification not found for %s: charts in OCI registries cannot be verified\n", ref)
+	}
+	return destfile, &provenance.Verification{}, nil
+}
+
 // ResolveChartVersion resolves a chart reference to a URL.
 //
 // It returns the URL as well as a preconfigured repo.Getter that can fetch
@@ -165,8 +230,16 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 				if err != nil {
 					return u, nil, err
 				}
-				getter, err := getterConstructor(ref, "", "", "")
-				return u, getter, err
+				credsURL := c.RepoURL
+				if credsURL == "" {
+					credsURL = ref
+				}
+				g, err := getterConstructor(getter.Options{
+					URL:      credsURL,
+					Username: c.Username,
+					Password: c.Password,
+				})
+				return u, g, err
 			}
 			return u, nil, err
 		}
@@ -195,7 +268,7 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 	}
 
 	// Next, we need to load the index, and actually look up the chart.
-	i, err := repo.LoadIndexFile(c.HelmHome.CacheIndex(r.Config.Name))
+	i, err := repo.LoadCachedIndexFile(c.HelmHome.CacheIndex(r.Config.Name))
 	if err != nil {
 		return u, r.Client, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
 	}
@@ -297,7 +370,7 @@ func (c *ChartDownloader) scanReposForURL(u string, rf *repo.RepoFile) (*repo.En
 			return nil, err
 		}
 
-		i, err := repo.LoadIndexFile(c.HelmHome.CacheIndex(r.Config.Name))
+		i, err := repo.LoadCachedIndexFile(c.HelmHome.CacheIndex(r.Config.Name))
 		if err != nil {
 			return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
 		}
@@ -25,9 +25,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/registry"
+	"k8s.io/helm/pkg/registry/registrytest"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/repo/repotest"
 )
@@ -94,7 +97,7 @@ func TestDownload(t *testing.T) {
 		t.Fatal("No http provider found")
 	}
 
-	getter, err := provider.New(srv.URL, "", "", "")
+	getter, err := provider.New(getter.Options{URL: srv.URL})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -129,6 +132,44 @@ func TestDownload(t *testing.T) {
 	}
 }
 
+func TestResolveChartVersion_Credentials(t *testing.T) {
+	var authorized bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, authorized = r.BasicAuth()
+	}))
+	defer srv.Close()
+
+	c := ChartDownloader{
+		HelmHome: helmpath.Home("testdata/helmhome"),
+		Out:      os.Stderr,
+		Getters:  getter.All(environment.EnvSettings{}),
+		Username: "username",
+		Password: "password",
+	}
+	for _, tt := range []struct {
+		repoURL string
+		expect  bool
+	}{
+		{"", true},
+		{srv.URL + "/charts", true},
+		{"http://repo.example.com/charts", false},
+	} {
+		authorized = false
+		c.RepoURL = tt.repoURL
+		ref := srv.URL + "/cdn/alpine-0.1.0.tgz"
+		_, g, err := c.ResolveChartVersion(ref, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g.Get(ref); err != nil {
+			t.Fatal(err)
+		}
+		if authorized != tt.expect {
+			t.Errorf("repository %q: expected credentials sent to be %t, got %t", tt.repoURL, tt.expect, authorized)
+		}
+	}
+}
+
 func TestIsTar(t *testing.T) {
 	tests := map[string]bool{
 		"foo.tgz":           true,
@@ -273,6 +314,111 @@ func TestDownloadTo_VerifyLater(t *testing.T) {
 	}
 }
 
+func TestDownloadTo_Registry(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-downloadto-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	hh := helmpath.Home(tmp)
+
+	srv := registrytest.NewServer()
+	defer srv.Stop()
+
+	// Push the chart from a separate cache, so that it has to be pulled.
+	ch, err := chartutil.Load("testdata/signtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := &registry.Reference{Registry: srv.Host(), Repository: "charts/signtest", Tag: "0.1.0"}
+	cache := registry.NewCache(filepath.Join(tmp, "push"))
+	if _, err := cache.SaveChart(ch, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.NewClient().Push(ref, cache); err != nil {
+		t.Fatal(err)
+	}
+
+	c := ChartDownloader{
+		HelmHome: hh,
+		Out:      ioutil.Discard,
+		Getters:  getter.All(environment.EnvSettings{}),
+	}
+	for _, tt := range []struct{ ref, version string }{
+		{"oci://" + srv.Host() + "/charts/signtest:0.1.0", ""},
+		{"oci://" + srv.Host() + "/charts/signtest", "0.1.0"},
+	} {
+		where, _, err := c.DownloadTo(tt.ref, tt.version, tmp)
+		if err != nil {
+			t.Errorf("%s: %s", tt.ref, err)
+			continue
+		}
+		if expect := filepath.Join(tmp, "signtest-0.1.0.tgz"); where != expect {
+			t.Errorf("Expected download to %s, got %s", expect, where)
+		}
+		if _, err := chartutil.Load(where); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, _, err := c.DownloadTo("oci://"+srv.Host()+"/charts/signtest", "", tmp); err == nil {
+		t.Error("Expected an error for a reference without a version")
+	}
+	c.Verify = VerifyAlways
+	if _, _, err := c.DownloadTo("oci://"+srv.Host()+"/charts/signtest:0.1.0", "", tmp); err == nil {
+		t.Error("Expected an error for verifying a chart from a registry")
+	}
+}
+
+func TestDownloadTo_RegistryAuth(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-downloadto-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	hh := helmpath.Home(tmp)
+
+	srv := registrytest.NewServer()
+	defer srv.Stop()
+	srv.RequireAuth("user", "pass")
+
+	authed := registry.NewClient()
+	authed.Username = "user"
+	authed.Password = "pass"
+
+	ch, err := chartutil.Load("testdata/signtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := &registry.Reference{Registry: srv.Host(), Repository: "charts/signtest", Tag: "0.1.0"}
+	cache := registry.NewCache(filepath.Join(tmp, "push"))
+	if _, err := cache.SaveChart(ch, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authed.Push(ref, cache); err != nil {
+		t.Fatal(err)
+	}
+
+	c := ChartDownloader{
+		HelmHome: hh,
+		Out:      ioutil.Discard,
+		Getters:  getter.All(environment.EnvSettings{}),
+	}
+	chartRef := "oci://" + srv.Host() + "/charts/signtest:0.1.0"
+	if _, _, err := c.DownloadTo(chartRef, "", tmp); err == nil {
+		t.Error("Expected an error for an anonymous pull")
+	}
+
+	c.Registry = authed
+	where, _, err := c.DownloadTo(chartRef, "", tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chartutil.Load(where); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestScanReposForURL(t *testing.T) {
 	hh := helmpath.Home("testdata/helmhome")
 	c := ChartDownloader{
@@ -34,6 +34,7 @@ import (
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/resolver"
 	"k8s.io/helm/pkg/urlutil"
@@ -183,10 +184,56 @@ func (m *Manager) loadChartDir() (*chart.Chart, error) {
 //
 // This returns a lock file, which has all of the requirements normalized to a specific version.
 func (m *Manager) resolve(req *chartutil.Requirements, repoNames map[string]string, hash string) (*chartutil.RequirementsLock, error) {
+	repos, err := m.loadChartRepositories()
+	if err != nil {
+		return nil, err
+	}
 	res := resolver.New(m.ChartPath, m.HelmHome)
+	res.Fetch = func(name, version, repoURL, digest string) (*chart.Chart, error) {
+		return m.fetchDependency(name, version, repoURL, digest, repos)
+	}
 	return res.Resolve(req, repoNames, hash)
 }
 
+// fetchDependency downloads a chart from a repository into the archive cache
+// and loads it, so that its own requirements can be resolved. The archive must
+// have the given digest, if any.
+func (m *Manager) fetchDependency(name, version, repoURL, digest string, repos map[string]*repo.ChartRepository) (*chart.Chart, error) {
+	churl, err := findChartURL(name, version, repoURL, repos)
+	if err != nil {
+		return nil, fmt.Errorf("could not find %s-%s: %s", name, version, err)
+	}
+
+	dest := m.HelmHome.Archive()
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return nil, err
+	}
+	// Chart versions do not change, so a chart that was fetched before is
+	// reused. The archive cache is shared by all repositories though, so the
+	// cached archive must have the digest from the index of this one.
+	if fname := filepath.Join(dest, path.Base(churl)); digest != "" && checkDigest(fname, digest) == nil {
+		return chartutil.LoadFile(fname)
+	}
+
+	if m.Debug {
+		fmt.Fprintf(m.Out, "Fetching %s to resolve its requirements\n", churl)
+	}
+	dl := ChartDownloader{
+		Out:      m.Out,
+		Verify:   VerifyNever,
+		HelmHome: m.HelmHome,
+		Getters:  m.Getters,
+	}
+	fname, _, err := dl.DownloadTo(churl, "", dest)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %s", churl, err)
+	}
+	if err := checkDigest(fname, digest); err != nil {
+		return nil, err
+	}
+	return chartutil.LoadFile(fname)
+}
+
 // downloadAll takes a list of dependencies and downloads them into charts/
 //
 // It will delete versions of the chart that exist on disk and might cause
@@ -216,12 +263,16 @@ func (m *Manager) downloadAll(deps []*chartutil.Dependency) error {
 		return fmt.Errorf("%q is not a directory", destPath)
 	}
 
-	fmt.Fprintf(m.Out, "Saving %d charts\n", len(deps))
+	// Delete all old versions first, so that a chart that is required under
+	// several aliases keeps each of its versions.
 	for _, dep := range deps {
 		if err := m.safeDeleteDep(dep.Name, destPath); err != nil {
 			return err
 		}
+	}
 
+	fmt.Fprintf(m.Out, "Saving %d charts\n", len(deps))
+	for _, dep := range deps {
 		if strings.HasPrefix(dep.Repository, "file://") {
 			if m.Debug {
 				fmt.Fprintf(m.Out, "Archiving %s from repo %s\n", dep.Name, dep.Repository)
@@ -231,6 +282,9 @@ func (m *Manager) downloadAll(deps []*chartutil.Dependency) error {
 				return err
 			}
 			dep.Version = ver
+			if err := m.vendorLockedArchive(filepath.Join(destPath, dep.Name+"-"+ver+".tgz"), dep, repos); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -243,9 +297,105 @@ func (m *Manager) downloadAll(deps []*chartutil.Dependency) error {
 			return fmt.Errorf("could not find %s: %s", churl, err)
 		}
 
-		if _, _, err := dl.DownloadTo(churl, "", destPath); err != nil {
+		fname, _, err := dl.DownloadTo(churl, "", destPath)
+		if err != nil {
 			return fmt.Errorf("could not download %s: %s", churl, err)
 		}
+		if err := checkDigest(fname, dep.Digest); err != nil {
+			return err
+		}
+		if err := m.vendorLockedArchive(fname, dep, repos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vendorLockedArchive makes the charts that the chart archive fname of dep
+// vendors match the versions locked for the dependencies of dep. The archive
+// is only rewritten if it vendors other versions.
+func (m *Manager) vendorLockedArchive(fname string, dep *chartutil.Dependency, repos map[string]*repo.ChartRepository) error {
+	if len(dep.Dependencies) == 0 {
+		return nil
+	}
+	ch, err := chartutil.LoadFile(fname)
+	if err != nil {
+		return err
+	}
+	changed, err := m.vendorLocked(ch, dep.Dependencies, repos)
+	if err != nil || !changed {
+		return err
+	}
+	fmt.Fprintf(m.Out, "Vendoring the locked dependencies of %s\n", dep.Name)
+	saved, err := chartutil.Save(ch, filepath.Dir(fname))
+	if err != nil {
+		return err
+	}
+	if saved != fname {
+		return os.Remove(fname)
+	}
+	return nil
+}
+
+// vendorLocked replaces the charts that ch vendors in its charts/ directory
+// with the versions locked in deps, and does the same for their dependencies
+// in turn. It returns whether ch was changed.
+//
+// Local dependencies of a packaged chart are not locked, so the charts that
+// satisfy them are kept as they are.
+func (m *Manager) vendorLocked(ch *chart.Chart, deps []*chartutil.Dependency, repos map[string]*repo.ChartRepository) (bool, error) {
+	locked := map[string]map[string]bool{}
+	for _, d := range deps {
+		if strings.HasPrefix(d.Repository, "file://") {
+			continue
+		}
+		if locked[d.Name] == nil {
+			locked[d.Name] = map[string]bool{}
+		}
+		locked[d.Name][d.Version] = true
+	}
+
+	// Drop the versions that are not locked.
+	vendored := []*chart.Chart{}
+	for _, sub := range ch.Dependencies {
+		if versions, ok := locked[sub.Metadata.Name]; !ok || versions[sub.Metadata.Version] {
+			vendored = append(vendored, sub)
+		}
+	}
+	changed := len(vendored) != len(ch.Dependencies)
+	ch.Dependencies = vendored
+
+	for _, d := range deps {
+		if locked[d.Name] == nil {
+			continue
+		}
+		sub := findVendored(ch, d.Name, d.Version)
+		if sub == nil {
+			fmt.Fprintf(m.Out, "Vendoring %s-%s into %s\n", d.Name, d.Version, ch.Metadata.Name)
+			var err error
+			sub, err = m.fetchDependency(d.Name, d.Version, d.Repository, d.Digest, repos)
+			if err != nil {
+				return false, err
+			}
+			ch.Dependencies = append(ch.Dependencies, sub)
+			changed = true
+		}
+		subChanged, err := m.vendorLocked(sub, d.Dependencies, repos)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || subChanged
+	}
+	return changed, nil
+}
+
+// findVendored returns the chart that ch vendors for the given name and
+// version, or nil.
+func findVendored(ch *chart.Chart, name, version string) *chart.Chart {
+	for _, sub := range ch.Dependencies {
+		if sub.Metadata.Name == name && sub.Metadata.Version == version {
+			return sub
+		}
 	}
 	return nil
 }
@@ -514,7 +664,7 @@ func (m *Manager) loadChartRepositories() (map[string]*repo.ChartRepository, err
 	for _, re := range rf.Repositories {
 		lname := re.Name
 		cacheindex := m.HelmHome.CacheIndex(lname)
-		index, err := repo.LoadIndexFile(cacheindex)
+		index, err := repo.LoadCachedIndexFile(cacheindex)
 		if err != nil {
 			return indices, err
 		}
@@ -529,6 +679,21 @@ func (m *Manager) loadChartRepositories() (map[string]*repo.ChartRepository, err
 	return indices, nil
 }
 
+// checkDigest checks that the chart archive fname has the given digest, if any.
+func checkDigest(fname, digest string) error {
+	if digest == "" {
+		return nil
+	}
+	sum, err := provenance.DigestFile(fname)
+	if err != nil {
+		return err
+	}
+	if "sha256:"+sum != digest {
+		return fmt.Errorf("%s does not match its digest: expected %s, got sha256:%s", filepath.Base(fname), digest, sum)
+	}
+	return nil
+}
+
 // writeLock writes a lockfile to disk
 func writeLock(chartpath string, lock *chartutil.RequirementsLock) error {
 	data, err := yaml.Marshal(lock)
@@ -17,11 +17,21 @@ package downloader
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/repo/repotest"
 )
 
 func TestVersionEquals(t *testing.T) {
@@ -156,3 +166,141 @@ func TestGetRepoNames(t *testing.T) {
 		}
 	}
 }
+
+func TestFetchDependency(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-fetchdependency-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	srv := repotest.NewServer(filepath.Join(tmp, "srv"))
+	defer srv.Stop()
+	if _, err := srv.CopyCharts("testdata/signtest-0.1.0.tgz"); err != nil {
+		t.Fatal(err)
+	}
+	i := repo.NewIndexFile()
+	i.Add(&chart.Metadata{Name: "signtest", Version: "0.1.0"}, "signtest-0.1.0.tgz", srv.URL(), "")
+	sum, err := provenance.DigestFile("testdata/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hh := helmpath.Home(tmp)
+	addTestingRepo(t, hh, srv.URL(), i)
+	// Another repository has a chart with the same file name in the cache.
+	other, err := chartutil.Save(&chart.Chart{Metadata: &chart.Metadata{Name: "other", Version: "0.1.0"}}, tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(other, filepath.Join(hh.Archive(), "signtest-0.1.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		Out:      bytes.NewBuffer(nil),
+		HelmHome: hh,
+		Getters:  getter.All(environment.EnvSettings{}),
+	}
+	repos, err := m.loadChartRepositories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := m.fetchDependency("signtest", "0.1.0", srv.URL(), "sha256:"+sum, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Metadata.Name != "signtest" {
+		t.Errorf("Expected the signtest chart, got %s", ch.Metadata.Name)
+	}
+
+	_, err = m.fetchDependency("signtest", "0.1.0", srv.URL(), "sha256:0000", repos)
+	if err == nil || !strings.Contains(err.Error(), "does not match its digest") {
+		t.Errorf("Expected a digest mismatch, got %v", err)
+	}
+}
+
+// addTestingRepo adds the repository "testing" with the given index to the
+// helm home hh.
+func addTestingRepo(t *testing.T, hh helmpath.Home, url string, i *repo.IndexFile) {
+	for _, p := range []string{hh.Cache(), hh.Archive()} {
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := i.WriteFile(hh.CacheIndex("testing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rf := repo.NewRepoFile()
+	rf.Add(&repo.Entry{Name: "testing", URL: url, Cache: hh.CacheIndex("testing")})
+	if err := rf.WriteFile(hh.RepositoryFile(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDownloadAll_VendorsLockedVersions(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-downloadall-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	srv := repotest.NewServer(filepath.Join(tmp, "srv"))
+	defer srv.Stop()
+	mariadb := func(version string) *chart.Chart {
+		return &chart.Chart{Metadata: &chart.Metadata{Name: "mariadb", Version: version}}
+	}
+	charts := []*chart.Chart{
+		mariadb("2.0.0"),
+		mariadb("2.1.0"),
+		{
+			Metadata:     &chart.Metadata{Name: "wordpress", Version: "0.1.0"},
+			Dependencies: []*chart.Chart{mariadb("2.0.0")},
+		},
+	}
+	for _, c := range charts {
+		if _, err := chartutil.Save(c, srv.Root()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	i, err := repo.IndexDirectory(srv.Root(), srv.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hh := helmpath.Home(tmp)
+	addTestingRepo(t, hh, srv.URL(), i)
+	chartPath := filepath.Join(tmp, "site")
+	if err := os.MkdirAll(chartPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{
+		Out:       bytes.NewBuffer(nil),
+		ChartPath: chartPath,
+		HelmHome:  hh,
+		Getters:   getter.All(environment.EnvSettings{}),
+	}
+	for _, version := range []string{"2.0.0", "2.1.0"} {
+		sum, err := provenance.DigestFile(filepath.Join(srv.Root(), "mariadb-"+version+".tgz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		deps := []*chartutil.Dependency{{
+			Name:       "wordpress",
+			Version:    "0.1.0",
+			Repository: srv.URL(),
+			Dependencies: []*chartutil.Dependency{
+				{Name: "mariadb", Version: version, Repository: srv.URL(), Digest: "sha256:" + sum},
+			},
+		}}
+		if err := m.downloadAll(deps); err != nil {
+			t.Fatal(err)
+		}
+		ch, err := chartutil.LoadFile(filepath.Join(chartPath, "charts", "wordpress-0.1.0.tgz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ch.Dependencies) != 1 || ch.Dependencies[0].Metadata.Version != version {
+			t.Errorf("Expected wordpress to vendor mariadb %s, got %v", version, ch.Dependencies)
+		}
+	}
+}
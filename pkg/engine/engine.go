@@ -17,12 +17,12 @@ limitations under the License.
 package engine
 
 import (
-	"bytes"
 	"fmt"
 	"path"
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig"
 
@@ -39,8 +39,27 @@ type Engine struct {
 	// a value that was not passed in.
 	Strict           bool
 	CurrentTemplates map[string]renderable
+	// Lookup is called by the 'lookup' function to read live objects. If it
+	// is nil, 'lookup' returns an empty map, as if no object existed.
+	Lookup LookupFunc
+
+	// The following limits bound the resources used by a call to Render.
+	// A zero value disables a limit.
+
+	// Timeout is the maximum wall-clock time of a call to Render.
+	Timeout time.Duration
+	// MaxTemplateSize is the maximum size in bytes of a rendered template.
+	MaxTemplateSize int
+	// MaxOutputSize is the maximum size in bytes of all rendered templates.
+	MaxOutputSize int
+	// MaxIncludeDepth is the maximum nesting depth of 'include' and 'tpl' calls.
+	MaxIncludeDepth int
 }
 
+// LookupFunc returns the live object with the given apiVersion, kind,
+// namespace and name, or an empty map if it does not exist.
+type LookupFunc func(apiVersion, kind, namespace, name string) (map[string]interface{}, error)
+
 // New creates a new Go template Engine instance.
 //
 // The FuncMap is initialized here. You may modify the FuncMap _prior to_ the
@@ -48,10 +67,16 @@ type Engine struct {
 //
 // The FuncMap sets all of the Sprig functions except for those that provide
 // access to the underlying OS (env, expandenv).
+//
+// The render limits are set to their defaults.
 func New() *Engine {
 	f := FuncMap()
 	return &Engine{
-		FuncMap: f,
+		FuncMap:         f,
+		Timeout:         DefaultTimeout,
+		MaxTemplateSize: DefaultMaxTemplateSize,
+		MaxOutputSize:   DefaultMaxOutputSize,
+		MaxIncludeDepth: DefaultMaxIncludeDepth,
 	}
 }
 
@@ -69,6 +94,8 @@ func New() *Engine {
 //	   included in the FuncMap is a placeholder.
 //      - "tpl": This is late-bound in Engine.Render(). The version
 //	   included in the FuncMap is a placeholder.
+//      - "lookup": This is late-bound in Engine.Render(). The version
+//	   included in the FuncMap always returns an empty map.
 func FuncMap() template.FuncMap {
 	f := sprig.TxtFuncMap()
 	delete(f, "env")
@@ -88,6 +115,9 @@ func FuncMap() template.FuncMap {
 		"include":  func(string, interface{}) string { return "not implemented" },
 		"required": func(string, interface{}) interface{} { return "not implemented" },
 		"tpl":      func(string, interface{}) interface{} { return "not implemented" },
+		"lookup": func(string, string, string, string) (map[string]interface{}, error) {
+			return map[string]interface{}{}, nil
+		},
 	}
 
 	for k, v := range extra {
@@ -120,7 +150,7 @@ func (e *Engine) Render(chrt *chart.Chart, values chartutil.Values) (map[string]
 	// Render the charts
 	tmap := allTemplates(chrt, values)
 	e.CurrentTemplates = tmap
-	return e.render(tmap)
+	return e.render(tmap, e.newRenderState())
 }
 
 // renderable is an object that can be rendered.
@@ -133,22 +163,41 @@ type renderable struct {
 	vals chartutil.Values
 	// namespace prefix to the templates of the current chart
 	basePath string
+	// chartScope is the path of the chart whose named templates the template
+	// shares. It is only set for templates of library charts, which share the
+	// named templates of the chart that depends on them.
+	chartScope string
+	// library is true for templates of library charts, which are not rendered.
+	library bool
 }
 
 // alterFuncMap takes the Engine's FuncMap and adds context-specific functions.
 //
-// The resulting FuncMap is only valid for the passed-in template.
-func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
+// The resulting FuncMap is only valid for the templates of the chart at scope
+// and the passed-in render state.
+func (e *Engine) alterFuncMap(sets *templateSets, scope string, state *renderState) template.FuncMap {
 	// Clone the func map because we are adding context-specific functions.
 	var funcMap template.FuncMap = map[string]interface{}{}
 	for k, v := range e.FuncMap {
 		funcMap[k] = v
 	}
 
-	// Add the 'include' function here so we can close over t.
+	// Add the 'include' function here so we can close over the template sets.
 	funcMap["include"] = func(name string, data interface{}) (string, error) {
-		buf := bytes.NewBuffer(nil)
+		t, name, err := sets.resolve(scope, name)
+		if err != nil {
+			return "", err
+		}
+		if err := e.enter(state, "include", name); err != nil {
+			return "", err
+		}
+		defer state.leave()
+
+		buf := e.newLimitedBuffer(state)
 		if err := t.ExecuteTemplate(buf, name, data); err != nil {
+			if state.err != nil {
+				return "", state.err
+			}
 			return "", err
 		}
 		return buf.String(), nil
@@ -166,46 +215,90 @@ func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
 		return val, nil
 	}
 
-	// Add the 'tpl' function here
+	// Add the 'tpl' function here. The template is rendered as part of the
+	// calling chart, so that it sees the same named templates.
 	funcMap["tpl"] = func(tpl string, vals chartutil.Values) (string, error) {
+		if err := e.enter(state, "tpl", tpl); err != nil {
+			return "", err
+		}
+		defer state.leave()
+
 		r := renderable{
-			tpl:  tpl,
-			vals: vals,
+			tpl:      tpl,
+			vals:     vals,
+			basePath: path.Join(scope, "templates"),
 		}
 
 		templates := map[string]renderable{}
 		templates["aaa_template"] = r
 
-		result, err := e.render(templates)
+		result, err := e.render(templates, state)
 		if err != nil {
+			if state.err != nil {
+				return "", state.err
+			}
 			return "", fmt.Errorf("Error during tpl function execution for %q: %s", tpl, err.Error())
 		}
 		return result["aaa_template"], nil
 	}
 
+	// Add the 'lookup' function here, so that it reads from the cluster
+	// only if the Engine was given a LookupFunc
+	funcMap["lookup"] = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		if e.Lookup == nil {
+			return map[string]interface{}{}, nil
+		}
+		obj, err := e.Lookup(apiVersion, kind, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup of %s %s %q in namespace %q failed: %s", apiVersion, kind, name, namespace, err)
+		}
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+		return obj, nil
+	}
+
+	// Check the deadline in every function, so that loops without output stop too.
+	for name, fn := range funcMap {
+		funcMap[name] = state.withDeadline(fn)
+	}
 	return funcMap
 }
 
 // render takes a map of templates/values and renders them.
-func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
-	// Basically, what we do here is start with an empty parent template and then
-	// build up a list of templates -- one for each file. Once all of the templates
-	// have been parsed, we loop through again and execute every template.
+func (e *Engine) render(tpls map[string]renderable, state *renderState) (map[string]string, error) {
+	// Basically, what we do here is start with an empty parent template for
+	// every chart and then build up a list of templates -- one for each file.
+	// Once all of the templates have been parsed, we loop through again and
+	// execute every template.
 	//
 	// The idea with this process is to make it possible for more complex templates
 	// to share common blocks, but to make the entire thing feel like a file-based
-	// template engine.
-	t := template.New("gotpl")
-	if e.Strict {
-		t.Option("missingkey=error")
-	} else {
-		// Not that zero will attempt to add default values for types it knows,
-		// but will still emit <no value> for others. We mitigate that later.
-		t.Option("missingkey=zero")
+	// template engine. Blocks are shared within a chart only, so that two charts
+	// can define templates with the same name.
+	sets := newTemplateSets()
+	parse := func(fname string, r renderable) error {
+		scope := r.scope()
+		t, ok := sets.sets[scope]
+		if !ok {
+			t = template.New("gotpl")
+			if e.Strict {
+				t.Option("missingkey=error")
+			} else {
+				// Not that zero will attempt to add default values for types it knows,
+				// but will still emit <no value> for others. We mitigate that later.
+				t.Option("missingkey=zero")
+			}
+			t.Funcs(e.alterFuncMap(sets, scope, state))
+			sets.sets[scope] = t
+		}
+		if _, err := t.New(fname).Parse(r.tpl); err != nil {
+			return fmt.Errorf("parse error in %q: %s", fname, err)
+		}
+		sets.files[fname] = scope
+		return nil
 	}
 
-	funcMap := e.alterFuncMap(t)
-
 	// We want to parse the templates in a predictable order. The order favors
 	// higher-level (in file system) templates over deeply nested templates.
 	keys := sortTemplates(tpls)
@@ -213,38 +306,39 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 	files := []string{}
 
 	for _, fname := range keys {
-		r := tpls[fname]
-		t = t.New(fname).Funcs(funcMap)
-		if _, err := t.Parse(r.tpl); err != nil {
-			return map[string]string{}, fmt.Errorf("parse error in %q: %s", fname, err)
+		if err := parse(fname, tpls[fname]); err != nil {
+			return map[string]string{}, err
 		}
 		files = append(files, fname)
 	}
 
 	// Adding the engine's currentTemplates to the template context
 	// so they can be referenced in the tpl function
-	for fname, r := range e.CurrentTemplates {
-		if t.Lookup(fname) == nil {
-			t = t.New(fname).Funcs(funcMap)
-			if _, err := t.Parse(r.tpl); err != nil {
-				return map[string]string{}, fmt.Errorf("parse error in %q: %s", fname, err)
+	for _, fname := range sortTemplates(e.CurrentTemplates) {
+		if _, ok := sets.files[fname]; !ok {
+			if err := parse(fname, e.CurrentTemplates[fname]); err != nil {
+				return map[string]string{}, err
 			}
 		}
 	}
 
 	rendered := make(map[string]string, len(files))
-	var buf bytes.Buffer
+	buf := e.newLimitedBuffer(state)
 	for _, file := range files {
 		// Don't render partials. We don't care out the direct output of partials.
 		// They are only included from other templates.
 		if strings.HasPrefix(path.Base(file), "_") {
 			continue
 		}
-		// At render time, add information about the template that is being rendered.
+		// Library charts only provide named templates to other charts.
 		tpl := tpls[file]
+		if tpl.library {
+			continue
+		}
+		// At render time, add information about the template that is being rendered.
 		vals := tpl.vals
 		vals["Template"] = map[string]interface{}{"Name": file, "BasePath": tpls[file].basePath}
-		if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
+		if err := state.execute(sets.sets[tpl.scope()], buf, file, vals); err != nil {
 			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, err)
 		}
 
@@ -255,6 +349,15 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 		data = pollute(data, &tpl)
 		rendered[file] = data
 		buf.Reset()
+
+		// Templates rendered by 'tpl' are counted as part of the template
+		// that calls it.
+		if state.depth == 0 {
+			state.total += len(data)
+			if e.MaxOutputSize > 0 && state.total > e.MaxOutputSize {
+				return map[string]string{}, fmt.Errorf("render error in %q: rendered output exceeds the maximum total size of %d bytes", file, e.MaxOutputSize)
+			}
+		}
 	}
 
 	return rendered, nil
@@ -289,7 +392,7 @@ func (p byPathLen) Less(i, j int) bool {
 // As it goes, it also prepares the values in a scope-sensitive manner.
 func allTemplates(c *chart.Chart, vals chartutil.Values) map[string]renderable {
 	templates := map[string]renderable{}
-	recAllTpls(c, templates, vals, true, "", "")
+	recAllTpls(c, templates, vals, true, "", "", "")
 	return templates
 }
 
@@ -297,7 +400,10 @@ func allTemplates(c *chart.Chart, vals chartutil.Values) map[string]renderable {
 //
 // As it recurses, it also sets the values to be appropriate for the template
 // scope.
-func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals chartutil.Values, top bool, parentPath string, parentID string) {
+//
+// The templates of a library chart share the named templates of the chart at
+// parentScope, which is the nearest chart above it that is not a library.
+func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals chartutil.Values, top bool, parentPath string, parentID string, parentScope string) {
 	// This should never evaluate to a nil map. That will cause problems when
 	// values are appended later.
 	cvals := chartutil.Values{}
@@ -333,15 +439,26 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals char
 		newParentID = path.Join(parentID, "charts", newParentID)
 	}
 
+	library := chartutil.IsLibraryChart(c)
+	scope := newParentID
+	if library && !top {
+		scope = parentScope
+	}
+
 	for _, child := range c.Dependencies {
-		recAllTpls(child, templates, cvals, false, parentPath, newParentID)
+		recAllTpls(child, templates, cvals, false, parentPath, newParentID, scope)
 	}
 	for _, t := range c.Templates {
-		templates[path.Join(newParentID, t.Name)] = renderable{
+		r := renderable{
 			tpl:      string(t.Data),
 			path:     parentPath,
 			vals:     cvals,
 			basePath: path.Join(newParentID, "templates"),
+			library:  library,
+		}
+		if scope != newParentID {
+			r.chartScope = scope
 		}
+		templates[path.Join(newParentID, t.Name)] = r
 	}
 }
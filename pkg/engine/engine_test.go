@@ -18,8 +18,10 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -154,7 +156,7 @@ func TestRenderInternals(t *testing.T) {
 		"three": {tpl: `{{template "two" dict "Value" "three"}}`, vals: vals},
 	}
 
-	out, err := e.render(tpls)
+	out, err := e.render(tpls, e.newRenderState())
 	if err != nil {
 		t.Fatalf("Failed template rendering: %s", err)
 	}
@@ -187,7 +189,7 @@ func TestParallelRenderInternals(t *testing.T) {
 			tt := fmt.Sprintf("expect-%d", i)
 			v := chartutil.Values{"val": tt}
 			tpls := map[string]renderable{fname: {tpl: `{{.val}}`, vals: v}}
-			out, err := e.render(tpls)
+			out, err := e.render(tpls, e.newRenderState())
 			if err != nil {
 				t.Errorf("Failed to render %s: %s", tt, err)
 			}
@@ -234,7 +236,7 @@ func TestAllTemplates(t *testing.T) {
 func TestRenderDependency(t *testing.T) {
 	e := New()
 	deptpl := `{{define "myblock"}}World{{end}}`
-	toptpl := `Hello {{template "myblock"}}`
+	toptpl := `Hello {{include "outerchart/charts/innerchart::myblock" .}}`
 	ch := &chart.Chart{
 		Metadata: &chart.Metadata{Name: "outerchart"},
 		Templates: []*chart.Template{
@@ -265,6 +267,108 @@ func TestRenderDependency(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, out["outer"])
 	}
 
+	// Without the chart, the block of the dependency is out of scope.
+	ch.Templates[0].Data = []byte(`Hello {{include "myblock" .}}`)
+	if _, err := e.Render(ch, map[string]interface{}{}); err == nil {
+		t.Error("Expected an error for a template defined in another chart")
+	} else if !strings.Contains(err.Error(), `no template "myblock" defined in chart "outerchart"`) {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestRenderScopedDefines(t *testing.T) {
+	sub := func(name string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: name},
+			Templates: []*chart.Template{
+				{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}` + name + `-full{{end}}`)},
+				{Name: "templates/name", Data: []byte(`{{include "fullname" .}} {{template "fullname"}}`)},
+			},
+		}
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "top"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}top-full{{end}}`)},
+			{Name: "templates/name", Data: []byte(`{{include "fullname" .}} {{include "top/charts/alpha::fullname" .}} {{include "top/charts/beta/templates/_helpers.tpl" .}}`)},
+			{Name: "templates/tpl", Data: []byte(`{{tpl "{{include \"fullname\" .}}" .}}`)},
+		},
+		Dependencies: []*chart.Chart{sub("alpha"), sub("beta")},
+	}
+
+	out, err := New().Render(ch, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"top/templates/name":              "top-full alpha-full ",
+		"top/templates/tpl":               "top-full",
+		"top/charts/alpha/templates/name": "alpha-full alpha-full",
+		"top/charts/beta/templates/name":  "beta-full beta-full",
+	}
+	for name, want := range expect {
+		if got := out[name]; got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+
+	ch.Templates[1].Data = []byte(`{{include "top/charts/gamma::fullname" .}}`)
+	if _, err := New().Render(ch, map[string]interface{}{}); err == nil {
+		t.Error("Expected an error for a qualified name of a missing chart")
+	}
+}
+
+func TestRenderLibraryChart(t *testing.T) {
+	lib := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "common", Type: "library"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "common.name"}}{{.Chart.Name}}{{end}}{{define "common.fullname"}}{{.Release.Name}}-{{include "common.name" .}}{{end}}`)},
+			{Name: "templates/configmap.yaml", Data: []byte(`kind: ConfigMap`)},
+		},
+	}
+	app := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "common.name"}}application{{end}}`)},
+			{Name: "templates/svc.yaml", Data: []byte(`name: {{include "common.fullname" .}}`)},
+		},
+		Dependencies: []*chart.Chart{lib},
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "top"},
+		Templates: []*chart.Template{
+			{Name: "templates/svc.yaml", Data: []byte(`name: {{include "top/charts/app::common.fullname" .}}`)},
+		},
+		Dependencies: []*chart.Chart{app},
+	}
+
+	vals := map[string]interface{}{
+		"Release": map[string]interface{}{"Name": "rel"},
+		"Chart":   ch.Metadata,
+	}
+	out, err := New().Render(ch, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"top/templates/svc.yaml":            "name: rel-application",
+		"top/charts/app/templates/svc.yaml": "name: rel-application",
+	}
+	for name, want := range expect {
+		if got := out[name]; got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+	if _, ok := out["top/charts/app/charts/common/templates/configmap.yaml"]; ok {
+		t.Error("Expected the templates of a library chart not to be rendered")
+	}
+
+	// The named templates of a library chart are not shared with the charts
+	// above the chart that depends on it.
+	ch.Templates[0].Data = []byte(`{{include "common.fullname" .}}`)
+	if _, err := New().Render(ch, vals); err == nil {
+		t.Error("Expected an error for a template of an indirect library chart")
+	}
 }
 
 func TestRenderNestedValues(t *testing.T) {
@@ -564,3 +668,165 @@ func TestAlterFuncMap(t *testing.T) {
 	}
 
 }
+
+func TestLookup(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby"},
+		Templates: []*chart.Template{
+			{Name: "templates/secret", Data: []byte(`{{ $s := lookup "v1" "Secret" "whales" "moby" }}{{ if $s }}{{ $s.data.password }}{{ else }}generated{{ end }}`)},
+		},
+		Values:       &chart.Config{Raw: ``},
+		Dependencies: []*chart.Chart{},
+	}
+	v := chartutil.Values{
+		"Values": chartutil.Values{},
+		"Chart":  c.Metadata,
+	}
+
+	// Without a LookupFunc, like in 'helm template', no object exists.
+	out, err := New().Render(c, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out["moby/templates/secret"]; got != "generated" {
+		t.Errorf("Expected %q, got %q", "generated", got)
+	}
+
+	e := New()
+	e.Lookup = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		if apiVersion != "v1" || kind != "Secret" || namespace != "whales" || name != "moby" {
+			t.Errorf("Unexpected lookup of %s %s %s/%s", apiVersion, kind, namespace, name)
+		}
+		return map[string]interface{}{
+			"data": map[string]interface{}{"password": "aGFycG9vbg=="},
+		}, nil
+	}
+	out, err = e.Render(c, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out["moby/templates/secret"]; got != "aGFycG9vbg==" {
+		t.Errorf("Expected %q, got %q", "aGFycG9vbg==", got)
+	}
+
+	e.Lookup = func(string, string, string, string) (map[string]interface{}, error) {
+		return nil, fmt.Errorf("the sea is closed")
+	}
+	if _, err := e.Render(c, v); err == nil {
+		t.Error("Expected lookup errors to fail rendering")
+	}
+}
+
+func TestRenderLimits(t *testing.T) {
+	render := func(e *Engine, tpls ...*chart.Template) error {
+		c := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "limits"},
+			Templates: tpls,
+			Values:    &chart.Config{Raw: ``},
+		}
+		_, err := e.Render(c, chartutil.Values{"Values": chartutil.Values{}, "Chart": c.Metadata})
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		engine func(*Engine)
+		tpls   []*chart.Template
+		expect string
+	}{
+		{
+			name: "recursive include",
+			tpls: []*chart.Template{
+				{Name: "templates/loop", Data: []byte(`{{ include "limits/templates/_again" . }}`)},
+				{Name: "templates/_again", Data: []byte(`{{ include "limits/templates/_again" . }}`)},
+			},
+			expect: `render error in "limits/templates/loop": include of "limits/templates/_again" exceeds the maximum nesting depth of 1000`,
+		},
+		{
+			name: "recursive tpl",
+			tpls: []*chart.Template{
+				{Name: "templates/loop", Data: []byte(`{{ define "again" }}{{ tpl "{{ include \"again\" . }}" . }}{{ end }}{{ include "again" . }}`)},
+			},
+			engine: func(e *Engine) { e.MaxIncludeDepth = 10 },
+			expect: `render error in "limits/templates/loop": include of "again" exceeds the maximum nesting depth of 10`,
+		},
+		{
+			name: "large template",
+			tpls: []*chart.Template{
+				{Name: "templates/big", Data: []byte(`{{ range $i, $e := (list 1 2 3) }}0123456789{{ end }}`)},
+			},
+			engine: func(e *Engine) { e.MaxTemplateSize = 15 },
+			expect: `render error in "limits/templates/big": rendered output exceeds the maximum template size of 15 bytes`,
+		},
+		{
+			name: "large output",
+			tpls: []*chart.Template{
+				{Name: "templates/a", Data: []byte(`0123456789`)},
+				{Name: "templates/b", Data: []byte(`0123456789`)},
+			},
+			engine: func(e *Engine) { e.MaxOutputSize = 15 },
+			expect: `rendered output exceeds the maximum total size of 15 bytes`,
+		},
+		{
+			name: "deadline",
+			tpls: []*chart.Template{
+				{Name: "templates/slow", Data: []byte(`{{ range $i, $e := (list 1 2 3) }}{{ include "limits/templates/_slow" . }}{{ end }}`)},
+				{Name: "templates/_slow", Data: []byte(`slow`)},
+			},
+			engine: func(e *Engine) { e.Timeout = time.Nanosecond },
+			expect: `render error in "limits/templates/slow": rendering took longer than 1ns`,
+		},
+		{
+			name: "deadline without output",
+			tpls: []*chart.Template{
+				{Name: "templates/busy", Data: []byte(`{{ range until 10000 }}{{ range until 10000 }}{{ $x := add 1 1 }}{{ end }}{{ end }}`)},
+			},
+			engine: func(e *Engine) { e.Timeout = 10 * time.Millisecond },
+			expect: `render error in "limits/templates/busy": rendering took longer than 10ms`,
+		},
+		{
+			name: "deadline in empty loops",
+			tpls: []*chart.Template{
+				{Name: "templates/idle", Data: []byte(`{{ $x := until 100000 }}{{ range $x }}{{ range $x }}{{ range $x }}{{ end }}{{ end }}{{ end }}`)},
+			},
+			engine: func(e *Engine) { e.Timeout = 10 * time.Millisecond },
+			expect: `render error in "limits/templates/idle": rendering took longer than 10ms`,
+		},
+	}
+
+	for _, tt := range tests {
+		e := New()
+		if tt.engine != nil {
+			tt.engine(e)
+		}
+		err := render(e, tt.tpls...)
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%s: expected error containing %q, got %q", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestRenderLimitsDisabled(t *testing.T) {
+	e := New()
+	e.MaxTemplateSize = 0
+	e.MaxOutputSize = 0
+	e.Timeout = 0
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "limits"},
+		Templates: []*chart.Template{
+			{Name: "templates/big", Data: []byte(`{{ range $i, $e := (list 1 2 3) }}0123456789{{ end }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	out, err := e.Render(c, chartutil.Values{"Values": chartutil.Values{}, "Chart": c.Metadata})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out["limits/templates/big"]; len(got) != 30 {
+		t.Errorf("Expected 30 bytes of output, got %q", got)
+	}
+}
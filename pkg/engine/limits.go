@@ -0,0 +1,181 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default wall-clock time limit of a Render call.
+	DefaultTimeout = time.Minute
+	// DefaultMaxTemplateSize is the default maximum size in bytes of one rendered template.
+	DefaultMaxTemplateSize = 10 << 20
+	// DefaultMaxOutputSize is the default maximum size in bytes of all templates rendered by a Render call.
+	DefaultMaxOutputSize = 50 << 20
+	// DefaultMaxIncludeDepth is the default maximum nesting depth of 'include' and 'tpl'.
+	DefaultMaxIncludeDepth = 1000
+)
+
+// renderState tracks the resources used by one call to Render, including the
+// templates rendered by 'include' and 'tpl'.
+type renderState struct {
+	// deadline is the time at which rendering fails. It is zero if there is no deadline.
+	deadline time.Time
+	timeout  time.Duration
+	// depth is the current nesting depth of 'include' and 'tpl'.
+	depth int
+	// total is the size of the templates rendered so far.
+	total int
+	// err is the first limit that was exceeded. It is reported as is, instead
+	// of the error of every enclosing 'include'.
+	err error
+}
+
+func (e *Engine) newRenderState() *renderState {
+	s := &renderState{timeout: e.Timeout}
+	if e.Timeout > 0 {
+		s.deadline = time.Now().Add(e.Timeout)
+	}
+	return s
+}
+
+// fail records err as the exceeded limit, unless one was recorded before.
+func (s *renderState) fail(err error) error {
+	if s.err == nil {
+		s.err = err
+	}
+	return s.err
+}
+
+func (s *renderState) errTimeout() error {
+	return fmt.Errorf("rendering took longer than %s", s.timeout)
+}
+
+// checkDeadline fails once the deadline of the render has passed.
+func (s *renderState) checkDeadline() error {
+	if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		return s.fail(s.errTimeout())
+	}
+	return nil
+}
+
+// execute executes the template name of t, and returns the exceeded limit
+// instead of the error of the template if there is one.
+//
+// It stops waiting for the template once the deadline of the render has
+// passed, since a loop that calls no function and writes no output cannot be
+// interrupted. The template is abandoned then: it keeps running until its next
+// function call or write, which fail after the deadline, and its output is
+// discarded.
+func (s *renderState) execute(t *template.Template, w io.Writer, name string, data interface{}) error {
+	if s.deadline.IsZero() {
+		return s.executeNow(t, w, name, data)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.executeNow(t, w, name, data)
+	}()
+	timer := time.NewTimer(s.deadline.Sub(time.Now()))
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		// The state belongs to the abandoned template from now on.
+		return s.errTimeout()
+	}
+}
+
+func (s *renderState) executeNow(t *template.Template, w io.Writer, name string, data interface{}) error {
+	err := t.ExecuteTemplate(w, name, data)
+	if err != nil && s.err != nil {
+		return s.err
+	}
+	return err
+}
+
+// withDeadline wraps the template function fn so that calling it fails once
+// the deadline of the render has passed. This stops loops that write no
+// output, which limitedBuffer never sees.
+//
+// Template functions cannot all return an error, so the error is raised as a
+// panic, which text/template turns into an execution error.
+func (s *renderState) withDeadline(fn interface{}) interface{} {
+	v := reflect.ValueOf(fn)
+	if s.deadline.IsZero() || v.Kind() != reflect.Func {
+		return fn
+	}
+	variadic := v.Type().IsVariadic()
+	return reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+		if err := s.checkDeadline(); err != nil {
+			panic(err)
+		}
+		if variadic {
+			return v.CallSlice(args)
+		}
+		return v.Call(args)
+	}).Interface()
+}
+
+// enter increases the nesting depth for an 'include' or 'tpl' call of name.
+// Every successful call must be followed by a call to leave.
+func (e *Engine) enter(s *renderState, fn, name string) error {
+	if err := s.checkDeadline(); err != nil {
+		return err
+	}
+	if e.MaxIncludeDepth > 0 && s.depth >= e.MaxIncludeDepth {
+		return s.fail(fmt.Errorf("%s of %q exceeds the maximum nesting depth of %d", fn, name, e.MaxIncludeDepth))
+	}
+	s.depth++
+	return nil
+}
+
+func (s *renderState) leave() {
+	s.depth--
+}
+
+// limitedBuffer is a buffer that fails writes beyond max bytes, and writes
+// after the deadline of the render.
+type limitedBuffer struct {
+	bytes.Buffer
+	max   int
+	state *renderState
+}
+
+func (e *Engine) newLimitedBuffer(s *renderState) *limitedBuffer {
+	return &limitedBuffer{max: e.MaxTemplateSize, state: s}
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if err := b.state.checkDeadline(); err != nil {
+		return 0, err
+	}
+	if b.max > 0 && b.Len()+len(p) > b.max {
+		return 0, b.state.fail(fmt.Errorf("rendered output exceeds the maximum template size of %d bytes", b.max))
+	}
+	return b.Buffer.Write(p)
+}
+
+// WriteString keeps bytes.Buffer's WriteString from bypassing the limits.
+func (b *limitedBuffer) WriteString(s string) (int, error) {
+	return b.Write([]byte(s))
+}
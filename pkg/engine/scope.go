@@ -0,0 +1,79 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// QualifiedSeparator separates the chart from the template name in a
+// qualified template name, like "mychart/charts/mysql::mysql.fullname".
+//
+// The chart is given by its path in the rendered chart, which is also the
+// prefix of the names of its template files.
+const QualifiedSeparator = "::"
+
+// scope returns the path of the chart whose named templates the template
+// shares, like "mychart/charts/mysql". This is the chart that the template
+// belongs to, unless that is a library chart.
+func (r renderable) scope() string {
+	if r.chartScope != "" {
+		return r.chartScope
+	}
+	return path.Dir(r.basePath)
+}
+
+// templateSets holds a template set per chart, so that the templates defined
+// by one chart do not override those of another.
+type templateSets struct {
+	// sets maps chart paths to their template sets.
+	sets map[string]*template.Template
+	// files maps the names of template files to the path of their chart.
+	files map[string]string
+}
+
+func newTemplateSets() *templateSets {
+	return &templateSets{
+		sets:  map[string]*template.Template{},
+		files: map[string]string{},
+	}
+}
+
+// resolve finds the template set that contains the template name, as called
+// from the chart at scope.
+//
+// A qualified name is looked up in the chart it names. Any other name is looked
+// up in the chart at scope. Template files can also be named by their full path
+// from any chart, since that path already names their chart.
+func (s *templateSets) resolve(scope, name string) (*template.Template, string, error) {
+	if i := strings.Index(name, QualifiedSeparator); i >= 0 {
+		chartPath, def := name[:i], name[i+len(QualifiedSeparator):]
+		if t, ok := s.sets[chartPath]; ok && t.Lookup(def) != nil {
+			return t, def, nil
+		}
+		return nil, "", fmt.Errorf("no template %q defined in chart %q", def, chartPath)
+	}
+	if t, ok := s.sets[scope]; ok && t.Lookup(name) != nil {
+		return t, name, nil
+	}
+	if chartPath, ok := s.files[name]; ok {
+		return s.sets[chartPath], name, nil
+	}
+	return nil, "", fmt.Errorf("no template %q defined in chart %q", name, scope)
+}
@@ -18,7 +18,12 @@ package getter
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"k8s.io/helm/pkg/helm/environment"
 )
@@ -29,9 +34,117 @@ type Getter interface {
 	Get(url string) (*bytes.Buffer, error)
 }
 
+// ConditionalGetter is a Getter that can skip downloading content that has not
+// changed since it was last downloaded.
+type ConditionalGetter interface {
+	Getter
+	// GetIfModified fetches the content at href, unless it still has the
+	// validators v, in which case it returns ErrNotModified. It also returns
+	// the validators of the fetched content.
+	GetIfModified(href string, v Validators) (*bytes.Buffer, Validators, error)
+}
+
+// Pusher is a Getter that can also upload charts to a repository.
+type Pusher interface {
+	Getter
+	// Push uploads the chart archive at chartPath to the repository at
+	// repoURL, along with its provenance file at provPath unless that is empty.
+	Push(repoURL, chartPath, provPath string) error
+}
+
+// Validators identify a version of the content at a URL.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// ErrNotModified indicates that content has not changed since it was last
+// downloaded.
+var ErrNotModified = errors.New("not modified")
+
+// Options are the settings of a getter for the repository or chart at URL.
+//
+// Every setting is optional, and the zero value of a setting selects its
+// default.
+type Options struct {
+	URL string
+	// CertFile and KeyFile are the client certificate presented to the server.
+	CertFile string
+	KeyFile  string
+	// CAFile is the CA bundle the certificate of the server is verified with,
+	// instead of the system roots.
+	CAFile string
+	// InsecureSkipTLSVerify disables verifying the certificate of the server.
+	InsecureSkipTLSVerify bool
+	// Username and Password are sent as basic auth credentials, and Token as
+	// a bearer token, to the host of URL only.
+	Username string
+	Password string
+	Token    string
+
+	// ConnectTimeout limits the time to establish a connection, including the
+	// TLS handshake. It defaults to DefaultConnectTimeout.
+	ConnectTimeout time.Duration
+	// ReadTimeout limits the time to wait for the next data from the server. It
+	// defaults to DefaultReadTimeout.
+	ReadTimeout time.Duration
+	// Proxy is the URL of the proxy to send requests through. Without it, the
+	// proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables.
+	Proxy string
+	// Headers are sent with every request to the host of URL, like the
+	// credentials.
+	Headers http.Header
+	// UserAgent is the User-Agent of requests. It defaults to the Helm version.
+	UserAgent string
+	// Retries is the number of times a failed request is retried. It defaults
+	// to DefaultRetries, and a negative number disables retries.
+	Retries int
+	// RetryBackoff is the time to wait before the first retry, which doubles
+	// with every further retry. It defaults to DefaultRetryBackoff.
+	RetryBackoff time.Duration
+}
+
+const (
+	// DefaultConnectTimeout is the default of Options.ConnectTimeout.
+	DefaultConnectTimeout = 30 * time.Second
+	// DefaultReadTimeout is the default of Options.ReadTimeout.
+	DefaultReadTimeout = 2 * time.Minute
+	// DefaultRetries is the default of Options.Retries.
+	DefaultRetries = 3
+	// DefaultRetryBackoff is the default of Options.RetryBackoff.
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// maxRetryBackoff caps the time to wait before a retry.
+	maxRetryBackoff = 30 * time.Second
+)
+
+// hasCredentials returns true if credentials are set.
+func (o Options) hasCredentials() bool {
+	return o.Username != "" || o.Password != "" || o.Token != ""
+}
+
+// sendsCredentialsTo returns true if the credentials may be sent with a request
+// for href, which is the case only if it has the same host as o.URL.
+func (o Options) sendsCredentialsTo(href string) bool {
+	return o.hasCredentials() && sameHost(o.URL, href)
+}
+
+// sameHost returns true if the URLs a and b have the same host and port.
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Host != "" && strings.EqualFold(ua.Host, ub.Host)
+}
+
 // Constructor is the function for every getter which creates a specific instance
 // according to the configuration
-type Constructor func(URL, CertFile, KeyFile, CAFile string) (Getter, error)
+type Constructor func(Options) (Getter, error)
 
 // Provider represents any getter and the schemes that it supports.
 //
@@ -17,59 +17,307 @@ package getter
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"k8s.io/helm/pkg/tlsutil"
-	"k8s.io/helm/pkg/urlutil"
+	"k8s.io/helm/pkg/version"
 )
 
+// sleep waits between retries. It is replaced in tests.
+var sleep = time.Sleep
+
 //httpGetter is the efault HTTP(/S) backend handler
 type httpGetter struct {
 	client *http.Client
+	opts   Options
 }
 
 //Get performs a Get from repo.Getter and returns the body.
+//
+// Requests that fail with a network error, a 5xx status other than 501, or
+// 429 Too Many Requests are retried with an exponential backoff.
 func (g *httpGetter) Get(href string) (*bytes.Buffer, error) {
-	buf := bytes.NewBuffer(nil)
+	buf, _, err := g.GetIfModified(href, Validators{})
+	return buf, err
+}
+
+// GetIfModified performs a conditional Get with the If-None-Match and
+// If-Modified-Since headers, and returns the body with its ETag and
+// Last-Modified headers.
+func (g *httpGetter) GetIfModified(href string, v Validators) (*bytes.Buffer, Validators, error) {
+	backoff := g.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		buf, newv, wait, err := g.get(href, v)
+		if err == nil || wait < 0 || attempt >= g.opts.Retries {
+			return buf, newv, err
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if wait > maxRetryBackoff {
+			wait = maxRetryBackoff
+		}
+		sleep(wait)
+	}
+}
+
+// get makes one attempt to fetch href. If it fails, wait is the time to wait
+// before retrying, which is zero for the default backoff, or negative if the
+// request is not to be retried.
+func (g *httpGetter) get(href string, v Validators) (buf *bytes.Buffer, newv Validators, wait time.Duration, err error) {
+	buf = bytes.NewBuffer(nil)
 
-	resp, err := g.client.Get(href)
+	req, err := g.newRequest("GET", href, nil)
 	if err != nil {
-		return buf, err
+		return buf, newv, -1, err
 	}
-	if resp.StatusCode != 200 {
-		return buf, fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
 	}
 
-	_, err = io.Copy(buf, resp.Body)
-	resp.Body.Close()
-	return buf, err
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if !isTemporary(err) {
+			wait = -1
+		}
+		return buf, newv, wait, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotModified && v != Validators{}:
+		return buf, v, -1, ErrNotModified
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		return buf, newv, retryAfter(resp), fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return buf, newv, 0, fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
+	default:
+		return buf, newv, -1, fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
+	}
+
+	if _, err = io.Copy(buf, resp.Body); err != nil {
+		return bytes.NewBuffer(nil), newv, 0, err
+	}
+	newv = Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return buf, newv, 0, nil
 }
 
-// newHTTPGetter constructs a valid http/https client as Getter
-func newHTTPGetter(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
-	var client httpGetter
-	if CertFile != "" && KeyFile != "" && CAFile != "" {
-		tlsConf, err := tlsutil.NewClientTLS(CertFile, KeyFile, CAFile)
+// newRequest returns a request for href with the headers and credentials of
+// the getter. Both are sent only if href has the host of the repository.
+func (g *httpGetter) newRequest(method, href string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, href, body)
+	if err != nil {
+		return nil, err
+	}
+	if sameHost(g.opts.URL, href) {
+		for k, v := range g.opts.Headers {
+			req.Header[k] = v
+		}
+	}
+	if g.opts.UserAgent != "" || req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", g.opts.userAgent())
+	}
+	if g.opts.sendsCredentialsTo(href) {
+		if g.opts.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.opts.Token)
+		} else {
+			req.SetBasicAuth(g.opts.Username, g.opts.Password)
+		}
+	}
+	return req, nil
+}
+
+// Push uploads a chart to the API of a repository that is served by
+// 'helm serve', at /api/charts below repoURL.
+//
+// Uploads are not retried, since the repository rejects a chart version that
+// it already has.
+func (g *httpGetter) Push(repoURL, chartPath, provPath string) error {
+	body := bytes.NewBuffer(nil)
+	mw := multipart.NewWriter(body)
+	files := [][2]string{{"chart", chartPath}}
+	if provPath != "" {
+		files = append(files, [2]string{"prov", provPath})
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f[1])
 		if err != nil {
-			return nil, fmt.Errorf("can't create TLS config for client: %s", err.Error())
+			return err
 		}
-		tlsConf.BuildNameToCertificate()
+		fw, err := mw.CreateFormFile(f[0], filepath.Base(f[1]))
+		if err != nil {
+			return err
+		}
+		fw.Write(data)
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
 
-		sni, err := urlutil.ExtractHostname(URL)
+	href := strings.TrimSuffix(repoURL, "/") + "/api/charts"
+	req, err := g.newRequest("POST", href, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+		return fmt.Errorf("Failed to push %s to %s : %s", filepath.Base(chartPath), href, apiErr.Error)
+	}
+	return fmt.Errorf("Failed to push %s to %s : %s", filepath.Base(chartPath), href, resp.Status)
+}
+
+// userAgent returns the User-Agent of requests.
+func (o Options) userAgent() string {
+	if o.UserAgent != "" {
+		return o.UserAgent
+	}
+	return "Helm/" + version.GetVersion()
+}
+
+// isTemporary returns true if err is a network error that may not happen when
+// the request is retried, as opposed to, say, an invalid certificate.
+func isTemporary(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+// retryAfter returns the time to wait that the server asks for in the
+// Retry-After header of resp, or zero if it does not.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// newHTTPGetter constructs a valid http/https client as Getter
+func newHTTPGetter(opts Options) (Getter, error) {
+	if opts.ConnectTimeout == 0 {
+		opts.ConnectTimeout = DefaultConnectTimeout
+	}
+	if opts.ReadTimeout == 0 {
+		opts.ReadTimeout = DefaultReadTimeout
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+
+	tlsConf, err := tlsutil.ClientConfig(tlsutil.Options{
+		CertFile:           opts.CertFile,
+		KeyFile:            opts.KeyFile,
+		CaCertFile:         opts.CAFile,
+		InsecureSkipVerify: opts.InsecureSkipTLSVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't create TLS config for client: %s", err.Error())
+	}
+	// The server name is left empty, so that the transport verifies the
+	// certificate of every server against its own host name, including the
+	// servers that requests are redirected to.
+	tlsConf.BuildNameToCertificate()
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := url.Parse(opts.Proxy)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid proxy URL %q: %s", opts.Proxy, err)
 		}
-		tlsConf.ServerName = sni
+		proxy = http.ProxyURL(u)
+	}
+
+	dialer := &net.Dialer{Timeout: opts.ConnectTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy: proxy,
+		Dial: func(network, addr string) (net.Conn, error) {
+			conn, err := dialer.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &timeoutConn{Conn: conn, timeout: opts.ReadTimeout}, nil
+		},
+		TLSClientConfig:     tlsConf,
+		TLSHandshakeTimeout: opts.ConnectTimeout,
+		IdleConnTimeout:     90 * time.Second,
+	}
 
-		client.client = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConf,
-			},
+	g := &httpGetter{opts: opts}
+	g.client = &http.Client{Transport: transport, CheckRedirect: g.checkRedirect}
+	return g, nil
+}
+
+// timeoutConn is a connection that fails reads that wait longer than timeout
+// for data.
+type timeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *timeoutConn) Read(b []byte) (int, error) {
+	if c.timeout > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Read(b)
+}
+
+// checkRedirect follows up to 10 redirects, like the default HTTP client, but
+// drops the credentials and custom headers when a request is redirected to
+// another host.
+func (g *httpGetter) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if !sameHost(via[0].URL.String(), req.URL.String()) {
+		req.Header.Del("Authorization")
+		for k := range g.opts.Headers {
+			req.Header.Del(k)
 		}
-	} else {
-		client.client = http.DefaultClient
 	}
-	return &client, nil
+	return nil
 }
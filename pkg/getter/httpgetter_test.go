@@ -16,28 +16,37 @@ limitations under the License.
 package getter
 
 import (
+	"compress/gzip"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestHTTPGetter(t *testing.T) {
-	g, err := newHTTPGetter("http://example.com", "", "", "")
+	g, err := newHTTPGetter(Options{URL: "http://example.com"})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	if hg, ok := g.(*httpGetter); !ok {
 		t.Fatal("Expected newHTTPGetter to produce an httpGetter")
-	} else if hg.client != http.DefaultClient {
-		t.Fatal("Expected newHTTPGetter to return a default HTTP client.")
+	} else if hg.client == http.DefaultClient {
+		t.Fatal("Expected newHTTPGetter to return its own HTTP client.")
+	} else if hg.opts.ConnectTimeout != DefaultConnectTimeout || hg.opts.ReadTimeout != DefaultReadTimeout || hg.opts.Retries != DefaultRetries {
+		t.Fatalf("Expected newHTTPGetter to default its options, got %+v", hg.opts)
 	}
 
 	// Test with SSL:
 	cd := "../../testdata"
 	join := filepath.Join
 	ca, pub, priv := join(cd, "ca.pem"), join(cd, "crt.pem"), join(cd, "key.pem")
-	g, err = newHTTPGetter("http://example.com/", pub, priv, ca)
+	g, err = newHTTPGetter(Options{URL: "http://example.com/", CertFile: pub, KeyFile: priv, CAFile: ca})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -45,4 +54,331 @@ func TestHTTPGetter(t *testing.T) {
 	if _, ok := g.(*httpGetter); !ok {
 		t.Fatal("Expected newHTTPGetter to produce an httpGetter")
 	}
+
+	// Each TLS setting can be given on its own.
+	for _, opts := range []Options{
+		{URL: "https://example.com/", CAFile: ca},
+		{URL: "https://example.com/", CertFile: pub, KeyFile: priv},
+		{URL: "https://example.com/", InsecureSkipTLSVerify: true},
+	} {
+		g, err := newHTTPGetter(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tlsConf := g.(*httpGetter).client.Transport.(*http.Transport).TLSClientConfig
+		if (opts.CAFile != "") != (tlsConf.RootCAs != nil) {
+			t.Errorf("Expected RootCAs only with a CA file for %+v", opts)
+		}
+		if (opts.CertFile != "") != (len(tlsConf.Certificates) == 1) {
+			t.Errorf("Expected a client certificate only with a certificate file for %+v", opts)
+		}
+		if opts.InsecureSkipTLSVerify != tlsConf.InsecureSkipVerify {
+			t.Errorf("Expected InsecureSkipVerify to be %t", opts.InsecureSkipTLSVerify)
+		}
+	}
+
+	if _, err := newHTTPGetter(Options{Proxy: "://"}); err == nil {
+		t.Error("Expected an error for an invalid proxy URL")
+	}
+}
+
+func TestHTTPGetterInsecureSkipTLSVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	g, err := newHTTPGetter(Options{URL: srv.URL, Retries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(srv.URL); err == nil {
+		t.Error("Expected an error for an unknown certificate authority")
+	}
+
+	g, err = newHTTPGetter(Options{URL: srv.URL, InsecureSkipTLSVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := g.Get(srv.URL); err != nil {
+		t.Fatal(err)
+	} else if b.String() != "ok" {
+		t.Errorf("Expected %q, got %q", "ok", b.String())
+	}
+}
+
+func TestHTTPGetterHeaders(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	g, err := newHTTPGetter(Options{URL: srv.URL, Headers: http.Header{"X-Team": {"fish"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("X-Team"); got != "fish" {
+		t.Errorf("Expected header X-Team: fish, got %q", got)
+	}
+	if got := header.Get("User-Agent"); !strings.HasPrefix(got, "Helm/") {
+		t.Errorf("Expected a Helm User-Agent, got %q", got)
+	}
+
+	g, err = newHTTPGetter(Options{URL: srv.URL, UserAgent: "starfish/1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("User-Agent"); got != "starfish/1.0" {
+		t.Errorf("Expected User-Agent starfish/1.0, got %q", got)
+	}
+}
+
+func TestHTTPGetterRetries(t *testing.T) {
+	var waits []time.Duration
+	defer func(s func(time.Duration)) { sleep = s }(sleep)
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	var statuses []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := statuses[0]
+		statuses = statuses[1:]
+		if code == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "7")
+		}
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		opts     Options
+		statuses []int
+		fails    bool
+		waits    []time.Duration
+	}{
+		{
+			opts:     Options{RetryBackoff: time.Second},
+			statuses: []int{500, 502, 200},
+			waits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			opts:     Options{RetryBackoff: time.Second},
+			statuses: []int{429, 200},
+			waits:    []time.Duration{7 * time.Second},
+		},
+		{
+			opts:     Options{Retries: 2, RetryBackoff: time.Second},
+			statuses: []int{503, 503, 503},
+			fails:    true,
+			waits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			opts:     Options{},
+			statuses: []int{404},
+			fails:    true,
+		},
+		{
+			opts:     Options{Retries: -1},
+			statuses: []int{500},
+			fails:    true,
+		},
+	}
+	for i, tt := range tests {
+		tt.opts.URL = srv.URL
+		g, err := newHTTPGetter(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		waits, statuses = nil, tt.statuses
+		_, err = g.Get(srv.URL)
+		if tt.fails != (err != nil) {
+			t.Errorf("%d: Expected failure %t, got error %v", i, tt.fails, err)
+		}
+		if len(statuses) != 0 {
+			t.Errorf("%d: Expected %d requests, got %d", i, len(tt.statuses), len(tt.statuses)-len(statuses))
+		}
+		if !reflect.DeepEqual(waits, tt.waits) {
+			t.Errorf("%d: Expected to wait %v, waited %v", i, tt.waits, waits)
+		}
+	}
+}
+
+func TestHTTPGetterReadTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	g, err := newHTTPGetter(Options{URL: srv.URL, ReadTimeout: 50 * time.Millisecond, Retries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(srv.URL); err == nil {
+		t.Error("Expected an error for a server that does not respond")
+	}
+}
+
+func TestHTTPGetterCredentials(t *testing.T) {
+	auth := map[string]string{}
+	credentials := func(r *http.Request) string {
+		return r.Header.Get("Authorization") + r.Header.Get("X-Api-Key")
+	}
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth["other"+r.URL.Path] = credentials(r)
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth[r.URL.Path] = credentials(r)
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/index.yaml", http.StatusFound)
+		case "/elsewhere":
+			http.Redirect(w, r, other.URL+"/index.yaml", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		opts   Options
+		expect string
+	}{
+		{Options{URL: srv.URL, Username: "user", Password: "pass"}, "Basic dXNlcjpwYXNz"},
+		{Options{URL: srv.URL, Token: "token"}, "Bearer token"},
+		{Options{URL: srv.URL, Headers: http.Header{"X-Api-Key": {"secret"}}}, "secret"},
+	}
+	for _, tt := range tests {
+		g, err := newHTTPGetter(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		auth = map[string]string{}
+		for _, p := range []string{"/index.yaml", "/redirect", "/elsewhere"} {
+			if _, err := g.Get(srv.URL + p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := g.Get(other.URL + "/chart.tgz"); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, p := range []string{"/index.yaml", "/redirect", "/elsewhere"} {
+			if auth[p] != tt.expect {
+				t.Errorf("Expected %q for %s, got %q", tt.expect, p, auth[p])
+			}
+		}
+		// Credentials and custom headers are not sent to other hosts, whether
+		// redirected or not.
+		for _, p := range []string{"other/index.yaml", "other/chart.tgz"} {
+			if auth[p] != "" {
+				t.Errorf("Expected no credentials for %s, got %q", p, auth[p])
+			}
+		}
+	}
+}
+
+func TestHTTPGetterIfModified(t *testing.T) {
+	const etag = `"v1"`
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("index"))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	g, err := newHTTPGetter(Options{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cg := g.(ConditionalGetter)
+
+	b, v, err := cg.GetIfModified(srv.URL, Validators{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "index" {
+		t.Errorf("Expected the decompressed body %q, got %q", "index", b.String())
+	}
+	expect := Validators{ETag: etag, LastModified: "Wed, 21 Oct 2015 07:28:00 GMT"}
+	if v != expect {
+		t.Errorf("Expected validators %+v, got %+v", expect, v)
+	}
+
+	if _, _, err := cg.GetIfModified(srv.URL, v); err != ErrNotModified {
+		t.Errorf("Expected ErrNotModified, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestHTTPGetterPush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-push-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chartPath, provPath := filepath.Join(dir, "foo-0.1.0.tgz"), filepath.Join(dir, "foo-0.1.0.tgz.prov")
+	ioutil.WriteFile(chartPath, []byte("chart"), 0644)
+	ioutil.WriteFile(provPath, []byte("prov"), 0644)
+
+	uploads := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, _ := r.BasicAuth(); u != "user" || p != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != "POST" || r.URL.Path != "/charts/api/charts" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		for _, field := range []string{"chart", "prov"} {
+			if f, h, err := r.FormFile(field); err == nil {
+				b, _ := ioutil.ReadAll(f)
+				uploads[h.Filename] = string(b)
+			}
+		}
+		if len(uploads) == 1 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error": "chart foo-0.1.0 already exists"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	g, err := newHTTPGetter(Options{URL: srv.URL + "/charts/", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.(Pusher).Push(srv.URL+"/charts/", chartPath, provPath); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{"foo-0.1.0.tgz": "chart", "foo-0.1.0.tgz.prov": "prov"}
+	if !reflect.DeepEqual(uploads, expect) {
+		t.Errorf("Expected uploads %v, got %v", expect, uploads)
+	}
+
+	uploads = map[string]string{}
+	err = g.(Pusher).Push(srv.URL+"/charts/", chartPath, "")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected the error of the repository, got %v", err)
+	}
 }
@@ -36,14 +36,18 @@ func collectPlugins(settings environment.EnvSettings) (Providers, error) {
 	var result Providers
 	for _, plugin := range plugins {
 		for _, downloader := range plugin.Metadata.Downloaders {
+			newGetter := newPluginGetter(
+				downloader.Command,
+				settings,
+				plugin.Metadata.Name,
+				plugin.Dir,
+			)
+			if downloader.PushCommand != "" {
+				newGetter = newPluginPusher(newGetter, downloader.PushCommand)
+			}
 			result = append(result, Provider{
 				Schemes: downloader.Protocols,
-				New: newPluginGetter(
-					downloader.Command,
-					settings,
-					plugin.Metadata.Name,
-					plugin.Dir,
-				),
+				New:     newGetter,
 			})
 		}
 	}
@@ -53,26 +57,42 @@ func collectPlugins(settings environment.EnvSettings) (Providers, error) {
 // pluginGetter is a generic type to invoke custom downloaders,
 // implemented in plugins.
 type pluginGetter struct {
-	command                   string
-	certFile, keyFile, cAFile string
-	settings                  environment.EnvSettings
-	name                      string
-	base                      string
+	command  string
+	opts     Options
+	settings environment.EnvSettings
+	name     string
+	base     string
 }
 
 // Get runs downloader plugin command
+//
+// The credentials are passed in the HELM_REPO_USERNAME, HELM_REPO_PASSWORD
+// and HELM_REPO_TOKEN environment variables, rather than as arguments, so that
+// they are not visible to other processes.
 func (p *pluginGetter) Get(href string) (*bytes.Buffer, error) {
-	argv := []string{p.certFile, p.keyFile, p.cAFile, href}
-	prog := exec.Command(filepath.Join(p.base, p.command), argv...)
+	return p.run(p.command, href)
+}
+
+// run runs command with the TLS files, href and args as its arguments.
+func (p *pluginGetter) run(command, href string, args ...string) (*bytes.Buffer, error) {
+	argv := append([]string{p.opts.CertFile, p.opts.KeyFile, p.opts.CAFile, href}, args...)
+	prog := exec.Command(filepath.Join(p.base, command), argv...)
 	plugin.SetupPluginEnv(p.settings, p.name, p.base)
 	prog.Env = os.Environ()
+	if p.opts.sendsCredentialsTo(href) {
+		prog.Env = append(prog.Env,
+			"HELM_REPO_USERNAME="+p.opts.Username,
+			"HELM_REPO_PASSWORD="+p.opts.Password,
+			"HELM_REPO_TOKEN="+p.opts.Token,
+		)
+	}
 	buf := bytes.NewBuffer(nil)
 	prog.Stdout = buf
 	prog.Stderr = os.Stderr
 	if err := prog.Run(); err != nil {
 		if eerr, ok := err.(*exec.ExitError); ok {
 			os.Stderr.Write(eerr.Stderr)
-			return nil, fmt.Errorf("plugin %q exited with error", p.command)
+			return nil, fmt.Errorf("plugin %q exited with error", command)
 		}
 		return nil, err
 	}
@@ -81,12 +101,10 @@ func (p *pluginGetter) Get(href string) (*bytes.Buffer, error) {
 
 // newPluginGetter constructs a valid plugin getter
 func newPluginGetter(command string, settings environment.EnvSettings, name, base string) Constructor {
-	return func(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
+	return func(opts Options) (Getter, error) {
 		result := &pluginGetter{
 			command:  command,
-			certFile: CertFile,
-			keyFile:  KeyFile,
-			cAFile:   CAFile,
+			opts:     opts,
 			settings: settings,
 			name:     name,
 			base:     base,
@@ -94,3 +112,32 @@ func newPluginGetter(command string, settings environment.EnvSettings, name, bas
 		return result, nil
 	}
 }
+
+// pluginPusher is a pluginGetter of a plugin that can also push charts.
+type pluginPusher struct {
+	*pluginGetter
+	pushCommand string
+}
+
+// Push runs the push command of the plugin, with the TLS files, the repository
+// URL, the chart archive and the provenance file, if any, as its arguments.
+func (p *pluginPusher) Push(repoURL, chartPath, provPath string) error {
+	args := []string{chartPath}
+	if provPath != "" {
+		args = append(args, provPath)
+	}
+	_, err := p.run(p.pushCommand, repoURL, args...)
+	return err
+}
+
+// newPluginPusher extends the getters of newGetter to push charts with the
+// pushCommand of a plugin.
+func newPluginPusher(newGetter Constructor, pushCommand string) Constructor {
+	return func(opts Options) (Getter, error) {
+		g, err := newGetter(opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pluginPusher{pluginGetter: g.(*pluginGetter), pushCommand: pushCommand}, nil
+	}
+}
@@ -73,7 +73,7 @@ func TestPluginGetter(t *testing.T) {
 
 	env := hh(false)
 	pg := newPluginGetter("echo", env, "test", ".")
-	g, err := pg("test://foo/bar", "", "", "")
+	g, err := pg(Options{URL: "test://foo/bar"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -89,3 +89,74 @@ func TestPluginGetter(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, got)
 	}
 }
+
+func TestPluginGetterCredentials(t *testing.T) {
+	oldhh := os.Getenv("HELM_HOME")
+	defer os.Setenv("HELM_HOME", oldhh)
+	os.Setenv("HELM_HOME", "")
+
+	env := hh(false)
+	pg := newPluginGetter("get.sh", env, "testgetter", "testdata/plugins/testgetter")
+	g, err := pg(Options{URL: "test://foo/index.yaml", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.Get("test://foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"HELM_REPO_USERNAME=user", "HELM_REPO_PASSWORD=pass"} {
+		if !strings.Contains(data.String(), expect) {
+			t.Errorf("Expected %q in the plugin environment", expect)
+		}
+	}
+
+	data, err = g.Get("test://elsewhere/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(data.String(), "HELM_REPO_PASSWORD=pass") {
+		t.Error("Expected no credentials for another host")
+	}
+}
+
+func TestPluginPusher(t *testing.T) {
+	oldhh := os.Getenv("HELM_HOME")
+	defer os.Setenv("HELM_HOME", oldhh)
+	os.Setenv("HELM_HOME", "")
+
+	env := hh(false)
+	p, err := collectPlugins(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newGetter, err := p.ByScheme("test2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := newGetter(Options{URL: "test2://foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pusher, ok := g.(Pusher)
+	if !ok {
+		t.Fatal("Expected the test2 plugin to push charts")
+	}
+	if err := pusher.Push("test2://foo", "foo-0.1.0.tgz", ""); err != nil {
+		t.Error(err)
+	}
+
+	newGetter, err = p.ByScheme("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err = newGetter(Options{URL: "test://foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.(Pusher); ok {
+		t.Error("Expected the test plugin not to push charts")
+	}
+}
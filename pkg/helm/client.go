@@ -283,6 +283,37 @@ func (h *Client) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-ch
 	return h.test(ctx, req)
 }
 
+// AdoptReleaseFromChart brings the existing resources described by a chart
+// under the management of a new release.
+func (h *Client) AdoptReleaseFromChart(rlsName string, chart *chart.Chart, ns string, opts ...AdoptOption) (*rls.AdoptReleaseResponse, error) {
+	// apply the adopt options
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.adoptReq
+	req.Chart = chart
+	req.Name = rlsName
+	req.Namespace = ns
+	req.DryRun = h.opts.dryRun
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	err := chartutil.ProcessRequirementsEnabled(req.Chart, req.Values)
+	if err != nil {
+		return nil, err
+	}
+	err = chartutil.ProcessRequirementsImportValues(req.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.adopt(ctx, req)
+}
+
 // connect returns a grpc connection to tiller or error. The grpc dial options
 // are constructed here.
 func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error) {
@@ -452,3 +483,15 @@ func (h *Client) test(ctx context.Context, req *rls.TestReleaseRequest) (<-chan
 
 	return ch, errc
 }
+
+// Executes tiller.AdoptRelease RPC.
+func (h *Client) adopt(ctx context.Context, req *rls.AdoptReleaseRequest) (*rls.AdoptReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.AdoptRelease(ctx, req)
+}
@@ -86,3 +86,8 @@ func (h Home) Plugins() string {
 func (h Home) Archive() string {
 	return h.Path("cache", "archive")
 }
+
+// Registry returns the path to the local cache of charts from OCI registries.
+func (h Home) Registry() string {
+	return h.Path("cache", "registry")
+}
@@ -35,4 +35,5 @@ func TestHelmHome(t *testing.T) {
 	isEq(t, hh.CacheIndex("t"), "r:\\repository\\cache\\t-index.yaml")
 	isEq(t, hh.Starters(), "r:\\starters")
 	isEq(t, hh.Archive(), "r:\\cache\\archive")
+	isEq(t, hh.Registry(), "r:\\cache\\registry")
 }
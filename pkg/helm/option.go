@@ -78,6 +78,8 @@ type options struct {
 	reuseValues bool
 	// release test options are applied directly to the test release history request
 	testReq rls.TestReleaseRequest
+	// release adopt options are applied directly to the adopt release request
+	adoptReq rls.AdoptReleaseRequest
 }
 
 // Host specifies the host address of the Tiller release server, (default = ":44134").
@@ -271,6 +273,13 @@ func DeletePurge(purge bool) DeleteOption {
 	}
 }
 
+// DeleteCRDs will (if true) also delete the custom resource definitions of the release.
+func DeleteCRDs(deleteCRDs bool) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.DeleteCrds = deleteCRDs
+	}
+}
+
 // InstallDryRun will (if true) execute an installation as a dry run.
 func InstallDryRun(dry bool) InstallOption {
 	return func(opts *options) {
@@ -431,3 +440,21 @@ func NewContext() context.Context {
 // ReleaseTestOption allows configuring optional request data for
 // issuing a TestRelease rpc.
 type ReleaseTestOption func(*options)
+
+// AdoptOption allows configuring optional request data for
+// issuing an AdoptRelease rpc.
+type AdoptOption func(*options)
+
+// AdoptValueOverrides specifies a list of values to include when adopting.
+func AdoptValueOverrides(raw []byte) AdoptOption {
+	return func(opts *options) {
+		opts.adoptReq.Values = &cpb.Config{Raw: string(raw)}
+	}
+}
+
+// AdoptDryRun will report the changes of an adoption without performing it.
+func AdoptDryRun(dry bool) AdoptOption {
+	return func(opts *options) {
+		opts.dryRun = dry
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+// AdoptResult describes a resource of a manifest and its live counterpart.
+type AdoptResult struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Found is true if a live object with the same kind and name exists.
+	Found bool
+	// Diff is a JSON merge patch from the live object to the manifest. It is
+	// empty if the object was not found or does not differ.
+	Diff string
+}
+
+// Adopt reads a manifest from an io.Reader and stamps the release annotations
+// of every resource onto the live object with the same kind and name.
+//
+// Resources that do not exist in the cluster are reported but left alone; they
+// are created by the next update of the release. If any live object is owned by
+// a different release, no object is changed and an error is returned. If dryRun
+// is set, only the differences are reported.
+//
+// Namespace will set the namespace
+func (c *Client) Adopt(namespace string, reader io.Reader, dryRun bool) ([]*AdoptResult, error) {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*AdoptResult{}
+	found := []*resource.Info{}
+	conflicts := []string{}
+	err = perform(infos, func(info *resource.Info) error {
+		owner, live, err := classify(info)
+		if err != nil {
+			return err
+		}
+		res := &AdoptResult{
+			Kind:      owner.Kind,
+			Name:      owner.Name,
+			Namespace: owner.Namespace,
+			Found:     owner.Ownership != Absent,
+		}
+		results = append(results, res)
+
+		switch owner.Ownership {
+		case Absent:
+			c.Log("%s %q does not exist and will be created on upgrade", owner.Kind, info.Name)
+			return nil
+		case OwnedByOtherRelease, OwnedByOtherPath:
+			conflicts = append(conflicts, owner.String())
+			return nil
+		}
+
+		diff, err := diffObjects(live, info.Object)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s %q: %s", owner.Kind, info.Name, err)
+		}
+		res.Diff = diff
+		found = append(found, info)
+		return nil
+	})
+	if err != nil {
+		return results, err
+	}
+	if len(conflicts) > 0 {
+		return results, fmt.Errorf("cannot adopt resources owned elsewhere: %s", strings.Join(conflicts, "; "))
+	}
+	if dryRun {
+		return results, nil
+	}
+
+	for _, info := range found {
+		c.Log("Adopting %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+		if err := stampResource(info); err != nil {
+			return results, fmt.Errorf("failed to adopt %q: %s", info.Name, err)
+		}
+	}
+	return results, nil
+}
+
+// diffObjects returns a JSON merge patch that contains only the fields of target
+// which differ from current.
+func diffObjects(current, target runtime.Object) (string, error) {
+	currentData, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	targetData, err := json.Marshal(target)
+	if err != nil {
+		return "", err
+	}
+	// Overlay the target onto the live object, so that fields populated by the
+	// server (status, uid, ...) do not show up as removals.
+	merged, err := jsonpatch.MergePatch(currentData, targetData)
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch(currentData, merged)
+	if err != nil {
+		return "", err
+	}
+	if string(patch) == "{}" {
+		return "", nil
+	}
+	return string(patch), nil
+}
+
+// templatePaths are the fields of workloads that hold the pod and job templates
+// whose release annotations are stamped along with those of the object.
+var templatePaths = [][]string{
+	{"spec", "template"},
+	{"spec", "jobTemplate"},
+	{"spec", "jobTemplate", "spec", "template"},
+}
+
+// stampResource patches the release annotations of info onto its live object,
+// including those of its pod templates.
+func stampResource(info *resource.Info) error {
+	patch, err := stampPatch(info.Object)
+	if err != nil {
+		return err
+	}
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.MergePatchType, patch)
+	if err != nil {
+		return err
+	}
+	return info.Refresh(obj, true)
+}
+
+// stampPatch returns a JSON merge patch that sets the release annotations of
+// obj and of its templates.
+func stampPatch(obj runtime.Object) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": releaseAnnotationFields(fields)},
+	}
+	for _, path := range templatePaths {
+		template, ok := nestedMap(fields, path)
+		if !ok {
+			continue
+		}
+		annos := releaseAnnotationFields(template)
+		if len(annos) == 0 {
+			continue
+		}
+		dst := patch
+		for _, k := range path {
+			dst = childMap(dst, k)
+		}
+		childMap(dst, "metadata")["annotations"] = annos
+	}
+	return json.Marshal(patch)
+}
+
+// releaseAnnotationFields returns the release annotations of the object fields.
+func releaseAnnotationFields(fields map[string]interface{}) map[string]interface{} {
+	annos := map[string]interface{}{}
+	metadata, _ := fields["metadata"].(map[string]interface{})
+	all, _ := metadata["annotations"].(map[string]interface{})
+	for _, key := range []releaseutil.AnnotationKey{releaseutil.DefaultPathKey, releaseutil.DefaultNamespaceKey, releaseutil.DefaultReleaseKey} {
+		if v, ok := all[string(key)]; ok {
+			annos[string(key)] = v
+		}
+	}
+	return annos
+}
+
+// nestedMap returns the map at path in fields.
+func nestedMap(fields map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	for _, k := range path {
+		next, ok := fields[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		fields = next
+	}
+	return fields, true
+}
+
+// childMap returns the map at key k in m, adding an empty one if it is missing.
+func childMap(m map[string]interface{}, k string) map[string]interface{} {
+	child, ok := m[k].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		m[k] = child
+	}
+	return child
+}
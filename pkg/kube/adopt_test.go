@@ -0,0 +1,153 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestAdopt(t *testing.T) {
+	target := newPodList("starfish", "otter")
+	for i := range target.Items {
+		target.Items[i].Annotations = map[string]string{
+			"helm.sh/release":   "sea",
+			"helm.sh/namespace": "default",
+			"helm.sh/path":      "ocean",
+		}
+	}
+	live := newPod("starfish")
+
+	tests := []struct {
+		name    string
+		dryRun  bool
+		owner   string
+		patched bool
+		err     bool
+	}{
+		{"adopt unmanaged", false, "", true, false},
+		{"dry run", true, "", false, false},
+		{"adopt owned", false, "sea", true, false},
+		{"conflict", false, "lake", false, true},
+	}
+
+	for _, tt := range tests {
+		patched := false
+		live.Annotations = nil
+		if tt.owner != "" {
+			live.Annotations = map[string]string{
+				"helm.sh/release":   tt.owner,
+				"helm.sh/namespace": "default",
+				"helm.sh/path":      "ocean",
+			}
+		}
+
+		f, tf, codec, _ := cmdtesting.NewAPIFactory()
+		tf.UnstructuredClient = &fake.RESTClient{
+			APIRegistry:          api.Registry,
+			NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				p, m := req.URL.Path, req.Method
+				switch {
+				case p == "/namespaces/default/pods/starfish" && m == "GET":
+					return newResponse(200, &live)
+				case p == "/namespaces/default/pods/otter" && m == "GET":
+					return newResponse(404, notFoundBody())
+				case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+					data, err := ioutil.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("could not dump request: %s", err)
+					}
+					req.Body.Close()
+					if !strings.Contains(string(data), `"helm.sh/release":"sea"`) {
+						t.Errorf("%s: expected release annotation in patch, got %s", tt.name, string(data))
+					}
+					patched = true
+					return newResponse(200, &live)
+				default:
+					t.Fatalf("%s: unexpected request: %s %s", tt.name, req.Method, req.URL.Path)
+					return nil, nil
+				}
+			}),
+		}
+
+		c := newTestClient(f)
+		results, err := c.Adopt(api.NamespaceDefault, objBody(codec, &target), tt.dryRun)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.err, err)
+		}
+		if patched != tt.patched {
+			t.Errorf("%s: expected patched %t, got %t", tt.name, tt.patched, patched)
+		}
+		if len(results) != 2 {
+			t.Fatalf("%s: expected 2 results, got %d", tt.name, len(results))
+		}
+		if !results[0].Found || results[1].Found {
+			t.Errorf("%s: expected only starfish to be found, got %+v, %+v", tt.name, results[0], results[1])
+		}
+	}
+}
+
+func TestStampPatch(t *testing.T) {
+	annos := map[string]interface{}{
+		"helm.sh/release":   "sea",
+		"helm.sh/namespace": "default",
+		"helm.sh/path":      "ocean",
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "extensions/v1beta1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":        "starfish",
+			"annotations": map[string]interface{}{"helm.sh/release": "sea", "helm.sh/namespace": "default", "helm.sh/path": "ocean", "other": "x"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": 1,
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": annos},
+			},
+		},
+	}}
+
+	data, err := stampPatch(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annos},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": annos},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(expect) {
+		t.Errorf("Expected patch %s, got %s", expect, data)
+	}
+}
@@ -63,6 +63,13 @@ type Client struct {
 	SchemaCacheDir string
 
 	Log func(string, ...interface{})
+
+	// Parallelism is the maximum number of resources of the same kind that are
+	// created, updated or deleted concurrently.
+	Parallelism int
+
+	// schemas caches the swagger schemas used by Validate.
+	schemas schemaCache
 }
 
 // New create a new Client
@@ -71,6 +78,7 @@ func New(config clientcmd.ClientConfig) *Client {
 		Factory:        cmdutil.NewFactory(config),
 		SchemaCacheDir: clientcmd.RecommendedSchemaFile,
 		Log:            func(_ string, _ ...interface{}) {},
+		Parallelism:    DefaultParallelism,
 	}
 }
 
@@ -94,7 +102,7 @@ func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shoul
 		return buildErr
 	}
 	c.Log("creating %d resource(s)", len(infos))
-	if err := perform(infos, createResource); err != nil {
+	if err := c.performParallel(infos, createResource, false); err != nil {
 		return err
 	}
 	if shouldWait {
@@ -227,14 +235,10 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 		return fmt.Errorf("failed decoding reader into objects: %s", err)
 	}
 
-	updateErrors := []string{}
-
+	// Every kind is tried even if an earlier one fails, so that all update
+	// errors are reported together.
 	c.Log("checking %d resources for changes", len(target))
-	err = target.Visit(func(info *resource.Info, err error) error {
-		if err != nil {
-			return err
-		}
-
+	err = c.performParallel(target, func(info *resource.Info) error {
 		helper := resource.NewHelper(info.Client, info.Mapping)
 		if _, err := helper.Get(info.Namespace, info.Name, info.Export); err != nil {
 			if !errors.IsNotFound(err) {
@@ -258,25 +262,24 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 
 		if err := updateResource(c, info, originalInfo.Object, force, recreate); err != nil {
 			c.Log("error updating the resource %q:\n\t %v", info.Name, err)
-			updateErrors = append(updateErrors, err.Error())
+			return err
 		}
 
 		return nil
-	})
-
-	switch {
-	case err != nil:
+	}, true)
+	if err != nil && err != ErrNoObjectsVisited {
 		return err
-	case len(updateErrors) != 0:
-		return fmt.Errorf(strings.Join(updateErrors, " && "))
 	}
 
-	for _, info := range original.Difference(target) {
+	// Failed deletions are only logged, so the error is always nil or
+	// ErrNoObjectsVisited.
+	c.performParallel(original.Difference(target), func(info *resource.Info) error {
 		c.Log("Deleting %q in %s...", info.Name, info.Namespace)
 		if err := deleteResource(c, info); err != nil {
 			c.Log("Failed to delete %q, err: %s", info.Name, err)
 		}
-	}
+		return nil
+	}, true)
 	if shouldWait {
 		return c.waitForResources(time.Duration(timeout)*time.Second, target)
 	}
@@ -291,11 +294,11 @@ func (c *Client) Delete(namespace string, reader io.Reader) error {
 	if err != nil {
 		return err
 	}
-	return perform(infos, func(info *resource.Info) error {
+	return c.performParallel(infos, func(info *resource.Info) error {
 		c.Log("Starting delete for %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
 		err := deleteResource(c, info)
 		return c.skipIfNotFound(err)
-	})
+	}, true)
 }
 
 func (c *Client) skipIfNotFound(err error) error {
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// CRDKind is the kind of custom resource definitions.
+const CRDKind = "CustomResourceDefinition"
+
+// WaitForCRDs waits until every custom resource definition in reader is
+// established, then drops the cached discovery information of the client so
+// that resources of the new kinds can be built.
+func (c *Client) WaitForCRDs(reader io.Reader, timeout int64) error {
+	infos, err := c.BuildUnstructured("", reader)
+	if err != nil {
+		return err
+	}
+	err = perform(infos, func(info *resource.Info) error {
+		if info.Mapping.GroupVersionKind.Kind != CRDKind {
+			return nil
+		}
+		c.Log("waiting for CustomResourceDefinition %q to be established", info.Name)
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		return wait.PollImmediate(time.Second, time.Duration(timeout)*time.Second, func() (bool, error) {
+			obj, err := helper.Get(info.Namespace, info.Name, info.Export)
+			if err != nil {
+				return false, err
+			}
+			return crdEstablished(obj), nil
+		})
+	})
+	if err != nil && err != ErrNoObjectsVisited {
+		return err
+	}
+
+	dc, err := c.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+	dc.Invalidate()
+	return nil
+}
+
+// crdEstablished reports whether the Established condition of a custom
+// resource definition is true.
+func crdEstablished(obj runtime.Object) bool {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	status, _ := u.Object["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		condition, _ := c.(map[string]interface{})
+		if condition["type"] == "Established" {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
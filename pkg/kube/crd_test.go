@@ -0,0 +1,54 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestCRDEstablished(t *testing.T) {
+	crd := func(conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":   CRDKind,
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	condition := func(kind, status string) map[string]interface{} {
+		return map[string]interface{}{"type": kind, "status": status}
+	}
+
+	tests := []struct {
+		name   string
+		obj    runtime.Object
+		expect bool
+	}{
+		{"no status", &unstructured.Unstructured{Object: map[string]interface{}{"kind": CRDKind}}, false},
+		{"names accepted", crd(condition("NamesAccepted", "True")), false},
+		{"established", crd(condition("NamesAccepted", "True"), condition("Established", "True")), true},
+		{"not established", crd(condition("Established", "False")), false},
+		{"typed object", &api.Pod{}, false},
+	}
+	for _, tt := range tests {
+		if got := crdEstablished(tt.obj); got != tt.expect {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// Lookup returns the live object with the given apiVersion, kind, namespace
+// and name as a map, as it would be serialized to JSON.
+//
+// An empty map is returned if the object does not exist. The namespace is
+// ignored for kinds that are not namespaced.
+func (c *Client) Lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapper, _, err := c.UnstructuredObject()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	client, err := c.UnstructuredClientForMapping(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := resource.NewHelper(client, mapping).Get(namespace, name, false)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+
+	data, err := json.Marshal(live)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	return obj, json.Unmarshal(data, &obj)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestLookup(t *testing.T) {
+	starfish := newPod("starfish")
+	starfish.Annotations = map[string]string{"color": "orange"}
+
+	f, tf, _, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &starfish)
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(404, notFoundBody())
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := newTestClient(f)
+	obj, err := c.Lookup("v1", "Pod", api.NamespaceDefault, "starfish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected metadata in %v", obj)
+	}
+	if metadata["name"] != "starfish" {
+		t.Errorf("expected name starfish, got %v", metadata["name"])
+	}
+	if a, _ := metadata["annotations"].(map[string]interface{}); a["color"] != "orange" {
+		t.Errorf("expected annotation color=orange, got %v", metadata["annotations"])
+	}
+
+	obj, err = c.Lookup("v1", "Pod", api.NamespaceDefault, "otter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obj) != 0 {
+		t.Errorf("expected an empty map for a missing object, got %v", obj)
+	}
+
+	if _, err := c.Lookup("v1", "Starfish", api.NamespaceDefault, "otter"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+// Ownership describes how a live object relates to the release of a manifest.
+type Ownership int
+
+const (
+	// Absent means that no live object exists.
+	Absent Ownership = iota
+	// OwnedByRelease means that the live object belongs to the release of the manifest.
+	OwnedByRelease
+	// OwnedByOtherRelease means that the live object belongs to a different release.
+	OwnedByOtherRelease
+	// Unmanaged means that the live object carries no release annotations.
+	Unmanaged
+	// OwnedByOtherPath means that the live object belongs to the release of the
+	// manifest, but to another chart path, for instance because two templates
+	// render the same object.
+	OwnedByOtherPath
+)
+
+var ownershipNames = map[Ownership]string{
+	Absent:              "absent",
+	OwnedByRelease:      "owned by this release",
+	OwnedByOtherRelease: "owned by another release",
+	Unmanaged:           "unmanaged",
+	OwnedByOtherPath:    "owned by another chart path of this release",
+}
+
+func (o Ownership) String() string {
+	return ownershipNames[o]
+}
+
+// OwnershipResult is the ownership of the live counterpart of a resource.
+type OwnershipResult struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Ownership Ownership
+	// Owner is the release annotated on the live object, if any.
+	Owner string
+	// Path is the chart path annotated on the live object, if it is owned by
+	// another chart path.
+	Path string
+}
+
+// String describes the result in a human readable way.
+func (r *OwnershipResult) String() string {
+	switch r.Ownership {
+	case OwnedByOtherRelease:
+		return fmt.Sprintf("%s %q is owned by release %q", r.Kind, r.Name, r.Owner)
+	case OwnedByOtherPath:
+		return fmt.Sprintf("%s %q is already rendered from chart path %q of release %q", r.Kind, r.Name, r.Path, r.Owner)
+	}
+	return fmt.Sprintf("%s %q is %s", r.Kind, r.Name, r.Ownership)
+}
+
+// Ownership reads a manifest from an io.Reader and classifies the live object
+// with the same kind and name of every resource in it.
+//
+// Namespace will set the namespace
+func (c *Client) Ownership(namespace string, reader io.Reader) ([]*OwnershipResult, error) {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+	results := []*OwnershipResult{}
+	err = perform(infos, func(info *resource.Info) error {
+		res, _, err := classify(info)
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+		return nil
+	})
+	return results, err
+}
+
+// classify looks up the live object of info and compares its release
+// annotations with the ones of info. The live object is returned if it exists.
+func classify(info *resource.Info) (*OwnershipResult, runtime.Object, error) {
+	res := &OwnershipResult{
+		Kind:      info.Mapping.GroupVersionKind.Kind,
+		Name:      info.Name,
+		Namespace: info.Namespace,
+	}
+	live, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, info.Export)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			res.Ownership = Absent
+			return res, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	switch {
+	case !releaseutil.Managed(live):
+		res.Ownership = Unmanaged
+	case releaseutil.MatchRelease(info.Object, live):
+		res.Ownership = OwnedByRelease
+	default:
+		annos := releaseutil.ReleaseAnnotations(live)
+		res.Ownership = OwnedByOtherRelease
+		res.Owner = annos[releaseutil.DefaultReleaseKey]
+		if sameRelease(releaseutil.ReleaseAnnotations(info.Object), annos) {
+			res.Ownership = OwnedByOtherPath
+			res.Path = annos[releaseutil.DefaultPathKey]
+		}
+	}
+	return res, live, nil
+}
+
+// sameRelease returns true if the release annotations a and b name the same
+// release and namespace, whatever their chart paths.
+func sameRelease(a, b map[releaseutil.AnnotationKey]string) bool {
+	return a[releaseutil.DefaultReleaseKey] == b[releaseutil.DefaultReleaseKey] &&
+		a[releaseutil.DefaultNamespaceKey] == b[releaseutil.DefaultNamespaceKey]
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestOwnership(t *testing.T) {
+	annotations := func(release string) map[string]string {
+		return map[string]string{
+			"helm.sh/release":   release,
+			"helm.sh/namespace": "default",
+			"helm.sh/path":      "ocean",
+		}
+	}
+
+	target := newPodList("starfish", "otter", "squid", "eel", "crab")
+	for i := range target.Items {
+		target.Items[i].Annotations = annotations("sea")
+	}
+	starfish, squid, eel, crab := newPod("starfish"), newPod("squid"), newPod("eel"), newPod("crab")
+	starfish.Annotations = annotations("sea")
+	squid.Annotations = annotations("lake")
+	crab.Annotations = annotations("sea")
+	crab.Annotations["helm.sh/path"] = "ocean/reef"
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &starfish)
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(200, &squid)
+			case p == "/namespaces/default/pods/eel" && m == "GET":
+				return newResponse(200, &eel)
+			case p == "/namespaces/default/pods/crab" && m == "GET":
+				return newResponse(200, &crab)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := newTestClient(f)
+	results, err := c.Ownership(api.NamespaceDefault, objBody(codec, &target))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]Ownership{
+		"starfish": OwnedByRelease,
+		"otter":    Absent,
+		"squid":    OwnedByOtherRelease,
+		"eel":      Unmanaged,
+		"crab":     OwnedByOtherPath,
+	}
+	if len(results) != len(expect) {
+		t.Fatalf("expected %d results, got %d", len(expect), len(results))
+	}
+	for _, r := range results {
+		if r.Ownership != expect[r.Name] {
+			t.Errorf("%s: expected %s, got %s", r.Name, expect[r.Name], r.Ownership)
+		}
+		if r.Name == "squid" && r.Owner != "lake" {
+			t.Errorf("squid: expected owner lake, got %q", r.Owner)
+		}
+		if r.Name == "crab" && r.Path != "ocean/reef" {
+			t.Errorf("crab: expected path ocean/reef, got %q", r.Path)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	goerrors "errors"
+	"strings"
+	"sync"
+
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// DefaultParallelism is the default number of resources of the same kind that
+// are applied concurrently.
+const DefaultParallelism = 8
+
+// groupByKind splits infos into runs of consecutive resources of the same kind.
+//
+// Manifests are sorted by kind before they reach the client, so every run is
+// a group of the install or uninstall order.
+func groupByKind(infos Result) []Result {
+	groups := []Result{}
+	for i, info := range infos {
+		if i == 0 || info.Mapping.GroupVersionKind.Kind != infos[i-1].Mapping.GroupVersionKind.Kind {
+			groups = append(groups, Result{})
+		}
+		groups[len(groups)-1].Append(info)
+	}
+	return groups
+}
+
+// performParallel runs fn on every resource of infos, with at most
+// c.Parallelism calls in flight.
+//
+// Resources of the same kind group are handled concurrently, but a group only
+// starts once the previous group has completed. If a group fails, the
+// remaining groups are skipped unless keepGoing is set. All errors are
+// returned as one.
+func (c *Client) performParallel(infos Result, fn ResourceActorFunc, keepGoing bool) error {
+	if len(infos) == 0 {
+		return ErrNoObjectsVisited
+	}
+
+	errs := []error{}
+	for _, group := range groupByKind(infos) {
+		groupErrs := performGroup(group, fn, c.Parallelism)
+		errs = append(errs, groupErrs...)
+		if len(groupErrs) > 0 && !keepGoing {
+			break
+		}
+	}
+	return joinErrors(errs)
+}
+
+// performGroup runs fn on every resource of infos using up to workers
+// goroutines, and returns the errors of all calls.
+func performGroup(infos Result, fn ResourceActorFunc, workers int) []error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = []error{}
+		sem  = make(chan struct{}, workers)
+	)
+	for _, info := range infos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(info *resource.Info) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(info); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(info)
+	}
+	wg.Wait()
+	return errs
+}
+
+// joinErrors combines errs into a single error, or returns nil if errs is empty.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return goerrors.New(strings.Join(msgs, " && "))
+}
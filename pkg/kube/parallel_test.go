@@ -0,0 +1,193 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func newInfos(kinds ...string) Result {
+	infos := Result{}
+	for i, kind := range kinds {
+		infos.Append(&resource.Info{
+			Name:    fmt.Sprintf("%s-%d", strings.ToLower(kind), i),
+			Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: kind}},
+		})
+	}
+	return infos
+}
+
+func TestGroupByKind(t *testing.T) {
+	groups := groupByKind(newInfos("Secret", "ConfigMap", "ConfigMap", "Service", "Service", "Service", "ConfigMap"))
+
+	expect := []int{1, 2, 3, 1}
+	if len(groups) != len(expect) {
+		t.Fatalf("expected %d groups, got %d", len(expect), len(groups))
+	}
+	for i, g := range groups {
+		if len(g) != expect[i] {
+			t.Errorf("group %d: expected %d resources, got %d", i, expect[i], len(g))
+		}
+	}
+}
+
+func TestPerformParallel(t *testing.T) {
+	infos := newInfos("ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "Service", "Service")
+	c := &Client{Parallelism: 2}
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+		done     = map[string]bool{}
+	)
+	err := c.performParallel(infos, func(info *resource.Info) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		if info.Mapping.GroupVersionKind.Kind == "Service" {
+			for _, i := range infos[:4] {
+				if !done[i.Name] {
+					t.Errorf("%s started before %s completed", info.Name, i.Name)
+				}
+			}
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		done[info.Name] = true
+		mu.Unlock()
+		return nil
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxSeen > c.Parallelism {
+		t.Errorf("expected at most %d resources in flight, got %d", c.Parallelism, maxSeen)
+	}
+	if len(done) != len(infos) {
+		t.Errorf("expected %d resources to be handled, got %d", len(infos), len(done))
+	}
+}
+
+func TestPerformParallelErrors(t *testing.T) {
+	infos := newInfos("ConfigMap", "ConfigMap", "ConfigMap", "Service")
+	failing := func(info *resource.Info) error {
+		if info.Mapping.GroupVersionKind.Kind == "ConfigMap" && info.Name != "configmap-1" {
+			return errors.New(info.Name + " failed")
+		}
+		return nil
+	}
+
+	for _, keepGoing := range []bool{false, true} {
+		var mu sync.Mutex
+		visited := map[string]bool{}
+		c := &Client{Parallelism: 4}
+		err := c.performParallel(infos, func(info *resource.Info) error {
+			mu.Lock()
+			visited[info.Name] = true
+			mu.Unlock()
+			return failing(info)
+		}, keepGoing)
+
+		if err == nil {
+			t.Fatalf("keepGoing %t: expected an error", keepGoing)
+		}
+		for _, e := range []string{"configmap-0 failed", "configmap-2 failed"} {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("keepGoing %t: expected %q in %q", keepGoing, e, err)
+			}
+		}
+		if visited["service-3"] != keepGoing {
+			t.Errorf("keepGoing %t: expected the next group to run: %t", keepGoing, keepGoing)
+		}
+	}
+
+	if err := (&Client{}).performParallel(Result{}, failing, false); err != ErrNoObjectsVisited {
+		t.Errorf("expected ErrNoObjectsVisited, got %v", err)
+	}
+}
+
+// newLatencyClient returns a client whose fake REST client answers every
+// request after the given latency.
+func newLatencyClient(latency time.Duration, parallelism int) (*Client, runtime.Codec, api.PodList) {
+	names := make([]string, 50)
+	for i := range names {
+		names[i] = fmt.Sprintf("pod-%d", i)
+	}
+	list := newPodList(names...)
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			time.Sleep(latency)
+			return newResponse(200, &list.Items[0])
+		}),
+	}
+	c := newTestClient(&fakeReaperFactory{Factory: f, reaper: &fakeReaper{}})
+	c.Parallelism = parallelism
+	return c, codec, list
+}
+
+func benchmarkCreate(b *testing.B, parallelism int) {
+	c, codec, list := newLatencyClient(time.Millisecond, parallelism)
+	for n := 0; n < b.N; n++ {
+		infos, err := c.BuildUnstructured(api.NamespaceDefault, objBody(codec, &list))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := c.performParallel(infos, createResource, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDelete(b *testing.B, parallelism int) {
+	c, codec, list := newLatencyClient(time.Millisecond, parallelism)
+	for n := 0; n < b.N; n++ {
+		if err := c.Delete(api.NamespaceDefault, objBody(codec, &list)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateSequential(b *testing.B) { benchmarkCreate(b, 1) }
+func BenchmarkCreateParallel(b *testing.B)   { benchmarkCreate(b, DefaultParallelism) }
+func BenchmarkDeleteSequential(b *testing.B) { benchmarkDelete(b, 1) }
+func BenchmarkDeleteParallel(b *testing.B)   { benchmarkDelete(b, DefaultParallelism) }
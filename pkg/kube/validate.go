@@ -0,0 +1,153 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/api/validation"
+)
+
+var (
+	documentSep   = regexp.MustCompile(`(?m)^---\s*$`)
+	sourceComment = regexp.MustCompile(`(?m)^#\s*Source:\s*(\S+)\s*$`)
+)
+
+// schemaCache holds the swagger schema of every group version fetched from the
+// cluster, so that discovery is queried at most once per group version for the
+// lifetime of the process.
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[schema.GroupVersion]validation.Schema
+}
+
+// get returns the cached schema of gv, calling fetch if it is not cached yet.
+// Failed fetches are not cached.
+func (s *schemaCache) get(gv schema.GroupVersion, fetch func(schema.GroupVersion) (validation.Schema, error)) (validation.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sc, ok := s.schemas[gv]; ok {
+		return sc, nil
+	}
+	sc, err := fetch(gv)
+	if err != nil {
+		return nil, err
+	}
+	if s.schemas == nil {
+		s.schemas = map[schema.GroupVersion]validation.Schema{}
+	}
+	s.schemas[gv] = sc
+	return sc, nil
+}
+
+// fetchSchema downloads the swagger schema of gv through discovery. Group
+// versions that the server does not publish a schema for, such as third party
+// resources, are not validated.
+func (c *Client) fetchSchema(gv schema.GroupVersion) (validation.Schema, error) {
+	client, err := c.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	decl, err := client.Discovery().SwaggerSchema(gv)
+	if errors.IsNotFound(err) {
+		c.Log("no schema published for %s, skipping validation", gv)
+		return validation.NullSchema{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch schema for %s: %s", gv, err)
+	}
+	data, err := json.Marshal(decl)
+	if err != nil {
+		return nil, err
+	}
+	return validation.NewSwaggerSchemaFromBytes(data, nil)
+}
+
+// Validate checks every document of a manifest against the swagger schema of
+// the cluster, before any resource is created.
+//
+// All errors are reported at once. Every error names the template of its
+// document, taken from the "# Source:" comment preceding it.
+func (c *Client) Validate(reader io.Reader) error {
+	return validateDocuments(reader, func(gv schema.GroupVersion) (validation.Schema, error) {
+		return c.schemas.get(gv, c.fetchSchema)
+	})
+}
+
+func validateDocuments(reader io.Reader, schemaFor func(schema.GroupVersion) (validation.Schema, error)) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	errs := []string{}
+	for i, doc := range documentSep.Split(strings.TrimSpace(string(data)), -1) {
+		source := fmt.Sprintf("document %d", i)
+		if m := sourceComment.FindStringSubmatch(doc); m != nil {
+			source = m[1]
+		}
+		if err := validateDocument([]byte(doc), schemaFor); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", source, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("manifest validation failed, %d error(s):\n\t%s", len(errs), strings.Join(errs, "\n\t"))
+}
+
+func validateDocument(doc []byte, schemaFor func(schema.GroupVersion) (validation.Schema, error)) error {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return err
+	}
+	// Documents holding nothing but comments decode to null.
+	if string(data) == "null" {
+		return nil
+	}
+
+	var head struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+	if head.Kind == "" {
+		return fmt.Errorf("kind not set")
+	}
+	gv, err := schema.ParseGroupVersion(head.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	sc, err := schemaFor(gv)
+	if err != nil {
+		return err
+	}
+	return sc.ValidateBytes(data)
+}
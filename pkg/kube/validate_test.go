@@ -0,0 +1,115 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/api/validation"
+)
+
+// replicasSchema rejects any object with a string replicas field.
+type replicasSchema struct{}
+
+func (replicasSchema) ValidateBytes(data []byte) error {
+	if strings.Contains(string(data), `"replicas":"`) {
+		return errors.New(`field replicas: expected type integer, got string`)
+	}
+	return nil
+}
+
+const validateManifest = `
+---
+# Source: mychart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: good
+---not-a-separator: a key that only starts like one
+---
+# Source: mychart/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: bad
+spec:
+  replicas: "three"
+---
+# Source: mychart/templates/empty.yaml
+# nothing rendered here
+---
+# Source: mychart/templates/nokind.yaml
+apiVersion: v1
+metadata:
+  name: nokind
+`
+
+func TestValidateDocuments(t *testing.T) {
+	fetched := []schema.GroupVersion{}
+	cache := &schemaCache{}
+	schemaFor := func(gv schema.GroupVersion) (validation.Schema, error) {
+		return cache.get(gv, func(gv schema.GroupVersion) (validation.Schema, error) {
+			fetched = append(fetched, gv)
+			return replicasSchema{}, nil
+		})
+	}
+
+	for i := 0; i < 2; i++ {
+		err := validateDocuments(strings.NewReader(validateManifest), schemaFor)
+		if err == nil {
+			t.Fatal("expected validation errors")
+		}
+		for _, e := range []string{
+			"2 error(s)",
+			"mychart/templates/deployment.yaml: field replicas",
+			"mychart/templates/nokind.yaml: kind not set",
+		} {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("expected %q in error, got %s", e, err)
+			}
+		}
+		if strings.Contains(err.Error(), "configmap.yaml") || strings.Contains(err.Error(), "empty.yaml") {
+			t.Errorf("unexpected error for a valid document: %s", err)
+		}
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected every schema to be fetched once, got %v", fetched)
+	}
+}
+
+func TestSchemaCacheFailure(t *testing.T) {
+	cache := &schemaCache{}
+	gv := schema.GroupVersion{Version: "v1"}
+	calls := 0
+	fail := func(schema.GroupVersion) (validation.Schema, error) {
+		calls++
+		return nil, errors.New("discovery unavailable")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get(gv, fail); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected failed fetches not to be cached, got %d calls", calls)
+	}
+}
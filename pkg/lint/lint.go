@@ -25,10 +25,16 @@ import (
 
 // All runs all of the available linters on the given base directory.
 func All(basedir string) support.Linter {
+	return AllWithLookup(basedir, nil)
+}
+
+// AllWithLookup runs all of the available linters on the given base directory,
+// answering the 'lookup' template function with the given function.
+func AllWithLookup(basedir string, lookup func(apiVersion, kind, namespace, name string) (map[string]interface{}, error)) support.Linter {
 	// Using abs path to get directory context
 	chartDir, _ := filepath.Abs(basedir)
 
-	linter := support.Linter{ChartDir: chartDir}
+	linter := support.Linter{ChartDir: chartDir, Lookup: lookup}
 	rules.Chartfile(&linter)
 	rules.Values(&linter)
 	rules.Templates(&linter)
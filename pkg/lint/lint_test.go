@@ -28,6 +28,7 @@ const badChartDir = "rules/testdata/badchartfile"
 const badValuesFileDir = "rules/testdata/badvaluesfile"
 const badYamlFileDir = "rules/testdata/albatross"
 const goodChartDir = "rules/testdata/goodone"
+const badSchemaDir = "rules/testdata/badschema"
 
 func TestBadChart(t *testing.T) {
 	m := All(badChartDir).Messages
@@ -85,6 +86,21 @@ func TestBadValues(t *testing.T) {
 	}
 }
 
+func TestBadSchema(t *testing.T) {
+	var errs []support.Message
+	for _, msg := range All(badSchemaDir).Messages {
+		if msg.Severity == support.ErrorSev {
+			errs = append(errs, msg)
+		}
+	}
+	if len(errs) != 1 || errs[0].Path != "values.schema.json" {
+		t.Fatalf("Expected the schema violation to be reported once, got %#v", errs)
+	}
+	if !strings.Contains(errs[0].Err.Error(), "replicaCont") {
+		t.Errorf("All didn't have the error for the schema violation: %s", errs[0].Err)
+	}
+}
+
 func TestGoodChart(t *testing.T) {
 	m := All(goodChartDir).Messages
 	if len(m) != 0 {
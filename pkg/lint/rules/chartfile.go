@@ -52,6 +52,7 @@ func Chartfile(linter *support.Linter) {
 	// Chart metadata
 	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartVersion(chartFile))
 	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartEngine(chartFile))
+	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartType(chartFile))
 	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartMaintainer(chartFile))
 	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartSources(chartFile))
 	linter.RunLinterRule(support.InfoSev, chartFileName, validateChartIconPresence(chartFile))
@@ -135,6 +136,14 @@ func validateChartEngine(cf *chart.Metadata) error {
 	return fmt.Errorf("engine '%v' not valid. Valid options are %v", cf.Engine, keys)
 }
 
+func validateChartType(cf *chart.Metadata) error {
+	switch cf.Type {
+	case "", chartutil.ApplicationChartType, chartutil.LibraryChartType:
+		return nil
+	}
+	return fmt.Errorf("type '%v' not valid. Valid options are %v", cf.Type, []string{chartutil.ApplicationChartType, chartutil.LibraryChartType})
+}
+
 func validateChartMaintainer(cf *chart.Metadata) error {
 	for _, maintainer := range cf.Maintainers {
 		if maintainer.Name == "" {
@@ -138,6 +138,25 @@ func TestValidateChartEngine(t *testing.T) {
 	}
 }
 
+func TestValidateChartType(t *testing.T) {
+	var successTest = []string{"", "application", "library"}
+
+	for _, typ := range successTest {
+		badChart.Type = typ
+		err := validateChartType(badChart)
+		if err != nil {
+			t.Errorf("validateChartType(%s) to return no error, got a linter error %s", typ, err.Error())
+		}
+	}
+
+	badChart.Type = "foobar"
+	err := validateChartType(badChart)
+	if err == nil || !strings.Contains(err.Error(), "not valid. Valid options are [application library]") {
+		t.Errorf("validateChartType(%s) to return an error, got no error", badChart.Type)
+	}
+	badChart.Type = ""
+}
+
 func TestValidateChartMaintainer(t *testing.T) {
 	var failTest = []struct {
 		Name     string
@@ -20,14 +20,19 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"text/template"
 
 	"github.com/ghodss/yaml"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/timeconv"
 	tversion "k8s.io/helm/pkg/version"
 )
@@ -53,6 +58,8 @@ func Templates(linter *support.Linter) {
 		return
 	}
 
+	linter.RunLinterRule(support.WarningSev, path, validateNoDuplicateDefines(chart))
+
 	options := chartutil.ReleaseOptions{Name: "testRelease", Time: timeconv.Now(), Namespace: "testNamespace"}
 	caps := &chartutil.Capabilities{
 		APIVersions: chartutil.DefaultVersionSet,
@@ -65,14 +72,18 @@ func Templates(linter *support.Linter) {
 		},
 		TillerVersion: tversion.GetVersionProto(),
 	}
-	valuesToRender, err := chartutil.ToRenderValuesCaps(chart, chart.Values, options, caps)
+	// The values are checked against the schemas by the Values rule, so that
+	// every violation is reported once and the templates are still linted.
+	valuesToRender, err := chartutil.ToRenderValuesCaps(withoutSchemas(chart), chart.Values, options, caps)
 	if err != nil {
 		// FIXME: This seems to generate a duplicate, but I can't find where the first
 		// error is coming from.
 		//linter.RunLinterRule(support.ErrorSev, err)
 		return
 	}
-	renderedContentMap, err := engine.New().Render(chart, valuesToRender)
+	e := engine.New()
+	e.Lookup = linter.Lookup
+	renderedContentMap, err := e.Render(chart, valuesToRender)
 
 	renderOk := linter.RunLinterRule(support.ErrorSev, path, err)
 
@@ -93,6 +104,12 @@ func Templates(linter *support.Linter) {
 
 		linter.RunLinterRule(support.ErrorSev, path, validateAllowedExtension(fileName))
 
+		// The templates of library charts are never rendered
+		if chartutil.IsLibraryChart(chart) {
+			linter.RunLinterRule(support.WarningSev, path, validateLibraryTemplate(fileName))
+			continue
+		}
+
 		// We only apply the following lint rules to yaml files
 		if filepath.Ext(fileName) != ".yaml" {
 			continue
@@ -142,6 +159,60 @@ func validateAllowedExtension(fileName string) error {
 	return fmt.Errorf("file extension '%s' not valid. Valid extensions are .yaml, .tpl, or .txt", ext)
 }
 
+// validateLibraryTemplate reports templates of a library chart that would
+// produce a manifest, since only named templates of library charts are used.
+func validateLibraryTemplate(fileName string) error {
+	if strings.HasPrefix(filepath.Base(fileName), "_") {
+		return nil
+	}
+	return fmt.Errorf("library charts are not rendered. Move the named templates of this file to a file that starts with '_'")
+}
+
+// validateNoDuplicateDefines reports named templates that are defined more
+// than once in the same chart, where the later definition silently wins.
+func validateNoDuplicateDefines(c *chart.Chart) error {
+	dups := duplicateDefines(c, c.GetMetadata().GetName())
+	if len(dups) == 0 {
+		return nil
+	}
+	return fmt.Errorf("templates are defined more than once: %s", strings.Join(dups, "; "))
+}
+
+// duplicateDefines lists the named templates that are defined in more than one
+// template file of a chart and of each of its dependencies.
+func duplicateDefines(c *chart.Chart, chartPath string) []string {
+	defined := map[string][]string{}
+	for _, tpl := range c.Templates {
+		t, err := template.New(tpl.Name).Funcs(engine.FuncMap()).Parse(string(tpl.Data))
+		if err != nil {
+			// Parse errors are reported when the templates are rendered.
+			continue
+		}
+		for _, d := range t.Templates() {
+			if d.Name() != tpl.Name {
+				defined[d.Name()] = append(defined[d.Name()], path.Join(chartPath, tpl.Name))
+			}
+		}
+	}
+
+	names := []string{}
+	for name, files := range defined {
+		if len(files) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	dups := []string{}
+	for _, name := range names {
+		dups = append(dups, fmt.Sprintf("%q in %s", name, strings.Join(defined[name], ", ")))
+	}
+	for _, dep := range c.Dependencies {
+		dups = append(dups, duplicateDefines(dep, path.Join(chartPath, "charts", dep.GetMetadata().GetName()))...)
+	}
+	return dups
+}
+
 func validateYamlContent(err error) error {
 	if err != nil {
 		return fmt.Errorf("unable to parse YAML\n\t%s", err)
@@ -156,3 +227,15 @@ type K8sYamlStruct struct {
 		Namespace string
 	}
 }
+
+// withoutSchemas returns a copy of the chart and of its dependencies without
+// their values schemas.
+func withoutSchemas(c *chart.Chart) *chart.Chart {
+	cp := *c
+	cp.Schema = nil
+	cp.Dependencies = make([]*chart.Chart, len(c.Dependencies))
+	for i, dep := range c.Dependencies {
+		cp.Dependencies[i] = withoutSchemas(dep)
+	}
+	return &cp
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package rules
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 const templateTestBasedir = "./testdata/albatross"
@@ -76,3 +78,84 @@ func TestTemplateIntegrationHappyPath(t *testing.T) {
 		t.Fatalf("Expected no error, got %d, %v", len(res), res)
 	}
 }
+
+func TestValidateNoDuplicateDefines(t *testing.T) {
+	sub := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "sub"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}sub{{end}}`)},
+			{Name: "templates/_more.tpl", Data: []byte(`{{define "fullname"}}more{{end}}`)},
+		},
+	}
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "top"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}top{{end}}{{define "name"}}top{{end}}`)},
+			{Name: "templates/svc.yaml", Data: []byte(`name: {{template "fullname"}}`)},
+		},
+		Dependencies: []*chart.Chart{sub},
+	}
+
+	// "fullname" is defined by both charts, which is fine, but twice by sub.
+	c.Templates[1].Data = []byte(`{{define "name"}}{{end}}name: {{template "fullname"}}`)
+	err := validateNoDuplicateDefines(c)
+	if err == nil {
+		t.Fatal("Expected duplicate defines to be reported")
+	}
+	expect := `templates are defined more than once: "name" in top/templates/_helpers.tpl, top/templates/svc.yaml; ` +
+		`"fullname" in top/charts/sub/templates/_helpers.tpl, top/charts/sub/templates/_more.tpl`
+	if err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+
+	c.Templates[1].Data = []byte(`name: {{template "fullname"}}`)
+	c.Dependencies = nil
+	if err := validateNoDuplicateDefines(c); err != nil {
+		t.Errorf("Expected no duplicates, got %s", err)
+	}
+}
+
+func TestTemplateLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-lint-lookup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"Chart.yaml":          "name: lookup\nversion: 0.1.0\n",
+		"templates/seed.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ (lookup \"v1\" \"ConfigMap\" .Release.Namespace \"seed\").metadata.name }}\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without a lookup function, no object exists and the template fails.
+	linter := support.Linter{ChartDir: dir}
+	Templates(&linter)
+	if len(linter.Messages) != 1 {
+		t.Fatalf("Expected one error, got %v", linter.Messages)
+	}
+
+	var looked []string
+	linter = support.Linter{
+		ChartDir: dir,
+		Lookup: func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+			looked = append(looked, strings.Join([]string{apiVersion, kind, namespace, name}, "/"))
+			return map[string]interface{}{"metadata": map[string]interface{}{"name": name}}, nil
+		},
+	}
+	Templates(&linter)
+	if len(linter.Messages) != 0 {
+		t.Errorf("Expected no error, got %v", linter.Messages)
+	}
+	if len(looked) != 1 || looked[0] != "v1/ConfigMap/testNamespace/seed" {
+		t.Errorf("Unexpected lookups: %v", looked)
+	}
+}
@@ -25,17 +25,18 @@ import (
 	"k8s.io/helm/pkg/lint/support"
 )
 
-// Values lints a chart's values.yaml file.
+// Values lints a chart's values.yaml file, and checks the values against the
+// chart's values.schema.json file.
 func Values(linter *support.Linter) {
 	file := "values.yaml"
 	vf := filepath.Join(linter.ChartDir, file)
 	fileExists := linter.RunLinterRule(support.InfoSev, file, validateValuesFileExistence(linter, vf))
 
-	if !fileExists {
+	if fileExists && !linter.RunLinterRule(support.ErrorSev, file, validateValuesFile(linter, vf)) {
 		return
 	}
 
-	linter.RunLinterRule(support.ErrorSev, file, validateValuesFile(linter, vf))
+	linter.RunLinterRule(support.ErrorSev, chartutil.SchemafileName, validateValuesSchema(linter))
 }
 
 func validateValuesFileExistence(linter *support.Linter, valuesPath string) error {
@@ -46,6 +47,21 @@ func validateValuesFileExistence(linter *support.Linter, valuesPath string) erro
 	return nil
 }
 
+// validateValuesSchema checks the default values of the chart and of its
+// dependencies against their schemas.
+func validateValuesSchema(linter *support.Linter) error {
+	chart, err := chartutil.Load(linter.ChartDir)
+	if err != nil {
+		// Loading errors are reported by the other rules.
+		return nil
+	}
+	vals, err := chartutil.CoalesceValues(chart, chart.Values)
+	if err != nil {
+		return nil
+	}
+	return chartutil.ValidateAgainstSchema(chart, vals)
+}
+
 func validateValuesFile(linter *support.Linter, valuesPath string) error {
 	_, err := chartutil.ReadValuesFile(valuesPath)
 	if err != nil {
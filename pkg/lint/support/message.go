@@ -39,6 +39,9 @@ type Linter struct {
 	// The highest severity of all the failing lint rules
 	HighestSeverity int
 	ChartDir        string
+	// Lookup answers the 'lookup' template function while the templates are
+	// rendered. If nil, 'lookup' finds no objects.
+	Lookup func(apiVersion, kind, namespace, name string) (map[string]interface{}, error)
 }
 
 // Message describes an error encountered while linting.
@@ -37,6 +37,10 @@ type Downloaders struct {
 	// Command is the executable path with which the plugin performs
 	// the actual download for the corresponding Protocols
 	Command string `json:"command"`
+	// PushCommand is the executable path with which the plugin uploads
+	// charts to repositories of the corresponding Protocols. Pushing is not
+	// supported if it is empty.
+	PushCommand string `json:"pushCommand,omitempty"`
 }
 
 // Metadata describes a plugin.
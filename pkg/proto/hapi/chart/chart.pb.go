@@ -51,6 +51,8 @@ type Chart struct {
 	// Miscellaneous files in a chart archive,
 	// e.g. README, LICENSE, etc.
 	Files []*google_protobuf.Any `protobuf:"bytes,5,rep,name=files" json:"files,omitempty"`
+	// JSON schema the values of this chart must conform to.
+	Schema []byte `protobuf:"bytes,6,opt,name=schema,proto3" json:"schema,omitempty"`
 }
 
 func (m *Chart) Reset()                    { *m = Chart{} }
@@ -93,6 +95,13 @@ func (m *Chart) GetFiles() []*google_protobuf.Any {
 	return nil
 }
 
+func (m *Chart) GetSchema() []byte {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Chart)(nil), "hapi.chart.Chart")
 }
@@ -100,21 +109,21 @@ func init() {
 func init() { proto.RegisterFile("hapi/chart/chart.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 242 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x86, 0x15, 0x4a, 0x0a, 0x1c, 0x2c, 0x58, 0x08, 0x4c, 0xa7, 0x8a, 0x09, 0x75, 0x70, 0x50,
-	0x11, 0x0f, 0x00, 0xcc, 0x2c, 0x16, 0x13, 0xdb, 0xb5, 0xb9, 0xa4, 0x91, 0x52, 0x3b, 0xaa, 0x5d,
-	0xa4, 0xbe, 0x3b, 0x03, 0xea, 0xd9, 0xa6, 0x09, 0xea, 0x12, 0x29, 0xf7, 0x7d, 0xff, 0xe5, 0xbf,
-	0xc0, 0xed, 0x0a, 0xbb, 0xa6, 0x58, 0xae, 0x70, 0xe3, 0xc3, 0x53, 0x75, 0x1b, 0xeb, 0xad, 0x80,
-	0xfd, 0x5c, 0xf1, 0x64, 0x72, 0xd7, 0x77, 0xac, 0xa9, 0x9a, 0x3a, 0x48, 0x93, 0xfb, 0x1e, 0x58,
-	0x93, 0xc7, 0x12, 0x3d, 0x1e, 0x41, 0x9e, 0xd6, 0x5d, 0x8b, 0x9e, 0x12, 0xaa, 0xad, 0xad, 0x5b,
-	0x2a, 0xf8, 0x6d, 0xb1, 0xad, 0x0a, 0x34, 0xbb, 0x80, 0x1e, 0x7e, 0x32, 0xc8, 0xdf, 0xf7, 0x19,
-	0xf1, 0x04, 0xe7, 0x69, 0xa3, 0xcc, 0xa6, 0xd9, 0xe3, 0xe5, 0xfc, 0x46, 0x1d, 0x2a, 0xa9, 0x8f,
-	0xc8, 0xf4, 0x9f, 0x25, 0xe6, 0x70, 0x91, 0x3e, 0xe4, 0xe4, 0xc9, 0x74, 0xf4, 0x3f, 0xf2, 0x19,
-	0xa1, 0x3e, 0x68, 0xe2, 0x05, 0xae, 0x4a, 0xea, 0xc8, 0x94, 0x64, 0x96, 0x0d, 0x39, 0x39, 0xe2,
-	0xd8, 0x75, 0x3f, 0xc6, 0x75, 0xf4, 0x40, 0x13, 0x33, 0x18, 0x7f, 0x63, 0xbb, 0x25, 0x27, 0x4f,
-	0xb9, 0x9a, 0x18, 0x04, 0xf8, 0x0f, 0xe9, 0x68, 0x88, 0x19, 0xe4, 0x55, 0xd3, 0x92, 0x93, 0x79,
-	0xac, 0x14, 0xae, 0x57, 0xe9, 0x7a, 0xf5, 0x6a, 0x76, 0x3a, 0x28, 0x6f, 0x67, 0x5f, 0x39, 0xef,
-	0x58, 0x8c, 0x99, 0x3e, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xe9, 0x70, 0x34, 0x75, 0x9e, 0x01,
-	0x00, 0x00,
+	// 254 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xbf, 0x4e, 0xc3, 0x30,
+	0x10, 0xc6, 0x95, 0x96, 0x04, 0x38, 0xba, 0x60, 0xa1, 0x62, 0x3a, 0x45, 0x4c, 0x55, 0x07, 0x07,
+	0x15, 0xf1, 0x00, 0xc0, 0xcc, 0x62, 0x31, 0xb1, 0x5d, 0x93, 0xcb, 0x1f, 0x29, 0xb1, 0xa3, 0xda,
+	0x45, 0xea, 0x7b, 0xf0, 0xc0, 0xa8, 0xb6, 0x43, 0x53, 0xd4, 0xc5, 0xd2, 0xdd, 0xf7, 0xfb, 0xce,
+	0xdf, 0x1d, 0xcc, 0x6b, 0xec, 0x9b, 0x2c, 0xaf, 0x71, 0x6b, 0xfd, 0x2b, 0xfa, 0xad, 0xb6, 0x9a,
+	0xc1, 0xa1, 0x2f, 0x5c, 0x67, 0x71, 0x3f, 0x66, 0xb4, 0x2a, 0x9b, 0xca, 0x43, 0x8b, 0x87, 0x91,
+	0xd0, 0x91, 0xc5, 0x02, 0x2d, 0x9e, 0x91, 0x2c, 0x75, 0x7d, 0x8b, 0x96, 0x06, 0xa9, 0xd2, 0xba,
+	0x6a, 0x29, 0x73, 0xd5, 0x66, 0x57, 0x66, 0xa8, 0xf6, 0x5e, 0x7a, 0xfc, 0x99, 0x40, 0xfc, 0x7e,
+	0xf0, 0xb0, 0x27, 0xb8, 0x1a, 0x26, 0xf2, 0x28, 0x8d, 0x96, 0x37, 0xeb, 0x3b, 0x71, 0x8c, 0x24,
+	0x3e, 0x82, 0x26, 0xff, 0x28, 0xb6, 0x86, 0xeb, 0xe1, 0x23, 0xc3, 0x27, 0xe9, 0xf4, 0xbf, 0xe5,
+	0x33, 0x88, 0xf2, 0x88, 0xb1, 0x17, 0x98, 0x15, 0xd4, 0x93, 0x2a, 0x48, 0xe5, 0x0d, 0x19, 0x3e,
+	0x75, 0xb6, 0xdb, 0xb1, 0xcd, 0xc5, 0x91, 0x27, 0x18, 0x5b, 0x41, 0xf2, 0x8d, 0xed, 0x8e, 0x0c,
+	0xbf, 0x70, 0xd1, 0xd8, 0x89, 0xc1, 0x5d, 0x48, 0x06, 0x82, 0xad, 0x20, 0x2e, 0x9b, 0x96, 0x0c,
+	0x8f, 0x43, 0x24, 0xbf, 0xbd, 0x18, 0xb6, 0x17, 0xaf, 0x6a, 0x2f, 0x3d, 0xc2, 0xe6, 0x90, 0x98,
+	0xbc, 0xa6, 0x0e, 0x79, 0x92, 0x46, 0xcb, 0x99, 0x0c, 0xd5, 0xdb, 0xe5, 0x57, 0xec, 0x66, 0x6f,
+	0x12, 0xe7, 0x7a, 0xfe, 0x1d, 0x00, 0xaa, 0x30, 0xbc, 0x50, 0xb6, 0x01, 0x00, 0x00,
 }
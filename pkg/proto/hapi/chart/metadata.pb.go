@@ -96,6 +96,9 @@ type Metadata struct {
 	// TillerVersion is a SemVer constraints on what version of Tiller is required.
 	// See SemVer ranges here: https://github.com/Masterminds/semver#basic-comparisons
 	TillerVersion string `protobuf:"bytes,15,opt,name=tillerVersion" json:"tillerVersion,omitempty"`
+	// The type of the chart: 'application' (the default) or 'library'.
+	// A library chart only provides named templates to the charts that depend on it.
+	Type string `protobuf:"bytes,16,opt,name=type" json:"type,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -208,6 +211,13 @@ func (m *Metadata) GetTillerVersion() string {
 	return ""
 }
 
+func (m *Metadata) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Maintainer)(nil), "hapi.chart.Maintainer")
 	proto.RegisterType((*Metadata)(nil), "hapi.chart.Metadata")
@@ -217,28 +227,28 @@ func init() {
 func init() { proto.RegisterFile("hapi/chart/metadata.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 354 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x5f, 0x4b, 0xe3, 0x40,
-	0x14, 0xc5, 0x37, 0x9b, 0xe6, 0xdf, 0xcd, 0x76, 0xb7, 0x0c, 0x4b, 0x99, 0x5d, 0x44, 0x42, 0xf1,
-	0x21, 0x4f, 0x29, 0x28, 0x88, 0xcf, 0x82, 0xf8, 0xa0, 0x6d, 0x25, 0xf8, 0x07, 0x7c, 0x1b, 0x93,
-	0x4b, 0x3b, 0xd8, 0xcc, 0x84, 0xc9, 0xa8, 0xf8, 0x7d, 0xfd, 0x20, 0x32, 0x93, 0xa4, 0x8d, 0xe0,
-	0xdb, 0x3d, 0xe7, 0xe4, 0xfe, 0xc2, 0xb9, 0x0c, 0xfc, 0xdb, 0xb0, 0x9a, 0xcf, 0x8b, 0x0d, 0x53,
-	0x7a, 0x5e, 0xa1, 0x66, 0x25, 0xd3, 0x2c, 0xab, 0x95, 0xd4, 0x92, 0x80, 0x89, 0x32, 0x1b, 0xcd,
-	0x4e, 0x01, 0x16, 0x8c, 0x0b, 0xcd, 0xb8, 0x40, 0x45, 0x08, 0x8c, 0x04, 0xab, 0x90, 0x3a, 0x89,
-	0x93, 0x46, 0xb9, 0x9d, 0xc9, 0x5f, 0xf0, 0xb0, 0x62, 0x7c, 0x4b, 0x7f, 0x5a, 0xb3, 0x15, 0xb3,
-	0x0f, 0x17, 0xc2, 0x45, 0x87, 0xfd, 0x76, 0x8d, 0xc0, 0x68, 0x23, 0x2b, 0xec, 0xb6, 0xec, 0x4c,
-	0x28, 0x04, 0x8d, 0x7c, 0x51, 0x05, 0x36, 0xd4, 0x4d, 0xdc, 0x34, 0xca, 0x7b, 0x69, 0x92, 0x57,
-	0x54, 0x0d, 0x97, 0x82, 0x8e, 0xec, 0x42, 0x2f, 0x49, 0x02, 0x71, 0x89, 0x4d, 0xa1, 0x78, 0xad,
-	0x4d, 0xea, 0xd9, 0x74, 0x68, 0x91, 0xff, 0x10, 0x3e, 0xe3, 0xfb, 0x9b, 0x54, 0x65, 0x43, 0x7d,
-	0x8b, 0xdd, 0x69, 0x72, 0x06, 0x71, 0xb5, 0xab, 0xd7, 0xd0, 0x20, 0x71, 0xd3, 0xf8, 0x78, 0x9a,
-	0xed, 0x0f, 0x90, 0xed, 0xdb, 0xe7, 0xc3, 0x4f, 0xc9, 0x14, 0x7c, 0x14, 0x6b, 0x2e, 0x90, 0x86,
-	0xf6, 0x97, 0x9d, 0x32, 0xbd, 0x78, 0x21, 0x05, 0x8d, 0xda, 0x5e, 0x66, 0x26, 0x87, 0x00, 0xac,
-	0xe6, 0xf7, 0x5d, 0x01, 0xb0, 0xc9, 0xc0, 0x21, 0x07, 0x10, 0x15, 0x52, 0x94, 0xdc, 0x36, 0x88,
-	0x6d, 0xbc, 0x37, 0x0c, 0x51, 0xb3, 0x75, 0x43, 0x7f, 0xb5, 0x44, 0x33, 0xb7, 0xc4, 0xba, 0x27,
-	0x8e, 0x7b, 0x62, 0xef, 0x98, 0xbc, 0xc4, 0x5a, 0x61, 0xc1, 0x34, 0x96, 0xf4, 0x77, 0xe2, 0xa4,
-	0x61, 0x3e, 0x70, 0xc8, 0x11, 0x8c, 0x35, 0xdf, 0x6e, 0x51, 0xf5, 0x88, 0x3f, 0x16, 0xf1, 0xd5,
-	0x9c, 0x25, 0xe0, 0x5f, 0xb4, 0xad, 0x62, 0x08, 0xee, 0x96, 0x57, 0xcb, 0xd5, 0xc3, 0x72, 0xf2,
-	0x83, 0x44, 0xe0, 0x5d, 0xae, 0x6e, 0x6f, 0xae, 0x27, 0xce, 0x79, 0xf0, 0xe8, 0xd9, 0x33, 0x3d,
-	0xf9, 0xf6, 0xe9, 0x9c, 0x7c, 0x06, 0x00, 0x00, 0xff, 0xff, 0xea, 0xb5, 0x4c, 0xbe, 0x57, 0x02,
-	0x00, 0x00,
+	// 361 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xdd, 0x6b, 0xdb, 0x30,
+	0x14, 0xc5, 0xe7, 0x39, 0xfe, 0xba, 0x5e, 0xb6, 0x20, 0x46, 0xd0, 0xc6, 0x18, 0x26, 0xec, 0xc1,
+	0x4f, 0x0e, 0x6c, 0x30, 0xf6, 0x3c, 0x18, 0x7b, 0x68, 0x93, 0x14, 0xd3, 0x0f, 0xe8, 0x9b, 0x6a,
+	0x5f, 0x12, 0xd1, 0x58, 0x12, 0xb2, 0xda, 0x92, 0xff, 0xbc, 0x8f, 0x45, 0xb2, 0x9d, 0xb8, 0xd0,
+	0xb7, 0x7b, 0xce, 0xf1, 0xfd, 0x5d, 0x8e, 0x30, 0x7c, 0xd9, 0x31, 0xc5, 0x97, 0xd5, 0x8e, 0x69,
+	0xb3, 0x6c, 0xd0, 0xb0, 0x9a, 0x19, 0x56, 0x28, 0x2d, 0x8d, 0x24, 0x60, 0xa3, 0xc2, 0x45, 0x8b,
+	0xdf, 0x00, 0x2b, 0xc6, 0x85, 0x61, 0x5c, 0xa0, 0x26, 0x04, 0x26, 0x82, 0x35, 0x48, 0xbd, 0xcc,
+	0xcb, 0x93, 0xd2, 0xcd, 0xe4, 0x33, 0x04, 0xd8, 0x30, 0xbe, 0xa7, 0xef, 0x9d, 0xd9, 0x89, 0xc5,
+	0xb3, 0x0f, 0xf1, 0xaa, 0xc7, 0xbe, 0xb9, 0x46, 0x60, 0xb2, 0x93, 0x0d, 0xf6, 0x5b, 0x6e, 0x26,
+	0x14, 0xa2, 0x56, 0x3e, 0xe8, 0x0a, 0x5b, 0xea, 0x67, 0x7e, 0x9e, 0x94, 0x83, 0xb4, 0xc9, 0x23,
+	0xea, 0x96, 0x4b, 0x41, 0x27, 0x6e, 0x61, 0x90, 0x24, 0x83, 0xb4, 0xc6, 0xb6, 0xd2, 0x5c, 0x19,
+	0x9b, 0x06, 0x2e, 0x1d, 0x5b, 0xe4, 0x2b, 0xc4, 0xf7, 0x78, 0x78, 0x92, 0xba, 0x6e, 0x69, 0xe8,
+	0xb0, 0x47, 0x4d, 0xfe, 0x40, 0xda, 0x1c, 0xeb, 0xb5, 0x34, 0xca, 0xfc, 0x3c, 0xfd, 0x39, 0x2f,
+	0x4e, 0x0f, 0x50, 0x9c, 0xda, 0x97, 0xe3, 0x4f, 0xc9, 0x1c, 0x42, 0x14, 0x5b, 0x2e, 0x90, 0xc6,
+	0xee, 0x64, 0xaf, 0x6c, 0x2f, 0x5e, 0x49, 0x41, 0x93, 0xae, 0x97, 0x9d, 0xc9, 0x77, 0x00, 0xa6,
+	0xf8, 0x75, 0x5f, 0x00, 0x5c, 0x32, 0x72, 0xc8, 0x37, 0x48, 0x2a, 0x29, 0x6a, 0xee, 0x1a, 0xa4,
+	0x2e, 0x3e, 0x19, 0x96, 0x68, 0xd8, 0xb6, 0xa5, 0x1f, 0x3a, 0xa2, 0x9d, 0x3b, 0xa2, 0x1a, 0x88,
+	0xd3, 0x81, 0x38, 0x38, 0x36, 0xaf, 0x51, 0x69, 0xac, 0x98, 0xc1, 0x9a, 0x7e, 0xcc, 0xbc, 0x3c,
+	0x2e, 0x47, 0x0e, 0xf9, 0x01, 0x53, 0xc3, 0xf7, 0x7b, 0xd4, 0x03, 0xe2, 0x93, 0x43, 0xbc, 0x36,
+	0xdd, 0xe5, 0x83, 0x42, 0x3a, 0xeb, 0x2f, 0x1f, 0x14, 0x2e, 0x32, 0x08, 0xff, 0x75, 0x4d, 0x53,
+	0x88, 0xae, 0xd6, 0x67, 0xeb, 0xcd, 0xcd, 0x7a, 0xf6, 0x8e, 0x24, 0x10, 0xfc, 0xdf, 0x5c, 0x5e,
+	0x9c, 0xcf, 0xbc, 0xbf, 0xd1, 0x6d, 0xe0, 0x9e, 0xee, 0x2e, 0x74, 0xbf, 0xd3, 0xaf, 0x97, 0x01,
+	0x00, 0x69, 0xea, 0xdf, 0x71, 0x6b, 0x02, 0x00, 0x00,
 }
@@ -176,6 +176,8 @@ func (m *InstallReleaseResponse) GetResult() *Result {
 
 type DeleteReleaseRequest struct {
 	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// DeleteCrds also deletes the custom resource definitions of the release.
+	DeleteCrds bool `protobuf:"varint,2,opt,name=delete_crds,json=deleteCrds" json:"delete_crds,omitempty"`
 }
 
 func (m *DeleteReleaseRequest) Reset()                    { *m = DeleteReleaseRequest{} }
@@ -190,6 +192,13 @@ func (m *DeleteReleaseRequest) GetRelease() *hapi_release5.Release {
 	return nil
 }
 
+func (m *DeleteReleaseRequest) GetDeleteCrds() bool {
+	if m != nil {
+		return m.DeleteCrds
+	}
+	return false
+}
+
 type DeleteReleaseResponse struct {
 	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Result  *Result                `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
@@ -681,43 +690,44 @@ var _ReleaseModuleService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/rudder/rudder.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 597 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x56, 0x5f, 0x8f, 0xd2, 0x4e,
-	0x14, 0xa5, 0xb0, 0x14, 0xb8, 0x64, 0x7f, 0x3f, 0x32, 0xa1, 0xd0, 0x34, 0x3e, 0x90, 0x3e, 0x18,
-	0xe2, 0xba, 0x25, 0x41, 0x1f, 0x7d, 0x51, 0x96, 0xfd, 0x13, 0x23, 0x9b, 0x0c, 0xe2, 0x26, 0xbe,
-	0x75, 0xe1, 0x82, 0xd5, 0xd2, 0xd6, 0xe9, 0x74, 0x1f, 0xd5, 0x4f, 0xe3, 0x57, 0xd2, 0x8f, 0x63,
-	0xda, 0x69, 0x89, 0xad, 0xd3, 0x88, 0x6b, 0xc2, 0x83, 0x4f, 0x9d, 0xe9, 0x3d, 0xdc, 0x39, 0xe7,
-	0xf4, 0xce, 0x09, 0xa0, 0xbf, 0xb3, 0x03, 0x67, 0xc4, 0xa2, 0xd5, 0x0a, 0x59, 0xfa, 0xb0, 0x02,
-	0xe6, 0x73, 0x9f, 0x74, 0xe3, 0x8a, 0x15, 0x22, 0xbb, 0x73, 0x96, 0x18, 0x5a, 0xa2, 0x66, 0xf4,
-	0x05, 0x1e, 0x5d, 0xb4, 0x43, 0x1c, 0x39, 0xde, 0xda, 0x17, 0x70, 0xc3, 0xc8, 0x15, 0xd2, 0xa7,
-	0xa8, 0x99, 0x2e, 0xa8, 0x14, 0xc3, 0xc8, 0xe5, 0x84, 0xc0, 0x51, 0xfc, 0x1b, 0x5d, 0x19, 0x28,
-	0xc3, 0x16, 0x4d, 0xd6, 0xa4, 0x03, 0x35, 0xd7, 0xdf, 0xe8, 0xd5, 0x41, 0x6d, 0xd8, 0xa2, 0xf1,
-	0xd2, 0x7c, 0x06, 0xea, 0x9c, 0xdb, 0x3c, 0x0a, 0x49, 0x1b, 0x1a, 0x8b, 0xd9, 0xcb, 0xd9, 0xf5,
-	0xcd, 0xac, 0x53, 0x89, 0x37, 0xf3, 0xc5, 0x64, 0x32, 0x9d, 0xcf, 0x3b, 0x0a, 0x39, 0x86, 0xd6,
-	0x62, 0x36, 0xb9, 0x7c, 0x3e, 0xbb, 0x98, 0x9e, 0x75, 0xaa, 0xa4, 0x05, 0xf5, 0x29, 0xa5, 0xd7,
-	0xb4, 0x53, 0x33, 0xfb, 0xa0, 0xbd, 0x41, 0x16, 0x3a, 0xbe, 0x47, 0x05, 0x0b, 0x8a, 0x1f, 0x23,
-	0x0c, 0xb9, 0x79, 0x0e, 0xbd, 0x62, 0x21, 0x0c, 0x7c, 0x2f, 0xc4, 0x98, 0x96, 0x67, 0x6f, 0x31,
-	0xa3, 0x15, 0xaf, 0x89, 0x0e, 0x8d, 0x3b, 0x81, 0xd6, 0xab, 0xc9, 0xeb, 0x6c, 0x6b, 0x5e, 0x82,
-	0x76, 0xe5, 0x85, 0xdc, 0x76, 0xdd, 0xfc, 0x01, 0x64, 0x04, 0x8d, 0x54, 0x78, 0xd2, 0xa9, 0x3d,
-	0xd6, 0xac, 0xc4, 0xc4, 0xcc, 0x8d, 0x0c, 0x9e, 0xa1, 0xcc, 0xcf, 0xd0, 0x2b, 0x76, 0x4a, 0x19,
-	0xfd, 0x69, 0x2b, 0xf2, 0x14, 0x54, 0x96, 0x78, 0x9c, 0xb0, 0x6d, 0x8f, 0x1f, 0x58, 0xb2, 0xef,
-	0x67, 0x89, 0xef, 0x40, 0x53, 0xac, 0x79, 0x01, 0xdd, 0x33, 0x74, 0x91, 0xe3, 0xdf, 0x2a, 0xf9,
-	0x04, 0x5a, 0xa1, 0xd1, 0x61, 0x85, 0x7c, 0x53, 0x40, 0x5b, 0x04, 0x1b, 0x66, 0xaf, 0x24, 0x52,
-	0x96, 0x11, 0x63, 0xe8, 0xf1, 0xdf, 0x10, 0x48, 0x51, 0xe4, 0x14, 0x54, 0x6e, 0xb3, 0x0d, 0x66,
-	0x04, 0x4a, 0xf0, 0x29, 0x28, 0x9e, 0x93, 0xd7, 0xce, 0x16, 0xfd, 0x88, 0xeb, 0xb5, 0x81, 0x32,
-	0xac, 0xd1, 0x6c, 0x1b, 0x4f, 0xd5, 0x8d, 0xed, 0x70, 0xfd, 0x68, 0xa0, 0x0c, 0x9b, 0x34, 0x59,
-	0x13, 0x03, 0x9a, 0x14, 0x97, 0x0c, 0x6d, 0x8e, 0x7a, 0x3d, 0x79, 0xbf, 0xdb, 0x93, 0x2e, 0xd4,
-	0xcf, 0x7d, 0xb6, 0x44, 0x5d, 0x4d, 0x0a, 0x62, 0x13, 0xcf, 0x48, 0x51, 0xd8, 0x61, 0xad, 0xfd,
-	0xae, 0x40, 0x8f, 0xfa, 0xae, 0x7b, 0x6b, 0x2f, 0x3f, 0xfc, 0x63, 0xde, 0x7e, 0x51, 0xa0, 0xff,
-	0x8b, 0xb4, 0x83, 0xdf, 0xc0, 0xb4, 0x93, 0x88, 0xbc, 0x7b, 0xdf, 0xc0, 0x00, 0xb4, 0x42, 0xa3,
-	0xfb, 0x0a, 0x79, 0x98, 0x86, 0xb4, 0x90, 0x41, 0xf2, 0xe8, 0x2b, 0x6f, 0xed, 0x8b, 0xe0, 0x1e,
-	0x7f, 0xad, 0xef, 0xb8, 0xbf, 0xf2, 0x57, 0x91, 0x8b, 0x73, 0x21, 0x95, 0xac, 0xa1, 0x91, 0x06,
-	0x2d, 0x39, 0x91, 0x9b, 0x20, 0x0d, 0x68, 0xe3, 0xf1, 0x7e, 0x60, 0xa1, 0xcb, 0xac, 0x90, 0x2d,
-	0xfc, 0x97, 0x8f, 0xcf, 0xb2, 0xe3, 0xa4, 0x71, 0x5d, 0x76, 0x9c, 0x3c, 0x91, 0xcd, 0x0a, 0x79,
-	0x0f, 0xc7, 0xb9, 0x8c, 0x23, 0x8f, 0xe4, 0x0d, 0x64, 0x89, 0x6a, 0x9c, 0xec, 0x85, 0xdd, 0x9d,
-	0x15, 0xc0, 0xff, 0x85, 0xc1, 0x24, 0x25, 0x74, 0xe5, 0x57, 0xd3, 0x38, 0xdd, 0x13, 0xfd, 0xb3,
-	0x99, 0xf9, 0x9c, 0x29, 0x33, 0x53, 0x1a, 0xb3, 0x65, 0x66, 0xca, 0xa3, 0x4b, 0x98, 0x99, 0x1b,
-	0xd7, 0x32, 0x33, 0x65, 0x97, 0xa3, 0xcc, 0x4c, 0xe9, 0xfc, 0x9b, 0x95, 0x17, 0xcd, 0xb7, 0xaa,
-	0x40, 0xdc, 0xaa, 0xc9, 0x1f, 0x92, 0x27, 0x3f, 0x02, 0x00, 0x00, 0xff, 0xff, 0xb6, 0xa5, 0x37,
-	0x75, 0xf7, 0x08, 0x00, 0x00,
+	// 612 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x56, 0x5d, 0x6f, 0xd3, 0x30,
+	0x14, 0x5d, 0xd6, 0x35, 0x5d, 0x6f, 0x35, 0xa8, 0xac, 0x66, 0x8b, 0x22, 0x24, 0xaa, 0x3c, 0xa0,
+	0x8a, 0x76, 0xa9, 0x54, 0x78, 0xe4, 0x05, 0xba, 0xee, 0x43, 0x88, 0x4e, 0x72, 0x29, 0x93, 0x78,
+	0x41, 0x59, 0xe2, 0x76, 0x81, 0x34, 0x09, 0xb6, 0xb3, 0x47, 0xe0, 0xd7, 0xf0, 0x97, 0xe0, 0xe7,
+	0xa0, 0xd8, 0x49, 0xb5, 0x84, 0x54, 0x84, 0x21, 0xf5, 0x81, 0xa7, 0xd8, 0xbe, 0xa7, 0xf7, 0x9e,
+	0x73, 0xe2, 0x1c, 0x15, 0xf4, 0x1b, 0x3b, 0xf2, 0x86, 0x34, 0x76, 0x5d, 0x42, 0xd3, 0x87, 0x15,
+	0xd1, 0x90, 0x87, 0xa8, 0x93, 0x54, 0x2c, 0x46, 0xe8, 0xad, 0xe7, 0x10, 0x66, 0xc9, 0x9a, 0x71,
+	0x24, 0xf1, 0xc4, 0x27, 0x36, 0x23, 0x43, 0x2f, 0x58, 0x84, 0x12, 0x6e, 0x18, 0xb9, 0x42, 0xfa,
+	0x94, 0x35, 0xd3, 0x07, 0x15, 0x13, 0x16, 0xfb, 0x1c, 0x21, 0xd8, 0x4b, 0x7e, 0xa3, 0x2b, 0x5d,
+	0xa5, 0xd7, 0xc4, 0x62, 0x8d, 0xda, 0x50, 0xf3, 0xc3, 0xa5, 0xbe, 0xdb, 0xad, 0xf5, 0x9a, 0x38,
+	0x59, 0x9a, 0x2f, 0x40, 0x9d, 0x71, 0x9b, 0xc7, 0x0c, 0xb5, 0xa0, 0x31, 0x9f, 0xbe, 0x9e, 0x5e,
+	0x5e, 0x4d, 0xdb, 0x3b, 0xc9, 0x66, 0x36, 0x1f, 0x8f, 0x27, 0xb3, 0x59, 0x5b, 0x41, 0x07, 0xd0,
+	0x9c, 0x4f, 0xc7, 0xe7, 0x2f, 0xa7, 0x67, 0x93, 0x93, 0xf6, 0x2e, 0x6a, 0x42, 0x7d, 0x82, 0xf1,
+	0x25, 0x6e, 0xd7, 0xcc, 0x23, 0xd0, 0xde, 0x11, 0xca, 0xbc, 0x30, 0xc0, 0x92, 0x05, 0x26, 0x9f,
+	0x63, 0xc2, 0xb8, 0x79, 0x0a, 0x87, 0xc5, 0x02, 0x8b, 0xc2, 0x80, 0x91, 0x84, 0x56, 0x60, 0xaf,
+	0x48, 0x46, 0x2b, 0x59, 0x23, 0x1d, 0x1a, 0xb7, 0x12, 0xad, 0xef, 0x8a, 0xe3, 0x6c, 0x6b, 0x9e,
+	0x83, 0x76, 0x11, 0x30, 0x6e, 0xfb, 0x7e, 0x7e, 0x00, 0x1a, 0x42, 0x23, 0x15, 0x2e, 0x3a, 0xb5,
+	0x46, 0x9a, 0x25, 0x4c, 0x4c, 0x0f, 0xad, 0x0c, 0x9e, 0xa1, 0xcc, 0xaf, 0x70, 0x58, 0xec, 0x94,
+	0x32, 0xfa, 0xdb, 0x56, 0xe8, 0x39, 0xa8, 0x54, 0x78, 0x2c, 0xd8, 0xb6, 0x46, 0x8f, 0xac, 0xb2,
+	0xf7, 0x67, 0xc9, 0xf7, 0x80, 0x53, 0xac, 0x79, 0x03, 0x9d, 0x13, 0xe2, 0x13, 0x4e, 0xfe, 0x51,
+	0x09, 0x7a, 0x0c, 0x2d, 0x57, 0x34, 0xfa, 0xe0, 0x50, 0x97, 0x09, 0x0e, 0xfb, 0x18, 0xe4, 0xd1,
+	0x98, 0xba, 0xcc, 0xfc, 0x02, 0x5a, 0x61, 0xd2, 0x76, 0x95, 0xfe, 0x50, 0x40, 0x9b, 0x47, 0x4b,
+	0x6a, 0xbb, 0x25, 0x5a, 0x9d, 0x98, 0x52, 0x12, 0xf0, 0x3f, 0x10, 0x48, 0x51, 0xe8, 0x18, 0x54,
+	0x6e, 0xd3, 0x25, 0xc9, 0x08, 0x6c, 0xc0, 0xa7, 0xa0, 0xe4, 0x22, 0xbd, 0xf5, 0x56, 0x24, 0x8c,
+	0xb9, 0x5e, 0xeb, 0x2a, 0xbd, 0x1a, 0xce, 0xb6, 0xc9, 0xb5, 0xbb, 0xb2, 0x3d, 0xae, 0xef, 0x09,
+	0xb7, 0xc4, 0x1a, 0x19, 0xb0, 0x8f, 0x89, 0x43, 0x89, 0xcd, 0x89, 0x5e, 0x17, 0xe7, 0xeb, 0x3d,
+	0xea, 0x40, 0xfd, 0x34, 0xa4, 0x0e, 0xd1, 0x55, 0x51, 0x90, 0x9b, 0xe4, 0x12, 0x15, 0x85, 0x6d,
+	0xd7, 0xda, 0x9f, 0x0a, 0x1c, 0xe2, 0xd0, 0xf7, 0xaf, 0x6d, 0xe7, 0xd3, 0x7f, 0xe6, 0xed, 0x37,
+	0x05, 0x8e, 0x7e, 0x93, 0xb6, 0x5d, 0x77, 0xcf, 0xa0, 0x93, 0x76, 0x92, 0x99, 0x78, 0xef, 0xb0,
+	0x89, 0x40, 0x2b, 0x34, 0xba, 0xaf, 0x90, 0x27, 0x69, 0x8a, 0x4b, 0x19, 0x28, 0x8f, 0xbe, 0x08,
+	0x16, 0xa1, 0x4c, 0xf6, 0xd1, 0xf7, 0xfa, 0x9a, 0xfb, 0x9b, 0xd0, 0x8d, 0x7d, 0x32, 0x93, 0x52,
+	0xd1, 0x02, 0x1a, 0x69, 0x12, 0xa3, 0x7e, 0xb9, 0x09, 0xa5, 0x09, 0x6e, 0x0c, 0xaa, 0x81, 0xa5,
+	0x2e, 0x73, 0x07, 0xad, 0xe0, 0x41, 0x3e, 0x5f, 0x37, 0x8d, 0x2b, 0xcd, 0x73, 0x63, 0x50, 0x0d,
+	0xbc, 0x1e, 0xf7, 0x11, 0x0e, 0x72, 0x19, 0x87, 0x9e, 0x96, 0x37, 0x28, 0x8b, 0x5c, 0xa3, 0x5f,
+	0x09, 0xbb, 0x9e, 0x15, 0xc1, 0xc3, 0xc2, 0xc5, 0x44, 0x1b, 0xe8, 0x96, 0x7f, 0x9a, 0xc6, 0x71,
+	0x45, 0xf4, 0x5d, 0x33, 0xf3, 0x39, 0xb3, 0xc9, 0xcc, 0xd2, 0x98, 0x35, 0x06, 0xd5, 0xc0, 0x77,
+	0xcd, 0xcc, 0x5d, 0xd7, 0x4d, 0x66, 0x96, 0x7d, 0x1c, 0x46, 0xbf, 0x12, 0x36, 0x9b, 0xf5, 0x6a,
+	0xff, 0xbd, 0x2a, 0x11, 0xd7, 0xaa, 0xf8, 0xc7, 0xf2, 0xec, 0xd7, 0x00, 0x66, 0x5a, 0xb6, 0xd8,
+	0x18, 0x09, 0x00, 0x00,
 }
@@ -30,6 +30,9 @@ It has these top-level messages:
 	GetHistoryResponse
 	TestReleaseRequest
 	TestReleaseResponse
+	AdoptReleaseRequest
+	AdoptReleaseResponse
+	AdoptedResource
 */
 package services
 
@@ -698,6 +701,8 @@ type UninstallReleaseRequest struct {
 	Purge bool `protobuf:"varint,3,opt,name=purge" json:"purge,omitempty"`
 	// timeout specifies the max amount of time any kubernetes client command can run.
 	Timeout int64 `protobuf:"varint,4,opt,name=timeout" json:"timeout,omitempty"`
+	// DeleteCrds also deletes the custom resource definitions of the release.
+	DeleteCrds bool `protobuf:"varint,5,opt,name=delete_crds,json=deleteCrds" json:"delete_crds,omitempty"`
 }
 
 func (m *UninstallReleaseRequest) Reset()                    { *m = UninstallReleaseRequest{} }
@@ -733,6 +738,13 @@ func (m *UninstallReleaseRequest) GetTimeout() int64 {
 	return 0
 }
 
+func (m *UninstallReleaseRequest) GetDeleteCrds() bool {
+	if m != nil {
+		return m.DeleteCrds
+	}
+	return false
+}
+
 // UninstallReleaseResponse represents a successful response to an uninstall request.
 type UninstallReleaseResponse struct {
 	// Release is the release that was marked deleted.
@@ -890,6 +902,139 @@ func (m *TestReleaseResponse) GetStatus() hapi_release1.TestRun_Status {
 	return hapi_release1.TestRun_UNKNOWN
 }
 
+// AdoptReleaseRequest is a request to bring existing resources under a release.
+type AdoptReleaseRequest struct {
+	// Name is the name of the release to create.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Chart is the protobuf representation of a chart.
+	Chart *hapi_chart3.Chart `protobuf:"bytes,2,opt,name=chart" json:"chart,omitempty"`
+	// Values is a string containing (unparsed) YAML values.
+	Values *hapi_chart.Config `protobuf:"bytes,3,opt,name=values" json:"values,omitempty"`
+	// Namepace is the kubernetes namespace of the release.
+	Namespace string `protobuf:"bytes,4,opt,name=namespace" json:"namespace,omitempty"`
+	// DryRun, if true, reports what would change without annotating any
+	// resource or recording a release.
+	DryRun bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+}
+
+func (m *AdoptReleaseRequest) Reset()                    { *m = AdoptReleaseRequest{} }
+func (m *AdoptReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*AdoptReleaseRequest) ProtoMessage()               {}
+func (*AdoptReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *AdoptReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdoptReleaseRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *AdoptReleaseRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *AdoptReleaseRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *AdoptReleaseRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+// AdoptReleaseResponse is the response to an adopt request.
+type AdoptReleaseResponse struct {
+	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Resources describes every resource of the rendered chart.
+	Resources []*AdoptedResource `protobuf:"bytes,2,rep,name=resources" json:"resources,omitempty"`
+}
+
+func (m *AdoptReleaseResponse) Reset()                    { *m = AdoptReleaseResponse{} }
+func (m *AdoptReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*AdoptReleaseResponse) ProtoMessage()               {}
+func (*AdoptReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *AdoptReleaseResponse) GetRelease() *hapi_release5.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *AdoptReleaseResponse) GetResources() []*AdoptedResource {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+// AdoptedResource describes a rendered resource and its live counterpart.
+type AdoptedResource struct {
+	Kind      string `protobuf:"bytes,1,opt,name=kind" json:"kind,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Namespace string `protobuf:"bytes,3,opt,name=namespace" json:"namespace,omitempty"`
+	// Found is true if a live object with the same kind and name exists.
+	Found bool `protobuf:"varint,4,opt,name=found" json:"found,omitempty"`
+	// Diff is a patch from the live object to the rendered one. It is empty
+	// if the resource was not found or does not differ.
+	Diff string `protobuf:"bytes,5,opt,name=diff" json:"diff,omitempty"`
+}
+
+func (m *AdoptedResource) Reset()                    { *m = AdoptedResource{} }
+func (m *AdoptedResource) String() string            { return proto.CompactTextString(m) }
+func (*AdoptedResource) ProtoMessage()               {}
+func (*AdoptedResource) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *AdoptedResource) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *AdoptedResource) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdoptedResource) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *AdoptedResource) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+func (m *AdoptedResource) GetDiff() string {
+	if m != nil {
+		return m.Diff
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
 	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
@@ -912,6 +1057,9 @@ func init() {
 	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
 	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
 	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
+	proto.RegisterType((*AdoptReleaseRequest)(nil), "hapi.services.tiller.AdoptReleaseRequest")
+	proto.RegisterType((*AdoptReleaseResponse)(nil), "hapi.services.tiller.AdoptReleaseResponse")
+	proto.RegisterType((*AdoptedResource)(nil), "hapi.services.tiller.AdoptedResource")
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
 }
@@ -950,6 +1098,8 @@ type ReleaseServiceClient interface {
 	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	// AdoptRelease brings existing resources under the management of a new release.
+	AdoptRelease(ctx context.Context, in *AdoptReleaseRequest, opts ...grpc.CallOption) (*AdoptReleaseResponse, error)
 }
 
 type releaseServiceClient struct {
@@ -1096,6 +1246,15 @@ func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error
 	return m, nil
 }
 
+func (c *releaseServiceClient) AdoptRelease(ctx context.Context, in *AdoptReleaseRequest, opts ...grpc.CallOption) (*AdoptReleaseResponse, error) {
+	out := new(AdoptReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/AdoptRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for ReleaseService service
 
 type ReleaseServiceServer interface {
@@ -1122,6 +1281,8 @@ type ReleaseServiceServer interface {
 	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
+	// AdoptRelease brings existing resources under the management of a new release.
+	AdoptRelease(context.Context, *AdoptReleaseRequest) (*AdoptReleaseResponse, error)
 }
 
 func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
@@ -1314,6 +1475,24 @@ func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ReleaseService_AdoptRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdoptReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).AdoptRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/AdoptRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).AdoptRelease(ctx, req.(*AdoptReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hapi.services.tiller.ReleaseService",
 	HandlerType: (*ReleaseServiceServer)(nil),
@@ -1350,6 +1529,10 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHistory",
 			Handler:    _ReleaseService_GetHistory_Handler,
 		},
+		{
+			MethodName: "AdoptRelease",
+			Handler:    _ReleaseService_AdoptRelease_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1369,82 +1552,90 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1217 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xdd, 0x6e, 0xe3, 0xc4,
-	0x17, 0xaf, 0xf3, 0x9d, 0x93, 0x36, 0xff, 0x74, 0x9a, 0xb6, 0xae, 0xff, 0x0b, 0x2a, 0x46, 0xb0,
-	0xd9, 0x85, 0x4d, 0x21, 0x70, 0x83, 0x84, 0x90, 0xba, 0xdd, 0xa8, 0x2d, 0x94, 0xae, 0xe4, 0x6c,
-	0x17, 0x09, 0x01, 0x91, 0x9b, 0x4c, 0x5a, 0xb3, 0x8e, 0x27, 0x78, 0xc6, 0x65, 0x7b, 0xcb, 0x1d,
-	0x8f, 0xc2, 0x5b, 0xf0, 0x1e, 0x5c, 0xc2, 0x83, 0x20, 0xcf, 0x87, 0xeb, 0x49, 0xed, 0xd6, 0xf4,
-	0x26, 0x9e, 0x99, 0xf3, 0xfd, 0x3b, 0x67, 0xce, 0x9c, 0x80, 0x75, 0xe9, 0x2e, 0xbc, 0x3d, 0x8a,
-	0xc3, 0x2b, 0x6f, 0x82, 0xe9, 0x1e, 0xf3, 0x7c, 0x1f, 0x87, 0xfd, 0x45, 0x48, 0x18, 0x41, 0xdd,
-	0x98, 0xd6, 0x57, 0xb4, 0xbe, 0xa0, 0x59, 0x5b, 0x5c, 0x62, 0x72, 0xe9, 0x86, 0x4c, 0xfc, 0x0a,
-	0x6e, 0x6b, 0x3b, 0x7d, 0x4e, 0x82, 0x99, 0x77, 0x21, 0x09, 0xc2, 0x44, 0x88, 0x7d, 0xec, 0x52,
-	0xac, 0xbe, 0x9a, 0x90, 0xa2, 0x79, 0xc1, 0x8c, 0x48, 0xc2, 0xff, 0x35, 0x02, 0xc3, 0x94, 0x8d,
-	0xc3, 0x28, 0x90, 0xc4, 0x1d, 0x8d, 0x48, 0x99, 0xcb, 0x22, 0xaa, 0x19, 0xbb, 0xc2, 0x21, 0xf5,
-	0x48, 0xa0, 0xbe, 0x82, 0x66, 0xff, 0x59, 0x82, 0x8d, 0x13, 0x8f, 0x32, 0x47, 0x08, 0x52, 0x07,
-	0xff, 0x12, 0x61, 0xca, 0x50, 0x17, 0xaa, 0xbe, 0x37, 0xf7, 0x98, 0x69, 0xec, 0x1a, 0xbd, 0xb2,
-	0x23, 0x36, 0x68, 0x0b, 0x6a, 0x64, 0x36, 0xa3, 0x98, 0x99, 0xa5, 0x5d, 0xa3, 0xd7, 0x74, 0xe4,
-	0x0e, 0x7d, 0x05, 0x75, 0x4a, 0x42, 0x36, 0x3e, 0xbf, 0x36, 0xcb, 0xbb, 0x46, 0xaf, 0x3d, 0xf8,
-	0xa0, 0x9f, 0x85, 0x53, 0x3f, 0xb6, 0x34, 0x22, 0x21, 0xeb, 0xc7, 0x3f, 0xcf, 0xaf, 0x9d, 0x1a,
-	0xe5, 0xdf, 0x58, 0xef, 0xcc, 0xf3, 0x19, 0x0e, 0xcd, 0x8a, 0xd0, 0x2b, 0x76, 0xe8, 0x10, 0x80,
-	0xeb, 0x25, 0xe1, 0x14, 0x87, 0x66, 0x95, 0xab, 0xee, 0x15, 0x50, 0xfd, 0x32, 0xe6, 0x77, 0x9a,
-	0x54, 0x2d, 0xd1, 0x97, 0xb0, 0x2a, 0x20, 0x19, 0x4f, 0xc8, 0x14, 0x53, 0xb3, 0xb6, 0x5b, 0xee,
-	0xb5, 0x07, 0x3b, 0x42, 0x95, 0x82, 0x7f, 0x24, 0x40, 0x3b, 0x20, 0x53, 0xec, 0xb4, 0x04, 0x7b,
-	0xbc, 0xa6, 0xe8, 0x11, 0x34, 0x03, 0x77, 0x8e, 0xe9, 0xc2, 0x9d, 0x60, 0xb3, 0xce, 0x3d, 0xbc,
-	0x39, 0xb0, 0x7f, 0x82, 0x86, 0x32, 0x6e, 0x0f, 0xa0, 0x26, 0x42, 0x43, 0x2d, 0xa8, 0x9f, 0x9d,
-	0x7e, 0x73, 0xfa, 0xf2, 0xbb, 0xd3, 0xce, 0x0a, 0x6a, 0x40, 0xe5, 0x74, 0xff, 0xdb, 0x61, 0xc7,
-	0x40, 0xeb, 0xb0, 0x76, 0xb2, 0x3f, 0x7a, 0x35, 0x76, 0x86, 0x27, 0xc3, 0xfd, 0xd1, 0xf0, 0x45,
-	0xa7, 0x64, 0xbf, 0x0b, 0xcd, 0xc4, 0x67, 0x54, 0x87, 0xf2, 0xfe, 0xe8, 0x40, 0x88, 0xbc, 0x18,
-	0x8e, 0x0e, 0x3a, 0x86, 0xfd, 0xbb, 0x01, 0x5d, 0x3d, 0x45, 0x74, 0x41, 0x02, 0x8a, 0xe3, 0x1c,
-	0x4d, 0x48, 0x14, 0x24, 0x39, 0xe2, 0x1b, 0x84, 0xa0, 0x12, 0xe0, 0xb7, 0x2a, 0x43, 0x7c, 0x1d,
-	0x73, 0x32, 0xc2, 0x5c, 0x9f, 0x67, 0xa7, 0xec, 0x88, 0x0d, 0xfa, 0x14, 0x1a, 0x32, 0x74, 0x6a,
-	0x56, 0x76, 0xcb, 0xbd, 0xd6, 0x60, 0x53, 0x07, 0x44, 0x5a, 0x74, 0x12, 0x36, 0xfb, 0x10, 0xb6,
-	0x0f, 0xb1, 0xf2, 0x44, 0xe0, 0xa5, 0x2a, 0x26, 0xb6, 0xeb, 0xce, 0x31, 0x77, 0x26, 0xb6, 0xeb,
-	0xce, 0x31, 0x32, 0xa1, 0x2e, 0xcb, 0x8d, 0xbb, 0x53, 0x75, 0xd4, 0xd6, 0x66, 0x60, 0xde, 0x56,
-	0x24, 0xe3, 0xca, 0xd2, 0xf4, 0x21, 0x54, 0xe2, 0x9b, 0xc0, 0xd5, 0xb4, 0x06, 0x48, 0xf7, 0xf3,
-	0x38, 0x98, 0x11, 0x87, 0xd3, 0xf5, 0x54, 0x95, 0x97, 0x53, 0x75, 0x94, 0xb6, 0x7a, 0x40, 0x02,
-	0x86, 0x03, 0xf6, 0x30, 0xff, 0x4f, 0x60, 0x27, 0x43, 0x93, 0x0c, 0x60, 0x0f, 0xea, 0xd2, 0x35,
-	0xae, 0x2d, 0x17, 0x57, 0xc5, 0x65, 0xff, 0x5d, 0x82, 0xee, 0xd9, 0x62, 0xea, 0x32, 0xac, 0x48,
-	0x77, 0x38, 0xf5, 0x18, 0xaa, 0xbc, 0xa3, 0x48, 0x2c, 0xd6, 0x85, 0x6e, 0xd1, 0x76, 0x0e, 0xe2,
-	0x5f, 0x47, 0xd0, 0xd1, 0x53, 0xa8, 0x5d, 0xb9, 0x7e, 0x84, 0x29, 0x07, 0x22, 0x41, 0x4d, 0x72,
-	0xf2, 0x76, 0xe4, 0x48, 0x0e, 0xb4, 0x0d, 0xf5, 0x69, 0x78, 0x1d, 0xf7, 0x13, 0x7e, 0x05, 0x1b,
-	0x4e, 0x6d, 0x1a, 0x5e, 0x3b, 0x51, 0x80, 0xde, 0x87, 0xb5, 0xa9, 0x47, 0xdd, 0x73, 0x1f, 0x8f,
-	0x2f, 0x09, 0x79, 0x43, 0xf9, 0x2d, 0x6c, 0x38, 0xab, 0xf2, 0xf0, 0x28, 0x3e, 0x43, 0x56, 0x5c,
-	0x49, 0x93, 0x10, 0xbb, 0x0c, 0x9b, 0x35, 0x4e, 0x4f, 0xf6, 0x31, 0x86, 0xcc, 0x9b, 0x63, 0x12,
-	0x31, 0x7e, 0x75, 0xca, 0x8e, 0xda, 0xa2, 0xf7, 0x60, 0x35, 0xc4, 0x14, 0xb3, 0xb1, 0xf4, 0xb2,
-	0xc1, 0x25, 0x5b, 0xfc, 0xec, 0xb5, 0x70, 0x0b, 0x41, 0xe5, 0x57, 0xd7, 0x63, 0x66, 0x93, 0x93,
-	0xf8, 0x5a, 0x88, 0x45, 0x14, 0x2b, 0x31, 0x50, 0x62, 0x11, 0xc5, 0x52, 0xac, 0x0b, 0xd5, 0x19,
-	0x09, 0x27, 0xd8, 0x6c, 0x71, 0x9a, 0xd8, 0xd8, 0x47, 0xb0, 0xb9, 0x04, 0xf2, 0x43, 0xf3, 0xf5,
-	0x8f, 0x01, 0x5b, 0x0e, 0xf1, 0xfd, 0x73, 0x77, 0xf2, 0xa6, 0x40, 0xc6, 0x52, 0xe0, 0x96, 0xee,
-	0x06, 0xb7, 0x9c, 0x01, 0x6e, 0xaa, 0x08, 0x2b, 0x5a, 0x11, 0x6a, 0xb0, 0x57, 0xf3, 0x61, 0xaf,
-	0xe9, 0xb0, 0x2b, 0x4c, 0xeb, 0x29, 0x4c, 0x13, 0xc0, 0x1a, 0x69, 0xc0, 0xbe, 0x86, 0xed, 0x5b,
-	0x51, 0x3e, 0x14, 0xb2, 0x3f, 0x4a, 0xb0, 0x79, 0x1c, 0x50, 0xe6, 0xfa, 0xfe, 0x12, 0x62, 0x49,
-	0x3d, 0x1b, 0x85, 0xeb, 0xb9, 0xf4, 0x5f, 0xea, 0xb9, 0xac, 0x41, 0xae, 0xf2, 0x53, 0x49, 0xe5,
-	0xa7, 0x50, 0x8d, 0x6b, 0x9d, 0xa5, 0xb6, 0xd4, 0x59, 0xd0, 0x3b, 0x00, 0xa2, 0x28, 0xb9, 0x72,
-	0x01, 0x6d, 0x93, 0x9f, 0x9c, 0xca, 0x46, 0xa2, 0xb2, 0xd1, 0xc8, 0xce, 0x46, 0xaa, 0xc2, 0xed,
-	0x63, 0xd8, 0x5a, 0x86, 0xea, 0xa1, 0xb0, 0xff, 0x66, 0xc0, 0xf6, 0x59, 0xe0, 0x65, 0x02, 0x9f,
-	0x55, 0xaa, 0xb7, 0xa0, 0x28, 0x65, 0x40, 0xd1, 0x85, 0xea, 0x22, 0x0a, 0x2f, 0xb0, 0x84, 0x56,
-	0x6c, 0xd2, 0x31, 0x56, 0xb4, 0x18, 0xed, 0x31, 0x98, 0xb7, 0x7d, 0x78, 0x60, 0x44, 0xb1, 0xd7,
-	0xc9, 0x4b, 0xd0, 0x14, 0x5d, 0xdf, 0xde, 0x80, 0xf5, 0x43, 0xcc, 0x5e, 0x8b, 0x6b, 0x21, 0xc3,
-	0xb3, 0x87, 0x80, 0xd2, 0x87, 0x37, 0xf6, 0xe4, 0x91, 0x6e, 0x4f, 0x8d, 0x45, 0x8a, 0x5f, 0x71,
-	0xd9, 0x5f, 0x70, 0xdd, 0x47, 0x1e, 0x65, 0x24, 0xbc, 0xbe, 0x0b, 0xba, 0x0e, 0x94, 0xe7, 0xee,
-	0x5b, 0xf9, 0x50, 0xc4, 0x4b, 0xfb, 0x90, 0x7b, 0x90, 0x88, 0x4a, 0x0f, 0xd2, 0xcf, 0xae, 0x51,
-	0xec, 0xd9, 0xfd, 0x01, 0xd0, 0x2b, 0x9c, 0x4c, 0x00, 0xf7, 0xbc, 0x58, 0x2a, 0x09, 0x25, 0xbd,
-	0xd0, 0x4c, 0xa8, 0x4f, 0x7c, 0xec, 0x06, 0xd1, 0x42, 0xa6, 0x4d, 0x6d, 0xed, 0x1f, 0x61, 0x43,
-	0xd3, 0x2e, 0xfd, 0x8c, 0xe3, 0xa1, 0x17, 0x52, 0x7b, 0xbc, 0x44, 0x9f, 0x43, 0x4d, 0x8c, 0x45,
-	0x5c, 0x77, 0x7b, 0xf0, 0x48, 0xf7, 0x9b, 0x2b, 0x89, 0x02, 0x39, 0x47, 0x39, 0x92, 0x77, 0xf0,
-	0x57, 0x03, 0xda, 0xea, 0xa1, 0x17, 0x43, 0x1b, 0xf2, 0x60, 0x35, 0x3d, 0xd1, 0xa0, 0x27, 0xf9,
-	0x33, 0xdd, 0xd2, 0x60, 0x6a, 0x3d, 0x2d, 0xc2, 0x2a, 0x22, 0xb0, 0x57, 0x3e, 0x31, 0x10, 0x85,
-	0xce, 0xf2, 0xa0, 0x81, 0x9e, 0x65, 0xeb, 0xc8, 0x99, 0x6c, 0xac, 0x7e, 0x51, 0x76, 0x65, 0x16,
-	0x5d, 0xf1, 0x9a, 0xd1, 0xa7, 0x03, 0x74, 0xaf, 0x1a, 0x7d, 0x20, 0xb1, 0xf6, 0x0a, 0xf3, 0x27,
-	0x76, 0x7f, 0x86, 0x35, 0xed, 0x85, 0x43, 0x39, 0x68, 0x65, 0xcd, 0x1a, 0xd6, 0x47, 0x85, 0x78,
-	0x13, 0x5b, 0x73, 0x68, 0xeb, 0x4d, 0x0a, 0xe5, 0x28, 0xc8, 0xec, 0xfa, 0xd6, 0xc7, 0xc5, 0x98,
-	0x13, 0x73, 0x14, 0x3a, 0xcb, 0x3d, 0x24, 0x2f, 0x8f, 0x39, 0xfd, 0x2e, 0x2f, 0x8f, 0x79, 0xad,
-	0xc9, 0x5e, 0x41, 0x2e, 0xc0, 0x4d, 0x0b, 0x41, 0x8f, 0x73, 0x13, 0xa2, 0x77, 0x1e, 0xab, 0x77,
-	0x3f, 0x63, 0x62, 0x62, 0x01, 0xff, 0x5b, 0x7a, 0x63, 0x51, 0x0e, 0x34, 0xd9, 0x03, 0x87, 0xf5,
-	0xac, 0x20, 0xf7, 0x52, 0x50, 0xb2, 0x2b, 0xdd, 0x11, 0x94, 0xde, 0xf2, 0xee, 0x08, 0x6a, 0xa9,
-	0xc1, 0xd9, 0x2b, 0xc8, 0x83, 0xb6, 0x13, 0x05, 0xd2, 0x74, 0xdc, 0x16, 0x50, 0x8e, 0xf4, 0xed,
-	0xae, 0x66, 0x3d, 0x29, 0xc0, 0x79, 0x73, 0xbf, 0x9f, 0xc3, 0xf7, 0x0d, 0xc5, 0x7a, 0x5e, 0xe3,
-	0xff, 0x69, 0x3f, 0xfb, 0x37, 0x00, 0x00, 0xff, 0xff, 0xf3, 0x7c, 0x9c, 0x49, 0xc1, 0x0f, 0x00,
-	0x00,
+	// 1350 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xcd, 0x72, 0xdc, 0xc4,
+	0x13, 0x8f, 0xf6, 0x7b, 0x7b, 0x1d, 0x67, 0x3d, 0xde, 0xd8, 0x8a, 0xfe, 0xf9, 0x83, 0x11, 0x05,
+	0xd9, 0x04, 0xb2, 0x86, 0x85, 0x0b, 0x55, 0x14, 0x55, 0xce, 0xc6, 0x65, 0x07, 0x8c, 0x53, 0x25,
+	0x27, 0xa1, 0x8a, 0x02, 0xb6, 0xe4, 0xd5, 0xac, 0x23, 0xa2, 0x95, 0x16, 0xcd, 0xc8, 0xc4, 0x47,
+	0x8e, 0x54, 0xf1, 0x16, 0x9c, 0x78, 0x00, 0xee, 0x1c, 0x78, 0x13, 0x78, 0x10, 0x6a, 0xbe, 0x64,
+	0x8d, 0x2c, 0xd9, 0xc2, 0x17, 0x2e, 0xab, 0x99, 0xe9, 0x9e, 0xfe, 0xf8, 0x75, 0x4f, 0x77, 0xdb,
+	0x60, 0xbd, 0x74, 0x97, 0xfe, 0x36, 0xc1, 0xf1, 0xa9, 0x3f, 0xc3, 0x64, 0x9b, 0xfa, 0x41, 0x80,
+	0xe3, 0xd1, 0x32, 0x8e, 0x68, 0x84, 0x06, 0x8c, 0x36, 0x52, 0xb4, 0x91, 0xa0, 0x59, 0x1b, 0xfc,
+	0xc6, 0xec, 0xa5, 0x1b, 0x53, 0xf1, 0x2b, 0xb8, 0xad, 0xcd, 0xec, 0x79, 0x14, 0xce, 0xfd, 0x13,
+	0x49, 0x10, 0x2a, 0x62, 0x1c, 0x60, 0x97, 0x60, 0xf5, 0xd5, 0x2e, 0x29, 0x9a, 0x1f, 0xce, 0x23,
+	0x49, 0xf8, 0x9f, 0x46, 0xa0, 0x98, 0xd0, 0x69, 0x9c, 0x84, 0x92, 0x78, 0x47, 0x23, 0x12, 0xea,
+	0xd2, 0x84, 0x68, 0xca, 0x4e, 0x71, 0x4c, 0xfc, 0x28, 0x54, 0x5f, 0x41, 0xb3, 0xff, 0xa8, 0xc1,
+	0xfa, 0x81, 0x4f, 0xa8, 0x23, 0x2e, 0x12, 0x07, 0xff, 0x90, 0x60, 0x42, 0xd1, 0x00, 0x9a, 0x81,
+	0xbf, 0xf0, 0xa9, 0x69, 0x6c, 0x19, 0xc3, 0xba, 0x23, 0x36, 0x68, 0x03, 0x5a, 0xd1, 0x7c, 0x4e,
+	0x30, 0x35, 0x6b, 0x5b, 0xc6, 0xb0, 0xeb, 0xc8, 0x1d, 0xfa, 0x0c, 0xda, 0x24, 0x8a, 0xe9, 0xf4,
+	0xf8, 0xcc, 0xac, 0x6f, 0x19, 0xc3, 0xd5, 0xf1, 0x3b, 0xa3, 0x22, 0x9c, 0x46, 0x4c, 0xd3, 0x51,
+	0x14, 0xd3, 0x11, 0xfb, 0x79, 0x74, 0xe6, 0xb4, 0x08, 0xff, 0x32, 0xb9, 0x73, 0x3f, 0xa0, 0x38,
+	0x36, 0x1b, 0x42, 0xae, 0xd8, 0xa1, 0x3d, 0x00, 0x2e, 0x37, 0x8a, 0x3d, 0x1c, 0x9b, 0x4d, 0x2e,
+	0x7a, 0x58, 0x41, 0xf4, 0x53, 0xc6, 0xef, 0x74, 0x89, 0x5a, 0xa2, 0x4f, 0x61, 0x45, 0x40, 0x32,
+	0x9d, 0x45, 0x1e, 0x26, 0x66, 0x6b, 0xab, 0x3e, 0x5c, 0x1d, 0xdf, 0x11, 0xa2, 0x14, 0xfc, 0x47,
+	0x02, 0xb4, 0x49, 0xe4, 0x61, 0xa7, 0x27, 0xd8, 0xd9, 0x9a, 0xa0, 0xbb, 0xd0, 0x0d, 0xdd, 0x05,
+	0x26, 0x4b, 0x77, 0x86, 0xcd, 0x36, 0xb7, 0xf0, 0xfc, 0xc0, 0xfe, 0x0e, 0x3a, 0x4a, 0xb9, 0x3d,
+	0x86, 0x96, 0x70, 0x0d, 0xf5, 0xa0, 0xfd, 0xfc, 0xf0, 0x8b, 0xc3, 0xa7, 0x5f, 0x1d, 0xf6, 0x6f,
+	0xa0, 0x0e, 0x34, 0x0e, 0x77, 0xbe, 0xdc, 0xed, 0x1b, 0x68, 0x0d, 0x6e, 0x1e, 0xec, 0x1c, 0x3d,
+	0x9b, 0x3a, 0xbb, 0x07, 0xbb, 0x3b, 0x47, 0xbb, 0x8f, 0xfb, 0x35, 0xfb, 0x0d, 0xe8, 0xa6, 0x36,
+	0xa3, 0x36, 0xd4, 0x77, 0x8e, 0x26, 0xe2, 0xca, 0xe3, 0xdd, 0xa3, 0x49, 0xdf, 0xb0, 0x7f, 0x36,
+	0x60, 0xa0, 0x87, 0x88, 0x2c, 0xa3, 0x90, 0x60, 0x16, 0xa3, 0x59, 0x94, 0x84, 0x69, 0x8c, 0xf8,
+	0x06, 0x21, 0x68, 0x84, 0xf8, 0xb5, 0x8a, 0x10, 0x5f, 0x33, 0x4e, 0x1a, 0x51, 0x37, 0xe0, 0xd1,
+	0xa9, 0x3b, 0x62, 0x83, 0x3e, 0x84, 0x8e, 0x74, 0x9d, 0x98, 0x8d, 0xad, 0xfa, 0xb0, 0x37, 0xbe,
+	0xad, 0x03, 0x22, 0x35, 0x3a, 0x29, 0x9b, 0xbd, 0x07, 0x9b, 0x7b, 0x58, 0x59, 0x22, 0xf0, 0x52,
+	0x19, 0xc3, 0xf4, 0xba, 0x0b, 0x6c, 0x1a, 0x52, 0xaf, 0xbb, 0xc0, 0xc8, 0x84, 0xb6, 0x4c, 0x37,
+	0x6e, 0x4e, 0xd3, 0x51, 0x5b, 0x9b, 0x82, 0x79, 0x51, 0x90, 0xf4, 0xab, 0x48, 0xd2, 0xbb, 0xd0,
+	0x60, 0x2f, 0x81, 0x8b, 0xe9, 0x8d, 0x91, 0x6e, 0xe7, 0x93, 0x70, 0x1e, 0x39, 0x9c, 0xae, 0x87,
+	0xaa, 0x9e, 0x0f, 0xd5, 0x7e, 0x56, 0xeb, 0x24, 0x0a, 0x29, 0x0e, 0xe9, 0xf5, 0xec, 0x3f, 0x80,
+	0x3b, 0x05, 0x92, 0xa4, 0x03, 0xdb, 0xd0, 0x96, 0xa6, 0x71, 0x69, 0xa5, 0xb8, 0x2a, 0x2e, 0xfb,
+	0xaf, 0x1a, 0x0c, 0x9e, 0x2f, 0x3d, 0x97, 0x62, 0x45, 0xba, 0xc4, 0xa8, 0x7b, 0xd0, 0xe4, 0x15,
+	0x45, 0x62, 0xb1, 0x26, 0x64, 0xf3, 0xa3, 0xd1, 0x84, 0xfd, 0x3a, 0x82, 0x8e, 0x1e, 0x40, 0xeb,
+	0xd4, 0x0d, 0x12, 0x4c, 0xcc, 0x7a, 0x16, 0x35, 0xc9, 0xc9, 0xcb, 0x91, 0x23, 0x39, 0xd0, 0x26,
+	0xb4, 0xbd, 0xf8, 0x8c, 0xd5, 0x13, 0xfe, 0x04, 0x3b, 0x4e, 0xcb, 0x8b, 0xcf, 0x9c, 0x24, 0x44,
+	0x6f, 0xc3, 0x4d, 0xcf, 0x27, 0xee, 0x71, 0x80, 0xa7, 0x2f, 0xa3, 0xe8, 0x15, 0xe1, 0xaf, 0xb0,
+	0xe3, 0xac, 0xc8, 0xc3, 0x7d, 0x76, 0x86, 0x2c, 0x96, 0x49, 0xb3, 0x18, 0xbb, 0x14, 0x9b, 0x2d,
+	0x4e, 0x4f, 0xf7, 0x0c, 0x43, 0xea, 0x2f, 0x70, 0x94, 0x50, 0xfe, 0x74, 0xea, 0x8e, 0xda, 0xa2,
+	0xb7, 0x60, 0x25, 0xc6, 0x04, 0xd3, 0xa9, 0xb4, 0xb2, 0xc3, 0x6f, 0xf6, 0xf8, 0xd9, 0x0b, 0x61,
+	0x16, 0x82, 0xc6, 0x8f, 0xae, 0x4f, 0xcd, 0x2e, 0x27, 0xf1, 0xb5, 0xb8, 0x96, 0x10, 0xac, 0xae,
+	0x81, 0xba, 0x96, 0x10, 0x2c, 0xaf, 0x0d, 0xa0, 0x39, 0x8f, 0xe2, 0x19, 0x36, 0x7b, 0x9c, 0x26,
+	0x36, 0xf6, 0x3e, 0xdc, 0xce, 0x81, 0x7c, 0xdd, 0x78, 0xfd, 0x6d, 0xc0, 0x86, 0x13, 0x05, 0xc1,
+	0xb1, 0x3b, 0x7b, 0x55, 0x21, 0x62, 0x19, 0x70, 0x6b, 0x97, 0x83, 0x5b, 0x2f, 0x00, 0x37, 0x93,
+	0x84, 0x0d, 0x2d, 0x09, 0x35, 0xd8, 0x9b, 0xe5, 0xb0, 0xb7, 0x74, 0xd8, 0x15, 0xa6, 0xed, 0x0c,
+	0xa6, 0x29, 0x60, 0x9d, 0x2c, 0x60, 0x9f, 0xc3, 0xe6, 0x05, 0x2f, 0xaf, 0x0b, 0xd9, 0x6f, 0x35,
+	0xb8, 0xfd, 0x24, 0x24, 0xd4, 0x0d, 0x82, 0x1c, 0x62, 0x69, 0x3e, 0x1b, 0x95, 0xf3, 0xb9, 0xf6,
+	0x6f, 0xf2, 0xb9, 0xae, 0x41, 0xae, 0xe2, 0xd3, 0xc8, 0xc4, 0xa7, 0x52, 0x8e, 0x6b, 0x95, 0xa5,
+	0x95, 0xab, 0x2c, 0xe8, 0xff, 0x00, 0x22, 0x29, 0xb9, 0x70, 0x01, 0x6d, 0x97, 0x9f, 0x1c, 0xca,
+	0x42, 0xa2, 0xa2, 0xd1, 0x29, 0x8e, 0x46, 0x26, 0xc3, 0xed, 0x27, 0xb0, 0x91, 0x87, 0xea, 0xba,
+	0xb0, 0xff, 0x6a, 0xc0, 0xe6, 0xf3, 0xd0, 0x2f, 0x04, 0xbe, 0x28, 0x55, 0x2f, 0x40, 0x51, 0x2b,
+	0x80, 0x62, 0x00, 0xcd, 0x65, 0x12, 0x9f, 0x60, 0x09, 0xad, 0xd8, 0x64, 0x7d, 0x6c, 0xe8, 0x3e,
+	0xbe, 0x09, 0x3d, 0x0f, 0x07, 0x98, 0xe2, 0xe9, 0x2c, 0xf6, 0x14, 0xba, 0x20, 0x8e, 0x26, 0xb1,
+	0x47, 0xec, 0x29, 0x98, 0x17, 0x8d, 0xbc, 0xa6, 0xcb, 0xcc, 0xad, 0xb4, 0x55, 0x74, 0x45, 0x5b,
+	0xb0, 0xd7, 0x61, 0x6d, 0x0f, 0xd3, 0x17, 0xe2, 0xdd, 0x48, 0xff, 0xed, 0x5d, 0x40, 0xd9, 0xc3,
+	0x73, 0x7d, 0xf2, 0x48, 0xd7, 0xa7, 0xe6, 0x26, 0xc5, 0xaf, 0xb8, 0xec, 0x4f, 0xb8, 0xec, 0x7d,
+	0x9f, 0xd0, 0x28, 0x3e, 0xbb, 0x0c, 0xdb, 0x3e, 0xd4, 0x17, 0xee, 0x6b, 0xd9, 0x49, 0xd8, 0xd2,
+	0xde, 0x03, 0x94, 0xbd, 0x2a, 0x2d, 0xc8, 0xf6, 0x65, 0xa3, 0x5a, 0x5f, 0xfe, 0x06, 0xd0, 0x33,
+	0x9c, 0x8e, 0x08, 0x57, 0xb4, 0x34, 0x15, 0xa5, 0x9a, 0x1e, 0x25, 0x13, 0xda, 0xb3, 0x00, 0xbb,
+	0x61, 0xb2, 0x94, 0x71, 0x55, 0x5b, 0xfb, 0x5b, 0x58, 0xd7, 0xa4, 0x4b, 0x3b, 0x99, 0x3f, 0xe4,
+	0x44, 0x4a, 0x67, 0x4b, 0xf4, 0x31, 0xb4, 0xc4, 0xdc, 0xc4, 0x65, 0xaf, 0x8e, 0xef, 0xea, 0x76,
+	0x73, 0x21, 0x49, 0x28, 0x07, 0x2d, 0x47, 0xf2, 0xda, 0xbf, 0x1b, 0xb0, 0xbe, 0xe3, 0x45, 0x4b,
+	0xfa, 0x5f, 0x35, 0x3f, 0xed, 0x69, 0x37, 0xf2, 0x4f, 0x3b, 0x53, 0x4a, 0x9a, 0xd9, 0x52, 0x62,
+	0xff, 0x62, 0xc0, 0x40, 0xb7, 0xfb, 0xba, 0x29, 0x3b, 0x81, 0x6e, 0x8c, 0x49, 0x94, 0xc4, 0x33,
+	0x5e, 0xdc, 0x58, 0xc8, 0x4b, 0x26, 0x68, 0xae, 0x0f, 0x7b, 0x8e, 0xe4, 0x76, 0xce, 0xef, 0xd9,
+	0x3f, 0x19, 0x70, 0x2b, 0x47, 0x66, 0x10, 0xbe, 0xf2, 0x43, 0x4f, 0x41, 0xc8, 0xd6, 0x29, 0xac,
+	0xb5, 0x0c, 0xac, 0x97, 0x8e, 0x4d, 0xa2, 0x3b, 0x24, 0xa1, 0x27, 0x47, 0x03, 0xb1, 0x61, 0x72,
+	0x3c, 0x7f, 0x3e, 0xe7, 0xa0, 0x74, 0x1d, 0xbe, 0x1e, 0xff, 0xd9, 0x85, 0x55, 0x35, 0xd4, 0x09,
+	0xcb, 0x91, 0x0f, 0x2b, 0xd9, 0xe9, 0x15, 0xdd, 0x2f, 0x9f, 0xdf, 0x73, 0x7f, 0x84, 0x58, 0x0f,
+	0xaa, 0xb0, 0x0a, 0xcc, 0xed, 0x1b, 0x1f, 0x18, 0x88, 0x40, 0x3f, 0x3f, 0x54, 0xa2, 0x87, 0xc5,
+	0x32, 0x4a, 0xa6, 0x58, 0x6b, 0x54, 0x95, 0x5d, 0xa9, 0x45, 0xa7, 0xb0, 0x76, 0x4e, 0x95, 0x93,
+	0x20, 0xba, 0x52, 0x8c, 0x3e, 0x7c, 0x5a, 0xdb, 0x95, 0xf9, 0x53, 0xbd, 0xdf, 0xc3, 0x4d, 0x6d,
+	0x9a, 0x41, 0x25, 0x68, 0x15, 0xcd, 0x95, 0xd6, 0x7b, 0x95, 0x78, 0x53, 0x5d, 0x0b, 0x58, 0xd5,
+	0x1b, 0x12, 0x2a, 0x11, 0x50, 0xd8, 0xe1, 0xad, 0xf7, 0xab, 0x31, 0xa7, 0xea, 0x08, 0xf4, 0xf3,
+	0xed, 0xa0, 0x2c, 0x8e, 0x25, 0xbd, 0xcd, 0x1a, 0x55, 0x65, 0x4f, 0x95, 0xba, 0x00, 0xe7, 0xdd,
+	0x00, 0xdd, 0x2b, 0x0d, 0x88, 0xde, 0x44, 0xac, 0xe1, 0xd5, 0x8c, 0xa9, 0x8a, 0x25, 0xdc, 0xca,
+	0xcd, 0x53, 0xa8, 0x04, 0x9a, 0xe2, 0xe1, 0xd2, 0x7a, 0x58, 0x91, 0x3b, 0xe7, 0x94, 0x6c, 0x30,
+	0x97, 0x38, 0xa5, 0x77, 0x2f, 0x6b, 0x78, 0x35, 0x63, 0xaa, 0xc2, 0x87, 0x55, 0x27, 0x09, 0xa5,
+	0x6a, 0x56, 0xe1, 0x51, 0xc9, 0xed, 0x8b, 0x0d, 0xca, 0xba, 0x5f, 0x81, 0x33, 0xf3, 0xbe, 0x4f,
+	0x60, 0x25, 0x5b, 0x6f, 0xcb, 0x4a, 0x49, 0x41, 0x2f, 0xb1, 0x1e, 0x54, 0x61, 0x55, 0xaa, 0x1e,
+	0xc1, 0xd7, 0x1d, 0xc5, 0x79, 0xdc, 0xe2, 0xff, 0x28, 0xf9, 0xe8, 0x9f, 0x01, 0x00, 0xf6, 0xa1,
+	0xa2, 0xc1, 0x16, 0x12, 0x00, 0x00,
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// messageHeader is the armor header of an encrypted PGP message.
+const messageHeader = "-----BEGIN PGP MESSAGE-----"
+
+// IsEncrypted returns true if data is an ASCII armored PGP message.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte(messageHeader))
+}
+
+// Encrypt encrypts data for the keys in the keyring matching the given ids.
+//
+// If no ids are given, the data is encrypted for the Signatory's Entity. The
+// result is an ASCII armored PGP message.
+func (s *Signatory) Encrypt(data []byte, ids ...string) ([]byte, error) {
+	var to openpgp.EntityList
+	for _, id := range ids {
+		e, err := findEntity(s.KeyRing, id)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return nil, fmt.Errorf("no key found for recipient %q", id)
+		}
+		to = append(to, e)
+	}
+	if len(to) == 0 {
+		if s.Entity == nil {
+			return nil, errors.New("no recipients given")
+		}
+		to = append(to, s.Entity)
+	}
+
+	out := bytes.NewBuffer(nil)
+	w, err := armor.Encode(out, "PGP MESSAGE", nil)
+	if err != nil {
+		return nil, err
+	}
+	pw, err := openpgp.Encrypt(w, to, nil, nil, &defaultPGPConfig)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := pw.Close(); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// Decrypt decrypts an ASCII armored PGP message with the private keys in the keyring.
+//
+// Private keys that are protected by a passphrase are unlocked with the
+// passphrase returned by fn.
+func (s *Signatory) Decrypt(message []byte, fn PassphraseFetcher) ([]byte, error) {
+	block, err := armor.Decode(bytes.NewBuffer(bytes.TrimSpace(message)))
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PGP MESSAGE" {
+		return nil, fmt.Errorf("expected a PGP MESSAGE block, got %q", block.Type)
+	}
+
+	prompt := func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if symmetric {
+			return nil, errors.New("symmetrically encrypted messages are not supported")
+		}
+		for _, k := range keys {
+			if k.PrivateKey == nil || !k.PrivateKey.Encrypted {
+				continue
+			}
+			p, err := fn(entityName(k.Entity))
+			if err != nil {
+				return nil, err
+			}
+			if err := k.PrivateKey.Decrypt(p); err == nil {
+				return nil, nil
+			}
+		}
+		return nil, errors.New("could not unlock a private key for this message")
+	}
+
+	md, err := openpgp.ReadMessage(block.Body, s.KeyRing, prompt, &defaultPGPConfig)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(md.UnverifiedBody)
+}
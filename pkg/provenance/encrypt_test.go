@@ -0,0 +1,107 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	pub, err := NewFromKeyring(testPubfile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := []byte("password: hunter2\n")
+	msg, err := pub.Encrypt(plain, "helm-testing@helm.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(msg) {
+		t.Fatalf("expected an armored PGP message, got %q", msg)
+	}
+
+	sec, err := NewFromKeyring(testKeyfile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := sec.Decrypt(msg, func(string) ([]byte, error) {
+		return nil, errors.New("key has no passphrase")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(plain) {
+		t.Errorf("expected %q, got %q", plain, out)
+	}
+}
+
+func TestDecryptWithPassphrase(t *testing.T) {
+	s, err := NewFromKeyring(testPasswordKeyfile, testPasswordKeyName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := []byte("secret: value\n")
+	msg, err := s.Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh keyring, so the private key is still locked.
+	s, err = NewFromKeyring(testPasswordKeyfile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Decrypt(msg, func(string) ([]byte, error) {
+		return []byte("secret0"), nil
+	}); err == nil {
+		t.Error("expected decryption with a bad passphrase to fail")
+	}
+
+	s, err = NewFromKeyring(testPasswordKeyfile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var asked string
+	out, err := s.Decrypt(msg, func(name string) ([]byte, error) {
+		asked = name
+		return []byte("secret"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asked != testPasswordKeyName {
+		t.Errorf("expected passphrase request for %q, got %q", testPasswordKeyName, asked)
+	}
+	if string(out) != string(plain) {
+		t.Errorf("expected %q, got %q", plain, out)
+	}
+}
+
+func TestEncryptUnknownRecipient(t *testing.T) {
+	s, err := NewFromKeyring(testPubfile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Encrypt([]byte("a: b"), "nobody@example.com"); err == nil {
+		t.Error("expected an error for an unknown recipient")
+	}
+	if _, err := s.Encrypt([]byte("a: b")); err == nil {
+		t.Error("expected an error without recipients")
+	}
+}
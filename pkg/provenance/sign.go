@@ -122,6 +122,13 @@ func NewFromKeyring(keyringfile, id string) (*Signatory, error) {
 		return s, nil
 	}
 
+	e, err := findEntity(ring, id)
+	s.Entity = e
+	return s, err
+}
+
+// findEntity returns the entity in the ring whose identity matches id.
+func findEntity(ring openpgp.EntityList, id string) (*openpgp.Entity, error) {
 	// We're gonna go all GnuPG on this and look for a string that _contains_. If
 	// two or more keys contain the string and none are a direct match, we error
 	// out.
@@ -130,8 +137,7 @@ func NewFromKeyring(keyringfile, id string) (*Signatory, error) {
 	for _, e := range ring {
 		for n := range e.Identities {
 			if n == id {
-				s.Entity = e
-				return s, nil
+				return e, nil
 			}
 			if strings.Contains(n, id) {
 				if candidate != nil {
@@ -142,11 +148,9 @@ func NewFromKeyring(keyringfile, id string) (*Signatory, error) {
 		}
 	}
 	if vague {
-		return s, fmt.Errorf("more than one key contain the id %q", id)
+		return nil, fmt.Errorf("more than one key contain the id %q", id)
 	}
-
-	s.Entity = candidate
-	return s, nil
+	return candidate, nil
 }
 
 // PassphraseFetcher returns a passphrase for decrypting keys.
@@ -179,15 +183,7 @@ func (s *Signatory) DecryptKey(fn PassphraseFetcher) error {
 		return nil
 	}
 
-	fname := "Unknown"
-	for i := range s.Entity.Identities {
-		if i != "" {
-			fname = i
-			break
-		}
-	}
-
-	p, err := fn(fname)
+	p, err := fn(entityName(s.Entity))
 	if err != nil {
 		return err
 	}
@@ -195,6 +191,16 @@ func (s *Signatory) DecryptKey(fn PassphraseFetcher) error {
 	return s.Entity.PrivateKey.Decrypt(p)
 }
 
+// entityName returns the first non-empty identity of an entity.
+func entityName(e *openpgp.Entity) string {
+	for i := range e.Identities {
+		if i != "" {
+			return i
+		}
+	}
+	return "Unknown"
+}
+
 // ClearSign signs a chart with the given key.
 //
 // This takes the path to a chart archive file and a key, and it returns a clear signature.
@@ -0,0 +1,274 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const (
+	// ManifestMediaType is the media type of the manifest of a chart.
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ConfigMediaType is the media type of the blob that holds the chart metadata.
+	ConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+	// ChartLayerMediaType is the media type of the blob that holds the chart archive.
+	ChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// digestRegexp matches the digests of blobs and manifests. Digests are used
+// as file names in the cache, so nothing else may be accepted.
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ErrNotFound indicates that a chart is not in the cache.
+var ErrNotFound = errors.New("chart not found in the cache")
+
+// Descriptor describes a blob of a manifest.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest is an OCI image manifest.
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+// chartLayer returns the layer that holds the chart archive.
+func (m *Manifest) chartLayer() (Descriptor, error) {
+	if m.Config.MediaType != ConfigMediaType {
+		return Descriptor{}, fmt.Errorf("not a chart: unexpected config media type %q", m.Config.MediaType)
+	}
+	for _, l := range m.Layers {
+		if l.MediaType == ChartLayerMediaType {
+			return l, nil
+		}
+	}
+	return Descriptor{}, errors.New("not a chart: no layer holds a chart archive")
+}
+
+// Digest returns the digest of data.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Cache is a local content-addressed store of charts.
+//
+// Manifests and blobs are stored by digest under blobs/, and the digest of
+// the manifest of each reference is stored under refs/.
+type Cache struct {
+	root string
+}
+
+// NewCache returns a cache rooted at the given directory, which is usually
+// helmpath.Home.Registry(). The directory is created when needed.
+func NewCache(root string) *Cache {
+	return &Cache{root: root}
+}
+
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.root, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+func (c *Cache) refPath(ref *Reference) string {
+	// Ports are separated by ':', which is not allowed in Windows paths.
+	registry := strings.Replace(ref.Registry, ":", "_", -1)
+	return filepath.Join(c.root, "refs", registry, filepath.FromSlash(ref.Repository), ref.Tag)
+}
+
+// SaveChart stores a chart in the cache under the given reference, and
+// returns the digest of its manifest.
+func (c *Cache) SaveChart(ch *chart.Chart, ref *Reference) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("%s: a tag is required", ref)
+	}
+	tmp, err := ioutil.TempDir("", "helm-registry-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	name, err := chartutil.Save(ch, tmp)
+	if err != nil {
+		return "", err
+	}
+	archive, err := ioutil.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	config, err := json.Marshal(ch.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	m := &Manifest{SchemaVersion: 2}
+	if m.Config, err = c.storeBlob(ConfigMediaType, config); err != nil {
+		return "", err
+	}
+	layer, err := c.storeBlob(ChartLayerMediaType, archive)
+	if err != nil {
+		return "", err
+	}
+	m.Layers = []Descriptor{layer}
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	d, err := c.storeBlob(ManifestMediaType, raw)
+	if err != nil {
+		return "", err
+	}
+	return d.Digest, c.tag(ref, d.Digest)
+}
+
+// ChartArchive returns the metadata and the archive of the chart stored
+// under the given reference.
+func (c *Cache) ChartArchive(ref *Reference) (*chart.Metadata, []byte, error) {
+	m, _, _, err := c.manifest(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	layer, err := m.chartLayer()
+	if err != nil {
+		return nil, nil, err
+	}
+	config, err := c.blob(m.Config.Digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	md := &chart.Metadata{}
+	if err := json.Unmarshal(config, md); err != nil {
+		return nil, nil, fmt.Errorf("%s: invalid chart metadata: %s", ref, err)
+	}
+	archive, err := c.blob(layer.Digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return md, archive, nil
+}
+
+// LoadChart loads the chart stored under the given reference.
+func (c *Cache) LoadChart(ref *Reference) (*chart.Chart, error) {
+	_, archive, err := c.ChartArchive(ref)
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.LoadArchive(bytes.NewReader(archive))
+}
+
+// manifest returns the manifest stored under the given reference, as well as
+// its raw content and digest.
+func (c *Cache) manifest(ref *Reference) (*Manifest, []byte, string, error) {
+	b, err := ioutil.ReadFile(c.refPath(ref))
+	if os.IsNotExist(err) {
+		return nil, nil, "", fmt.Errorf("%s: %s", ref, ErrNotFound)
+	} else if err != nil {
+		return nil, nil, "", err
+	}
+	digest := strings.TrimSpace(string(b))
+	raw, err := c.blob(digest)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, nil, "", fmt.Errorf("%s: invalid manifest: %s", ref, err)
+	}
+	return m, raw, digest, nil
+}
+
+// blob reads the blob with the given digest, and checks its content.
+func (c *Cache) blob(digest string) ([]byte, error) {
+	if !digestRegexp.MatchString(digest) {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	b, err := ioutil.ReadFile(c.blobPath(digest))
+	if err != nil {
+		return nil, err
+	}
+	if Digest(b) != digest {
+		return nil, fmt.Errorf("blob %s is corrupted", digest)
+	}
+	return b, nil
+}
+
+// hasBlob returns true if the blob with the given digest is in the cache.
+func (c *Cache) hasBlob(digest string) bool {
+	if !digestRegexp.MatchString(digest) {
+		return false
+	}
+	_, err := os.Stat(c.blobPath(digest))
+	return err == nil
+}
+
+// storeBlob stores data by its digest, and returns its descriptor.
+func (c *Cache) storeBlob(mediaType string, data []byte) (Descriptor, error) {
+	d := Descriptor{MediaType: mediaType, Digest: Digest(data), Size: int64(len(data))}
+	if c.hasBlob(d.Digest) {
+		return d, nil
+	}
+	return d, writeFileAtomic(c.blobPath(d.Digest), data)
+}
+
+// tag stores the digest of the manifest of the given reference.
+func (c *Cache) tag(ref *Reference, digest string) error {
+	return writeFileAtomic(c.refPath(ref), []byte(digest+"\n"))
+}
+
+// writeFileAtomic writes data to a temporary file that is renamed to name,
+// so that readers never see a partially written file.
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(name), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func testChart(version string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "mychart", Version: version, ApiVersion: "v1"},
+		Templates: []*chart.Template{
+			{Name: "templates/configmap.yaml", Data: []byte("kind: ConfigMap")},
+		},
+	}
+}
+
+func tempCache(t *testing.T) (*Cache, func()) {
+	dir, err := ioutil.TempDir("", "helm-registry-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewCache(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestCache(t *testing.T) {
+	cache, cleanup := tempCache(t)
+	defer cleanup()
+
+	ref := &Reference{Registry: "localhost:5000", Repository: "myrepo/mychart", Tag: "0.1.0"}
+	if _, err := cache.LoadChart(ref); err == nil || !strings.Contains(err.Error(), ErrNotFound.Error()) {
+		t.Errorf("Expected %q, got %v", ErrNotFound, err)
+	}
+
+	digest, err := cache.SaveChart(testChart("0.1.0"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _, d, err := cache.manifest(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != digest {
+		t.Errorf("Expected digest %s, got %s", digest, d)
+	}
+	if m.Config.MediaType != ConfigMediaType || len(m.Layers) != 1 || m.Layers[0].MediaType != ChartLayerMediaType {
+		t.Errorf("Unexpected manifest %+v", m)
+	}
+
+	md, _, err := cache.ChartArchive(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Name != "mychart" || md.Version != "0.1.0" {
+		t.Errorf("Unexpected metadata %v", md)
+	}
+	c, err := cache.LoadChart(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Templates) != 1 || string(c.Templates[0].Data) != "kind: ConfigMap" {
+		t.Errorf("Unexpected templates %v", c.Templates)
+	}
+
+	// A corrupted blob is detected, instead of being loaded.
+	if err := ioutil.WriteFile(cache.blobPath(m.Layers[0].Digest), []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.LoadChart(ref); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Errorf("Expected a corrupted blob, got %v", err)
+	}
+}
@@ -0,0 +1,394 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxManifestSize is the maximum size of a manifest read from a registry.
+const maxManifestSize = 4 << 20
+
+// challengeRegexp matches the parameters of a WWW-Authenticate header.
+var challengeRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Client pushes charts to, and pulls charts from, OCI registries.
+//
+// Registries on localhost are accessed over HTTP, all others over HTTPS unless
+// PlainHTTP is set.
+type Client struct {
+	// HTTPClient is the client used for all requests.
+	HTTPClient *http.Client
+	// PlainHTTP accesses all registries over HTTP.
+	PlainHTTP bool
+	// Username and Password are the credentials sent to registries and their
+	// token services. If they are empty, requests are anonymous.
+	Username string
+	Password string
+
+	mu sync.Mutex
+	// auth holds the last Authorization header that was accepted by each registry.
+	auth map[string]string
+}
+
+// NewClient returns a client that uses the default HTTP client.
+func NewClient() *Client {
+	return &Client{HTTPClient: http.DefaultClient}
+}
+
+// Push uploads the chart stored in cache under ref to the registry, and
+// returns the digest of its manifest.
+func (c *Client) Push(ref *Reference, cache *Cache) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("%s: a tag is required", ref)
+	}
+	m, raw, digest, err := cache.manifest(ref)
+	if err != nil {
+		return "", err
+	}
+	if _, err := m.chartLayer(); err != nil {
+		return "", fmt.Errorf("%s: %s", ref, err)
+	}
+
+	for _, d := range append([]Descriptor{m.Config}, m.Layers...) {
+		exists, err := c.blobExists(ref, d.Digest)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			continue
+		}
+		data, err := cache.blob(d.Digest)
+		if err != nil {
+			return "", err
+		}
+		if err := c.uploadBlob(ref, d.Digest, data); err != nil {
+			return "", err
+		}
+	}
+
+	header := http.Header{"Content-Type": {ManifestMediaType}}
+	resp, err := c.do("PUT", c.url(ref, "manifests/"+ref.Tag), header, raw)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", responseError(resp)
+	}
+	return digest, nil
+}
+
+// Pull downloads the chart at ref from the registry into cache, and returns
+// the digest of its manifest.
+func (c *Client) Pull(ref *Reference, cache *Cache) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("%s: a tag is required", ref)
+	}
+	header := http.Header{"Accept": {ManifestMediaType}}
+	resp, err := c.do("GET", c.url(ref, "manifests/"+ref.Tag), header, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError(resp)
+	}
+	raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(raw) > maxManifestSize {
+		return "", fmt.Errorf("%s: manifest is larger than %d bytes", ref, maxManifestSize)
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return "", fmt.Errorf("%s: invalid manifest: %s", ref, err)
+	}
+	layer, err := m.chartLayer()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", ref, err)
+	}
+	for _, d := range []Descriptor{m.Config, layer} {
+		if err := c.pullBlob(ref, d, cache); err != nil {
+			return "", err
+		}
+	}
+
+	d, err := cache.storeBlob(ManifestMediaType, raw)
+	if err != nil {
+		return "", err
+	}
+	return d.Digest, cache.tag(ref, d.Digest)
+}
+
+// pullBlob downloads a blob into cache, unless it is there already.
+func (c *Client) pullBlob(ref *Reference, d Descriptor, cache *Cache) error {
+	if !digestRegexp.MatchString(d.Digest) {
+		return fmt.Errorf("%s: invalid digest %q", ref, d.Digest)
+	}
+	if cache.hasBlob(d.Digest) {
+		return nil
+	}
+	resp, err := c.do("GET", c.url(ref, "blobs/"+d.Digest), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, d.Size+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != d.Size || Digest(data) != d.Digest {
+		return fmt.Errorf("%s: blob %s does not match its digest", ref, d.Digest)
+	}
+	_, err = cache.storeBlob(d.MediaType, data)
+	return err
+}
+
+func (c *Client) blobExists(ref *Reference, digest string) (bool, error) {
+	resp, err := c.do("HEAD", c.url(ref, "blobs/"+digest), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, responseError(resp)
+}
+
+// uploadBlob uploads a blob in a single request, after starting an upload session.
+func (c *Client) uploadBlob(ref *Reference, digest string, data []byte) error {
+	resp, err := c.do("POST", c.url(ref, "blobs/uploads/"), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return responseError(resp)
+	}
+	loc, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("%s: invalid upload location: %s", ref, err)
+	}
+	q := loc.Query()
+	q.Set("digest", digest)
+	loc.RawQuery = q.Encode()
+
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	resp, err = c.do("PUT", loc.String(), header, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// url returns the URL of an endpoint of the repository of ref.
+func (c *Client) url(ref *Reference, endpoint string) string {
+	scheme := "https"
+	if c.PlainHTTP || isLocalhost(ref.Registry) {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, endpoint)
+}
+
+func isLocalhost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// do sends a request. If the registry asks for authorization, the request is
+// sent again with the credentials or token that the registry asked for.
+func (c *Client) do(method, u string, header http.Header, body []byte) (*http.Response, error) {
+	req, err := newRequest(method, u, header, body)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	auth := c.auth[req.URL.Host]
+	c.mu.Unlock()
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	// Tokens are scoped to the repository and the actions of a request, so a
+	// new token may be needed even if the last one was accepted.
+	auth, err = c.authorize(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, fmt.Errorf("authorization to %s failed: %s", req.URL.Host, err)
+	}
+	if req, err = newRequest(method, u, header, body); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", auth)
+	if resp, err = c.HTTPClient.Do(req); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		c.mu.Lock()
+		if c.auth == nil {
+			c.auth = map[string]string{}
+		}
+		c.auth[req.URL.Host] = auth
+		c.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func newRequest(method, u string, header http.Header, body []byte) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return req, nil
+}
+
+// authorize returns the Authorization header that answers the challenge of
+// a WWW-Authenticate header.
+func (c *Client) authorize(challenge string) (string, error) {
+	i := strings.Index(challenge, " ")
+	if i < 0 {
+		return "", fmt.Errorf("unsupported challenge %q", challenge)
+	}
+	params := map[string]string{}
+	for _, m := range challengeRegexp.FindAllStringSubmatch(challenge[i+1:], -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+
+	switch strings.ToLower(challenge[:i]) {
+	case "basic":
+		if c.Username == "" && c.Password == "" {
+			return "", fmt.Errorf("credentials are required")
+		}
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.SetBasicAuth(c.Username, c.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		token, err := c.token(params["realm"], params["service"], params["scope"])
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+	return "", fmt.Errorf("unsupported challenge %q", challenge)
+}
+
+// token requests a token from the token service at realm.
+func (c *Client) token(realm, service, scope string) (string, error) {
+	u, err := url.Parse(realm)
+	if err != nil || !u.IsAbs() {
+		return "", fmt.Errorf("invalid token realm %q", realm)
+	}
+	q := u.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError(resp)
+	}
+
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return "", fmt.Errorf("invalid token response: %s", err)
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	if t.Token == "" {
+		return "", fmt.Errorf("no token in the response of %s", u.Host)
+	}
+	return t.Token, nil
+}
+
+// responseError returns an error for an unexpected response, including the
+// messages of the errors that registries return in the response body.
+func responseError(resp *http.Response) error {
+	var body struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	msg := resp.Status
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err == nil {
+		for _, e := range body.Errors {
+			msg += fmt.Sprintf(": %s %s", e.Code, e.Message)
+		}
+	}
+	return fmt.Errorf("%s %s failed: %s", resp.Request.Method, resp.Request.URL, msg)
+}
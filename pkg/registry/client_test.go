@@ -0,0 +1,109 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/registry/registrytest"
+)
+
+func TestPushPull(t *testing.T) {
+	srv := registrytest.NewServer()
+	defer srv.Stop()
+
+	src, cleanup := tempCache(t)
+	defer cleanup()
+	ref := &Reference{Registry: srv.Host(), Repository: "myrepo/mychart", Tag: "0.1.0"}
+	saved, err := src.SaveChart(testChart("0.1.0"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient()
+	pushed, err := c.Push(ref, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushed != saved {
+		t.Errorf("Expected digest %s, got %s", saved, pushed)
+	}
+	if n := srv.BlobCount(); n != 2 {
+		t.Errorf("Expected 2 blobs in the registry, got %d", n)
+	}
+
+	// Blobs that are in the registry already are not uploaded again.
+	other := &Reference{Registry: srv.Host(), Repository: "myrepo/mychart", Tag: "latest"}
+	if _, err := src.SaveChart(testChart("0.1.0"), other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Push(other, src); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.BlobCount(); n != 2 {
+		t.Errorf("Expected 2 blobs in the registry, got %d", n)
+	}
+
+	dest, cleanup := tempCache(t)
+	defer cleanup()
+	pulled, err := c.Pull(ref, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pulled != saved {
+		t.Errorf("Expected digest %s, got %s", saved, pulled)
+	}
+	ch, err := dest.LoadChart(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Metadata.Name != "mychart" || ch.Metadata.Version != "0.1.0" {
+		t.Errorf("Unexpected chart %v", ch.Metadata)
+	}
+
+	missing := &Reference{Registry: srv.Host(), Repository: "myrepo/mychart", Tag: "0.2.0"}
+	if _, err := c.Pull(missing, dest); err == nil || !strings.Contains(err.Error(), "MANIFEST_UNKNOWN") {
+		t.Errorf("Expected an unknown manifest, got %v", err)
+	}
+}
+
+func TestPushPullWithAuth(t *testing.T) {
+	srv := registrytest.NewServer()
+	defer srv.Stop()
+	srv.RequireAuth("user", "secret")
+
+	cache, cleanup := tempCache(t)
+	defer cleanup()
+	ref := &Reference{Registry: srv.Host(), Repository: "mychart", Tag: "0.1.0"}
+	if _, err := cache.SaveChart(testChart("0.1.0"), ref); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient().Push(ref, cache); err == nil {
+		t.Error("Expected anonymous push to fail")
+	}
+
+	c := NewClient()
+	c.Username, c.Password = "user", "secret"
+	if _, err := c.Push(ref, cache); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Pull(ref, cache); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package registry stores charts in OCI registries.
+
+A chart is stored in a registry as an OCI image manifest with two blobs: a
+config blob that holds the chart's metadata as JSON, and a single layer that
+holds the chart archive, as written by 'chartutil.Save':
+
+	{
+	  "schemaVersion": 2,
+	  "config": {
+	    "mediaType": "application/vnd.cncf.helm.config.v1+json",
+	    "digest": "sha256:8ec7c0f2f6860037c19b54c3cfbab48d9b4b21b485a93d87b64690fdb68c2111",
+	    "size": 117
+	  },
+	  "layers": [
+	    {
+	      "mediaType": "application/vnd.cncf.helm.chart.content.v1.tar+gzip",
+	      "digest": "sha256:1b251d38cfe948dfc0a5745b7af5ca574ecb61e52aed10b19039db39af6e1617",
+	      "size": 2487
+	    }
+	  ]
+	}
+
+Charts are saved to, and pulled into, a local content-addressed Cache, and
+pushed from it with a Client.
+*/
+package registry
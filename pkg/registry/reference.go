@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Scheme is the URL scheme of chart references in OCI registries, like
+// "oci://localhost:5000/myrepo/mychart:0.1.0".
+const Scheme = "oci"
+
+var (
+	// repositoryRegexp matches repository names, as defined by the OCI distribution spec.
+	repositoryRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	// tagRegexp matches tags, as defined by the OCI distribution spec.
+	tagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// Reference names a chart in an OCI registry.
+type Reference struct {
+	// Registry is the host of the registry, like "localhost:5000".
+	Registry string
+	// Repository is the name of the repository in the registry, like "myrepo/mychart".
+	Repository string
+	// Tag is the tag of the chart in the repository, like "0.1.0".
+	Tag string
+}
+
+// ParseReference parses a reference of the form REGISTRY/REPOSITORY[:TAG],
+// optionally prefixed with "oci://".
+func ParseReference(s string) (*Reference, error) {
+	ref := strings.TrimPrefix(s, Scheme+"://")
+	i := strings.Index(ref, "/")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid chart reference %q: expected REGISTRY/REPOSITORY[:TAG]", s)
+	}
+	r := &Reference{Registry: ref[:i], Repository: ref[i+1:]}
+	if j := strings.LastIndex(r.Repository, ":"); j >= 0 {
+		r.Repository, r.Tag = r.Repository[:j], r.Repository[j+1:]
+		if r.Tag == "" {
+			return nil, fmt.Errorf("invalid chart reference %q: empty tag", s)
+		}
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chart reference %q: %s", s, err)
+	}
+	return r, nil
+}
+
+// Validate checks that the repository and the tag, if any, are valid.
+func (r *Reference) Validate() error {
+	if !repositoryRegexp.MatchString(r.Repository) {
+		return fmt.Errorf("invalid repository name %q", r.Repository)
+	}
+	if r.Tag != "" && !tagRegexp.MatchString(r.Tag) {
+		return fmt.Errorf("invalid tag %q", r.Tag)
+	}
+	return nil
+}
+
+// String returns the reference in the form REGISTRY/REPOSITORY[:TAG].
+func (r *Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	return s
+}
+
+// TagForVersion returns the tag for a chart version. SemVer build metadata is
+// separated by '_' instead of '+', since '+' is not allowed in tags.
+func TagForVersion(version string) string {
+	return strings.Replace(version, "+", "_", -1)
+}
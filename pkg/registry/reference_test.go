@@ -0,0 +1,68 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		ref    string
+		expect *Reference
+	}{
+		{"localhost:5000/mychart:0.1.0", &Reference{"localhost:5000", "mychart", "0.1.0"}},
+		{"oci://localhost:5000/myrepo/mychart:0.1.0", &Reference{"localhost:5000", "myrepo/mychart", "0.1.0"}},
+		{"example.com/my-repo/my_chart", &Reference{"example.com", "my-repo/my_chart", ""}},
+		{"example.com/mychart:0.1.0_build.1", &Reference{"example.com", "mychart", "0.1.0_build.1"}},
+		{"mychart:0.1.0", nil},
+		{"example.com/", nil},
+		{"example.com/MyChart:0.1.0", nil},
+		{"example.com/mychart:", nil},
+		{"example.com/mychart:0.1.0+build.1", nil},
+		{"example.com/../mychart:0.1.0", nil},
+		{"example.com/mychart:../0.1.0", nil},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseReference(tt.ref)
+		if tt.expect == nil {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %v", tt.ref, r)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", tt.ref, err)
+			continue
+		}
+		if *r != *tt.expect {
+			t.Errorf("%s: expected %v, got %v", tt.ref, tt.expect, r)
+		}
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	r := &Reference{Registry: "localhost:5000", Repository: "myrepo/mychart"}
+	if s := r.String(); s != "localhost:5000/myrepo/mychart" {
+		t.Errorf("Unexpected reference %q", s)
+	}
+	r.Tag = TagForVersion("0.1.0+build.1")
+	if s := r.String(); s != "localhost:5000/myrepo/mychart:0.1.0_build.1" {
+		t.Errorf("Unexpected reference %q", s)
+	}
+}
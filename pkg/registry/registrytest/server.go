@@ -0,0 +1,241 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrytest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// token is the only token issued by the token service of a Server.
+const token = "registrytest-token"
+
+// Server is an in-memory OCI registry for testing.
+type Server struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]manifest
+	uploads   int
+	// username and password are required by the token service, if set.
+	username string
+	password string
+}
+
+type manifest struct {
+	mediaType string
+	data      []byte
+}
+
+// NewServer starts a registry on localhost. The caller is responsible for
+// stopping it.
+func NewServer() *Server {
+	s := &Server{
+		blobs:     map[string][]byte{},
+		manifests: map[string]manifest{},
+	}
+	s.srv = httptest.NewServer(s)
+	return s
+}
+
+// RequireAuth makes the registry require a bearer token for all requests,
+// which its token service issues to clients with the given credentials.
+func (s *Server) RequireAuth(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+}
+
+// Host returns the host of the registry, which is the registry part of the
+// references of the charts it stores.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.srv.URL, "http://")
+}
+
+// URL returns the URL of the registry.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Stop stops the registry.
+func (s *Server) Stop() {
+	s.srv.Close()
+}
+
+// BlobCount returns the number of blobs in the registry.
+func (s *Server) BlobCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.blobs)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		s.serveToken(w, r)
+		return
+	}
+	if s.username != "" && r.Header.Get("Authorization") != "Bearer "+token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registrytest",scope="repository:*:pull,push"`, s.srv.URL))
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/v2/")
+	if p == r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case strings.Contains(p, "/blobs/uploads/"):
+		s.serveUpload(w, r)
+	case strings.Contains(p, "/blobs/"):
+		s.serveBlob(w, r, p[strings.LastIndex(p, "/blobs/")+len("/blobs/"):])
+	case strings.Contains(p, "/manifests/"):
+		i := strings.LastIndex(p, "/manifests/")
+		s.serveManifest(w, r, p[:i], p[i+len("/manifests/"):])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if u, p, ok := r.BasicAuth(); !ok || u != s.username || p != s.password {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid credentials")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
+
+// serveUpload starts an upload with POST, and completes it with a PUT of the
+// whole blob.
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		s.uploads++
+		w.Header().Set("Location", fmt.Sprintf("%s%d", strings.TrimSuffix(r.URL.Path, "/")+"/", s.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case "PUT":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+			return
+		}
+		digest := r.URL.Query().Get("digest")
+		if digest != digestOf(data) {
+			writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "digest does not match content")
+			return
+		}
+		s.blobs[digest] = data
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveBlob(w http.ResponseWriter, r *http.Request, digest string) {
+	data, ok := s.blobs[digest]
+	if !ok {
+		writeError(w, http.StatusNotFound, "BLOB_UNKNOWN", "blob unknown to registry")
+		return
+	}
+	switch r.Method {
+	case "HEAD":
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	case "GET":
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveManifest(w http.ResponseWriter, r *http.Request, repo, ref string) {
+	key := repo + ":" + ref
+	switch r.Method {
+	case "PUT":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+			return
+		}
+		var m struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+			Layers []struct {
+				Digest string `json:"digest"`
+			} `json:"layers"`
+		}
+		if err := json.Unmarshal(data, &m); err != nil {
+			writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+			return
+		}
+		digests := []string{m.Config.Digest}
+		for _, l := range m.Layers {
+			digests = append(digests, l.Digest)
+		}
+		for _, d := range digests {
+			if _, ok := s.blobs[d]; !ok {
+				writeError(w, http.StatusBadRequest, "MANIFEST_BLOB_UNKNOWN", "blob unknown to registry: "+d)
+				return
+			}
+		}
+		mf := manifest{mediaType: r.Header.Get("Content-Type"), data: data}
+		s.manifests[key] = mf
+		s.manifests[repo+":"+digestOf(data)] = mf
+		w.Header().Set("Docker-Content-Digest", digestOf(data))
+		w.WriteHeader(http.StatusCreated)
+	case "GET", "HEAD":
+		mf, ok := s.manifests[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+			return
+		}
+		w.Header().Set("Content-Type", mf.mediaType)
+		w.Header().Set("Docker-Content-Digest", digestOf(mf.data))
+		if r.Method == "GET" {
+			w.Write(mf.data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
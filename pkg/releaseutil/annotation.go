@@ -187,3 +187,25 @@ func matchReleaseAnnotations(a, b map[string]string) bool {
 	}
 	return true
 }
+
+// ReleaseAnnotations returns the release annotations (path, namespace and release)
+// carried by an object. Other annotations are not included.
+func ReleaseAnnotations(obj runtime.Object) map[AnnotationKey]string {
+	annos := make(map[AnnotationKey]string)
+	accessor := meta.NewAccessor()
+	origin, err := accessor.Annotations(obj)
+	if err != nil {
+		return annos
+	}
+	for _, key := range []AnnotationKey{DefaultPathKey, DefaultNamespaceKey, DefaultReleaseKey} {
+		if v, ok := origin[string(key)]; ok {
+			annos[key] = v
+		}
+	}
+	return annos
+}
+
+// Managed checks if an object carries any release annotation.
+func Managed(obj runtime.Object) bool {
+	return len(ReleaseAnnotations(obj)) > 0
+}
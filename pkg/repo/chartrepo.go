@@ -17,12 +17,15 @@ limitations under the License.
 package repo // import "k8s.io/helm/pkg/repo"
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 
@@ -39,6 +42,44 @@ type Entry struct {
 	CertFile string `json:"certFile"`
 	KeyFile  string `json:"keyFile"`
 	CAFile   string `json:"caFile"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+	// ConnectTimeout and ReadTimeout are in seconds.
+	ConnectTimeout int64             `json:"connectTimeout,omitempty"`
+	ReadTimeout    int64             `json:"readTimeout,omitempty"`
+	Proxy          string            `json:"proxy,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	UserAgent      string            `json:"userAgent,omitempty"`
+	Retries        int               `json:"retries,omitempty"`
+}
+
+// GetterOptions returns the options of a getter for the repository.
+func (e *Entry) GetterOptions() getter.Options {
+	o := getter.Options{
+		URL:                   e.URL,
+		CertFile:              e.CertFile,
+		KeyFile:               e.KeyFile,
+		CAFile:                e.CAFile,
+		InsecureSkipTLSVerify: e.InsecureSkipTLSVerify,
+		Username:              e.Username,
+		Password:              e.Password,
+		Token:                 e.Token,
+		ConnectTimeout:        time.Duration(e.ConnectTimeout) * time.Second,
+		ReadTimeout:           time.Duration(e.ReadTimeout) * time.Second,
+		Proxy:                 e.Proxy,
+		UserAgent:             e.UserAgent,
+		Retries:               e.Retries,
+	}
+	if len(e.Headers) > 0 {
+		o.Headers = http.Header{}
+		for k, v := range e.Headers {
+			o.Headers.Set(k, v)
+		}
+	}
+	return o
 }
 
 // ChartRepository represents a chart repository
@@ -60,7 +101,7 @@ func NewChartRepository(cfg *Entry, getters getter.Providers) (*ChartRepository,
 	if err != nil {
 		return nil, fmt.Errorf("Could not find protocol handler for: %s", u.Scheme)
 	}
-	client, _ := getterConstructor(cfg.URL, cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	client, err := getterConstructor(cfg.GetterOptions())
 	if err != nil {
 		return nil, fmt.Errorf("Could not construct protocol handler for: %s", u.Scheme)
 	}
@@ -108,23 +149,14 @@ func (r *ChartRepository) Load() error {
 //
 // cachePath is prepended to any index that does not have an absolute path. This
 // is for pre-2.2.0 repo files.
+//
+// If the getter of the repository supports it, the index is only downloaded
+// again if it changed since it was cached. A pre-parsed form of the index is
+// cached along with it, for LoadCachedIndexFile.
 func (r *ChartRepository) DownloadIndexFile(cachePath string) error {
 	var indexURL string
 
 	indexURL = strings.TrimSuffix(r.Config.URL, "/") + "/index.yaml"
-	resp, err := r.Client.Get(indexURL)
-	if err != nil {
-		return err
-	}
-
-	index, err := ioutil.ReadAll(resp)
-	if err != nil {
-		return err
-	}
-
-	if _, err := loadIndex(index); err != nil {
-		return err
-	}
 
 	// In Helm 2.2.0 the config.cache was accidentally switched to an absolute
 	// path, which broke backward compatibility. This fixes it by prepending a
@@ -137,7 +169,45 @@ func (r *ChartRepository) DownloadIndexFile(cachePath string) error {
 		cp = filepath.Join(cachePath, cp)
 	}
 
-	return ioutil.WriteFile(cp, index, 0644)
+	var resp *bytes.Buffer
+	var validators getter.Validators
+	var err error
+	if cg, ok := r.Client.(getter.ConditionalGetter); ok {
+		resp, validators, err = cg.GetIfModified(indexURL, readValidators(cp, indexURL))
+		if err == getter.ErrNotModified {
+			if _, ok := loadBinaryIndex(cp); ok {
+				return nil
+			}
+			i, err := LoadIndexFile(cp)
+			if err != nil {
+				return err
+			}
+			return writeBinaryIndex(cp, i)
+		}
+	} else {
+		resp, err = r.Client.Get(indexURL)
+	}
+	if err != nil {
+		return err
+	}
+
+	index, err := gunzipIndex(resp.Bytes())
+	if err != nil {
+		return err
+	}
+
+	i, err := loadIndex(index)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(cp, index, 0644); err != nil {
+		return err
+	}
+	if err := writeBinaryIndex(cp, i); err != nil {
+		return err
+	}
+	return writeValidators(cp, indexURL, validators)
 }
 
 // Index generates an index for the chart repository and writes an index.yaml file.
@@ -181,30 +251,22 @@ func (r *ChartRepository) generateIndex() error {
 // FindChartInRepoURL finds chart in chart repository pointed by repoURL
 // without adding repo to repostiories
 func FindChartInRepoURL(repoURL, chartName, chartVersion, certFile, keyFile, caFile string, getters getter.Providers) (string, error) {
+	return FindChartInAuthRepoURL(repoURL, "", "", chartName, chartVersion, certFile, keyFile, caFile, getters)
+}
 
-	// Download and write the index file to a temporary location
-	tempIndexFile, err := ioutil.TempFile("", "tmp-repo-file")
-	if err != nil {
-		return "", fmt.Errorf("cannot write index file for repository requested")
-	}
-	defer os.Remove(tempIndexFile.Name())
-
+// FindChartInAuthRepoURL finds chart in chart repository pointed by repoURL
+// using the given basic auth credentials, without adding repo to repositories
+func FindChartInAuthRepoURL(repoURL, username, password, chartName, chartVersion, certFile, keyFile, caFile string, getters getter.Providers) (string, error) {
 	c := Entry{
 		URL:      repoURL,
 		CertFile: certFile,
 		KeyFile:  keyFile,
 		CAFile:   caFile,
+		Username: username,
+		Password: password,
 	}
-	r, err := NewChartRepository(&c, getters)
-	if err != nil {
-		return "", err
-	}
-	if err := r.DownloadIndexFile(tempIndexFile.Name()); err != nil {
-		return "", fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", repoURL, err)
-	}
-
 	// Read the index file for the repository to get chart information and return chart URL
-	repoIndex, err := LoadIndexFile(tempIndexFile.Name())
+	repoIndex, err := FetchIndexFile(&c, getters)
 	if err != nil {
 		return "", err
 	}
@@ -224,3 +286,25 @@ func FindChartInRepoURL(repoURL, chartName, chartVersion, certFile, keyFile, caF
 
 	return cv.URLs[0], nil
 }
+
+// FetchIndexFile downloads the index of the chart repository of cfg, without
+// adding the repository to the repositories file.
+func FetchIndexFile(cfg *Entry, getters getter.Providers) (*IndexFile, error) {
+	// Download and write the index file to a temporary location
+	dir, err := ioutil.TempDir("", "helm-repo-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot write index file for repository requested")
+	}
+	defer os.RemoveAll(dir)
+
+	c := *cfg
+	c.Cache = "index.yaml"
+	r, err := NewChartRepository(&c, getters)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DownloadIndexFile(dir); err != nil {
+		return nil, fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", cfg.URL, err)
+	}
+	return LoadCachedIndexFile(filepath.Join(dir, c.Cache))
+}
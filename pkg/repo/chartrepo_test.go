@@ -268,3 +268,32 @@ func TestErrorFindChartInRepoURL(t *testing.T) {
 		t.Errorf("Expected error for chart not found, but got a different error (%v)", err)
 	}
 }
+
+func TestEntryGetterOptions(t *testing.T) {
+	e := &Entry{
+		URL:                   testURL,
+		CAFile:                "ca.crt",
+		Token:                 "token",
+		InsecureSkipTLSVerify: true,
+		ConnectTimeout:        5,
+		ReadTimeout:           60,
+		Proxy:                 "http://proxy:3128",
+		Headers:               map[string]string{"x-api-key": "secret"},
+		UserAgent:             "custom",
+		Retries:               -1,
+	}
+	o := e.GetterOptions()
+
+	if o.URL != testURL || o.CAFile != "ca.crt" || o.Token != "token" || !o.InsecureSkipTLSVerify {
+		t.Errorf("Unexpected TLS or credential options: %+v", o)
+	}
+	if o.ConnectTimeout != 5*time.Second || o.ReadTimeout != time.Minute {
+		t.Errorf("Unexpected timeouts: %s, %s", o.ConnectTimeout, o.ReadTimeout)
+	}
+	if o.Proxy != "http://proxy:3128" || o.UserAgent != "custom" || o.Retries != -1 {
+		t.Errorf("Unexpected transport options: %+v", o)
+	}
+	if o.Headers.Get("X-Api-Key") != "secret" {
+		t.Errorf("Unexpected headers: %v", o.Headers)
+	}
+}
@@ -182,13 +182,32 @@ func (i IndexFile) Get(name, version string) (*ChartVersion, error) {
 
 // WriteFile writes an index file to the given destination path.
 //
-// The mode on the file is set to 'mode'.
+// The mode on the file is set to 'mode'. The file is replaced atomically, so
+// that readers never see a partially written index.
 func (i IndexFile) WriteFile(dest string, mode os.FileMode) error {
 	b, err := yaml.Marshal(i)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(dest, b, mode)
+	return writeFileAtomic(dest, b, mode)
+}
+
+// remove removes the entry for a chart with the given name and exact version,
+// and returns it. It returns nil if there is no such entry.
+func (i IndexFile) remove(name, version string) *ChartVersion {
+	vs := i.Entries[name]
+	for k, cv := range vs {
+		if cv.Version != version {
+			continue
+		}
+		if len(vs) == 1 {
+			delete(i.Entries, name)
+		} else {
+			i.Entries[name] = append(vs[:k:k], vs[k+1:]...)
+		}
+		return cv
+	}
+	return nil
 }
 
 // Merge merges the given index file into this index.
@@ -0,0 +1,189 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/facebookgo/atomicfile"
+
+	"k8s.io/helm/pkg/getter"
+)
+
+// The files that are cached next to the index.yaml of a repository.
+const (
+	// validatorsSuffix is the suffix of the file with the HTTP validators of
+	// the cached index, which are sent to only download it again if it changed.
+	validatorsSuffix = ".validators"
+	// binarySuffix is the suffix of the file with the pre-parsed index.
+	binarySuffix = ".bin"
+)
+
+// binaryIndexFormat is the version of the format of the pre-parsed index. It
+// must be changed whenever IndexFile changes in a way that gob cannot decode.
+const binaryIndexFormat = 1
+
+// binaryIndex is the pre-parsed form of a cached index.yaml.
+type binaryIndex struct {
+	Format int
+	// Size and ModTime are those of the index.yaml the index was parsed from,
+	// so that it is not used once the index.yaml has changed.
+	Size    int64
+	ModTime int64
+	Index   *IndexFile
+}
+
+// cacheValidators are the validators of a cached index, and the URL it was
+// downloaded from.
+type cacheValidators struct {
+	URL string `json:"url"`
+	getter.Validators
+}
+
+// LoadCachedIndexFile loads the index.yaml of a repository that was cached by
+// DownloadIndexFile.
+//
+// It loads the pre-parsed form of the index if it is up to date, which is much
+// faster than parsing the YAML of a large index.
+func LoadCachedIndexFile(path string) (*IndexFile, error) {
+	if i, ok := loadBinaryIndex(path); ok {
+		return i, nil
+	}
+	return LoadIndexFile(path)
+}
+
+// RemoveCachedIndexFile removes a cached index.yaml, along with the files that
+// are cached next to it.
+func RemoveCachedIndexFile(path string) error {
+	for _, p := range []string{path, path + validatorsSuffix, path + binarySuffix} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadBinaryIndex loads the pre-parsed form of the index.yaml at path. It
+// returns false if there is none, or if the index.yaml has changed since.
+func loadBinaryIndex(path string) (*IndexFile, bool) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	f, err := os.Open(path + binarySuffix)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var b binaryIndex
+	if err := gob.NewDecoder(f).Decode(&b); err != nil {
+		return nil, false
+	}
+	if b.Format != binaryIndexFormat || b.Size != fi.Size() || b.ModTime != fi.ModTime().UnixNano() || b.Index == nil {
+		return nil, false
+	}
+	if b.Index.Entries == nil {
+		b.Index.Entries = map[string]ChartVersions{}
+	}
+	return b.Index, true
+}
+
+// writeBinaryIndex writes the pre-parsed form i of the index.yaml at path.
+func writeBinaryIndex(path string, i *IndexFile) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	b := binaryIndex{
+		Format:  binaryIndexFormat,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime().UnixNano(),
+		Index:   i,
+	}
+	if err := gob.NewEncoder(&buf).Encode(&b); err != nil {
+		return err
+	}
+	return writeFileAtomic(path+binarySuffix, buf.Bytes(), 0644)
+}
+
+// readValidators returns the validators of the index.yaml at path, if it was
+// downloaded from url.
+func readValidators(path, url string) getter.Validators {
+	if _, err := os.Stat(path); err != nil {
+		return getter.Validators{}
+	}
+	b, err := ioutil.ReadFile(path + validatorsSuffix)
+	if err != nil {
+		return getter.Validators{}
+	}
+	var v cacheValidators
+	if err := json.Unmarshal(b, &v); err != nil || v.URL != url {
+		return getter.Validators{}
+	}
+	return v.Validators
+}
+
+// writeValidators writes the validators v of the index.yaml at path, which was
+// downloaded from url.
+func writeValidators(path, url string, v getter.Validators) error {
+	if v == (getter.Validators{}) {
+		if err := os.Remove(path + validatorsSuffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.Marshal(cacheValidators{URL: url, Validators: v})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path+validatorsSuffix, b, 0644)
+}
+
+// gunzipIndex decompresses an index that was served as a gzip file, rather
+// than with a gzip Content-Encoding, which the HTTP client already decodes.
+func gunzipIndex(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// writeFileAtomic writes data to the file name, so that readers never see a
+// partially written file.
+func writeFileAtomic(name string, data []byte, mode os.FileMode) error {
+	f, err := atomicfile.New(name, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Abort()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+)
+
+func TestDownloadIndexFileIfModified(t *testing.T) {
+	index, err := ioutil.ReadFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var full, notModified int
+	srv, err := startLocalServerForTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(index)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	dirName, err := ioutil.TempDir("", "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+
+	indexFilePath := filepath.Join(dirName, testRepo+"-index.yaml")
+	r, err := NewChartRepository(&Entry{
+		Name:  testRepo,
+		URL:   srv.URL,
+		Cache: indexFilePath,
+	}, getter.All(environment.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := r.DownloadIndexFile(""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("Expected 1 full and 1 conditional download, got %d and %d", full, notModified)
+	}
+	for _, p := range []string{indexFilePath, indexFilePath + validatorsSuffix, indexFilePath + binarySuffix} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("Expected %s to be cached: %s", p, err)
+		}
+	}
+
+	// Without the cached index.yaml, the index is downloaded again.
+	if err := RemoveCachedIndexFile(indexFilePath); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DownloadIndexFile(""); err != nil {
+		t.Fatal(err)
+	}
+	if full != 2 {
+		t.Errorf("Expected 2 full downloads, got %d", full)
+	}
+}
+
+func TestDownloadIndexFileGzip(t *testing.T) {
+	index, err := ioutil.ReadFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(index)
+	gz.Close()
+	srv, err := startLocalServerForTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-gzip")
+		w.Write(buf.Bytes())
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	dirName, err := ioutil.TempDir("", "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+
+	indexFilePath := filepath.Join(dirName, testRepo+"-index.yaml")
+	r, err := NewChartRepository(&Entry{
+		Name:  testRepo,
+		URL:   srv.URL,
+		Cache: indexFilePath,
+	}, getter.All(environment.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DownloadIndexFile(""); err != nil {
+		t.Fatal(err)
+	}
+
+	i, err := LoadIndexFile(indexFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyLocalIndex(t, i)
+}
+
+func TestLoadCachedIndexFile(t *testing.T) {
+	dirName, err := ioutil.TempDir("", "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+
+	index, err := ioutil.ReadFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexFilePath := filepath.Join(dirName, testRepo+"-index.yaml")
+	if err := ioutil.WriteFile(indexFilePath, index, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a pre-parsed index, the YAML is loaded.
+	i, err := LoadCachedIndexFile(indexFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyLocalIndex(t, i)
+
+	if err := writeBinaryIndex(indexFilePath, i); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadBinaryIndex(indexFilePath); !ok {
+		t.Fatal("Expected the pre-parsed index to be loaded")
+	}
+	i, err = LoadCachedIndexFile(indexFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyLocalIndex(t, i)
+
+	// Once the YAML changes, the pre-parsed index is out of date.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(indexFilePath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadBinaryIndex(indexFilePath); ok {
+		t.Error("Expected the pre-parsed index to be out of date")
+	}
+}
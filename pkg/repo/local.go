@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/ghodss/yaml"
 
@@ -52,13 +53,32 @@ const indexHTMLTemplate = `
 `
 
 // RepositoryServer is an HTTP handler for serving a chart repository.
+//
+// It also serves an API at APIPath, and at /charts followed by APIPath, to
+// list, upload and delete charts.
 type RepositoryServer struct {
 	RepoPath string
+	// URL is the base URL of the charts that are uploaded through the API.
+	URL string
+	// Username and Password, or Token, are the credentials that the API
+	// requires. Without them, charts cannot be uploaded or deleted.
+	Username string
+	Password string
+	Token    string
+
+	// mu serializes changes to the index.
+	mu sync.Mutex
 }
 
 // ServeHTTP implements the http.Handler interface.
 func (s *RepositoryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	uri := r.URL.Path
+	// The API is also served below /charts, where the repository is, since
+	// clients find it relative to the repository URL.
+	if p := strings.TrimPrefix(uri, "/charts"); p == APIPath || strings.HasPrefix(p, APIPath+"/") {
+		s.serveAPI(w, r, p)
+		return
+	}
 	switch uri {
 	case "/", "/charts/", "/charts/index.html", "/charts/index":
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -0,0 +1,313 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
+)
+
+// APIPath is the path of the API of a RepositoryServer.
+//
+// The API has these endpoints:
+//
+//	GET    /api/charts                  lists all charts as JSON
+//	GET    /api/charts/NAME             lists the versions of a chart as JSON
+//	POST   /api/charts                  uploads a chart
+//	DELETE /api/charts/NAME/VERSION     deletes a version of a chart
+//
+// A chart is uploaded either as the body of the request, or as the 'chart'
+// field of a multipart form, with its provenance file in the 'prov' field.
+const APIPath = "/api/charts"
+
+// MaxUploadSize is the maximum size of a request that uploads a chart.
+const MaxUploadSize = 20 << 20
+
+// apiError is the body of a response of the API that failed.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// hasCredentials returns true if the server requires credentials for the API.
+func (s *RepositoryServer) hasCredentials() bool {
+	return s.Username != "" || s.Password != "" || s.Token != ""
+}
+
+// authorized returns true if r carries the credentials of the server.
+func (s *RepositoryServer) authorized(r *http.Request) bool {
+	if s.Token != "" {
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") && secureCompare(strings.TrimPrefix(auth, "Bearer "), s.Token) {
+			return true
+		}
+	}
+	if s.Username != "" || s.Password != "" {
+		username, password, ok := r.BasicAuth()
+		// Both are compared, so that the time does not tell which was wrong.
+		u, p := secureCompare(username, s.Username), secureCompare(password, s.Password)
+		if ok && u && p {
+			return true
+		}
+	}
+	return false
+}
+
+func secureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// serveAPI serves the requests for the API. path is the path of the request
+// from APIPath on.
+//
+// Charts can only be uploaded and deleted if the server has credentials, and
+// every request to the API must carry them.
+func (s *RepositoryServer) serveAPI(w http.ResponseWriter, r *http.Request, path string) {
+	if s.hasCredentials() && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="helm"`)
+		writeAPIError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, APIPath), "/"), "/")
+	if parts[0] == "" {
+		parts = nil
+	}
+	switch {
+	case r.Method == "GET" && len(parts) <= 1:
+		s.listCharts(w, parts)
+	case r.Method == "POST" && len(parts) == 0:
+		if !s.hasCredentials() {
+			writeAPIError(w, http.StatusForbidden, "uploading charts requires the server to have credentials")
+			return
+		}
+		s.uploadChart(w, r)
+	case r.Method == "DELETE" && len(parts) == 2:
+		if !s.hasCredentials() {
+			writeAPIError(w, http.StatusForbidden, "deleting charts requires the server to have credentials")
+			return
+		}
+		s.deleteChart(w, parts[0], parts[1])
+	case len(parts) <= 2:
+		writeAPIError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+	default:
+		writeAPIError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// listCharts writes the index entries of all charts, or of the chart that is
+// named by parts.
+func (s *RepositoryServer) listCharts(w http.ResponseWriter, parts []string) {
+	i, err := s.loadIndex()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(parts) == 0 {
+		writeAPIResponse(w, http.StatusOK, i.Entries)
+		return
+	}
+	versions, ok := i.Entries[parts[0]]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("chart %q not found", parts[0]))
+		return
+	}
+	writeAPIResponse(w, http.StatusOK, versions)
+}
+
+// uploadChart saves the uploaded chart and its provenance file, and adds it to
+// the index.
+func (s *RepositoryServer) uploadChart(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+	data, prov, err := readUpload(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ch, err := chartutil.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid chart: %s", err))
+		return
+	}
+	if err := validateUpload(ch.Metadata, prov); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, version := ch.Metadata.Name, ch.Metadata.Version
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.loadIndex()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if i.Has(name, version) {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("chart %s-%s already exists", name, version))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.tgz", name, version)
+	if err := writeFileAtomic(filepath.Join(s.RepoPath, filename), data, 0644); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if prov != nil {
+		if err := writeFileAtomic(filepath.Join(s.RepoPath, filename+".prov"), prov, 0644); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	digest, err := provenance.Digest(bytes.NewReader(data))
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	i.Add(ch.Metadata, filename, s.URL, digest)
+	i.SortEntries()
+	if err := i.WriteFile(s.indexPath(), 0644); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cv, _ := i.Get(name, version)
+	writeAPIResponse(w, http.StatusCreated, cv)
+}
+
+// readUpload returns the chart and the provenance file of an upload.
+func readUpload(r *http.Request) (data, prov []byte, err error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		data, err = ioutil.ReadAll(r.Body)
+		return data, nil, err
+	}
+
+	if err := r.ParseMultipartForm(MaxUploadSize); err != nil {
+		return nil, nil, err
+	}
+	read := func(field string) ([]byte, error) {
+		f, _, err := r.FormFile(field)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ioutil.ReadAll(f)
+	}
+	if data, err = read("chart"); err != nil {
+		return nil, nil, fmt.Errorf("no chart uploaded: %s", err)
+	}
+	prov, err = read("prov")
+	if err == http.ErrMissingFile {
+		return data, nil, nil
+	}
+	return data, prov, err
+}
+
+// validateUpload checks that an uploaded chart can be stored and indexed.
+func validateUpload(md *chart.Metadata, prov []byte) error {
+	if md == nil || md.Name == "" {
+		return fmt.Errorf("invalid chart: no name")
+	}
+	if strings.ContainsAny(md.Name, `/\`) || md.Name == "." || md.Name == ".." {
+		return fmt.Errorf("invalid chart name %q", md.Name)
+	}
+	if _, err := semver.NewVersion(md.Version); err != nil {
+		return fmt.Errorf("invalid version %q of chart %s: %s", md.Version, md.Name, err)
+	}
+	if prov != nil && !bytes.HasPrefix(bytes.TrimSpace(prov), []byte("-----BEGIN PGP SIGNED MESSAGE-----")) {
+		return fmt.Errorf("invalid provenance file of chart %s-%s: not a signed message", md.Name, md.Version)
+	}
+	return nil
+}
+
+// deleteChart removes a version of a chart from the index, and deletes its
+// files.
+func (s *RepositoryServer) deleteChart(w http.ResponseWriter, name, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.loadIndex()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	cv := i.remove(name, version)
+	if cv == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("chart %s-%s not found", name, version))
+		return
+	}
+	if err := i.WriteFile(s.indexPath(), 0644); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The files are removed after the index, so that the index never refers
+	// to a missing file. Only files in the repository are removed.
+	for _, u := range cv.URLs {
+		base := path.Base(u)
+		if base == "." || base == ".." || base == "/" {
+			continue
+		}
+		filename := filepath.Join(s.RepoPath, base)
+		for _, f := range []string{filename, filename + ".prov"} {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				writeAPIError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+	}
+	writeAPIResponse(w, http.StatusOK, cv)
+}
+
+func (s *RepositoryServer) indexPath() string {
+	return filepath.Join(s.RepoPath, indexPath)
+}
+
+// loadIndex loads the index of the repository, or returns an empty index if it
+// has none yet.
+func (s *RepositoryServer) loadIndex() (*IndexFile, error) {
+	i, err := LoadIndexFile(s.indexPath())
+	if os.IsNotExist(err) {
+		return NewIndexFile(), nil
+	}
+	return i, err
+}
+
+func writeAPIResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, code int, msg string) {
+	writeAPIResponse(w, code, apiError{Error: msg})
+}
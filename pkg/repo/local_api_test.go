@@ -0,0 +1,210 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+)
+
+const testProv = "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA512\n\nname: sprocket\n"
+
+func TestRepositoryServerAPI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-serve-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &RepositoryServer{RepoPath: dir, URL: "http://example.com/charts", Username: "user", Password: "pass"}
+	srv, err := startLocalServerForTests(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	sprocket, err := ioutil.ReadFile("testdata/repository/sprocket-1.2.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	frobnitz, err := ioutil.ReadFile("testdata/repository/frobnitz-1.2.3.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A chart with its provenance file in a multipart form.
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	fw, _ := mw.CreateFormFile("chart", "frobnitz-1.2.3.tgz")
+	fw.Write(frobnitz)
+	fw, _ = mw.CreateFormFile("prov", "frobnitz-1.2.3.tgz.prov")
+	fw.Write([]byte(testProv))
+	mw.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        []byte
+		contentType string
+		noAuth      bool
+		expect      int
+	}{
+		{"unauthorized", "POST", "/api/charts", sprocket, "", true, http.StatusUnauthorized},
+		{"upload", "POST", "/api/charts", sprocket, "", false, http.StatusCreated},
+		{"upload again", "POST", "/api/charts", sprocket, "", false, http.StatusConflict},
+		{"upload invalid", "POST", "/api/charts", []byte("not a chart"), "", false, http.StatusBadRequest},
+		{"upload with provenance", "POST", "/api/charts", form.Bytes(), mw.FormDataContentType(), false, http.StatusCreated},
+		{"list", "GET", "/api/charts", nil, "", false, http.StatusOK},
+		{"list chart", "GET", "/api/charts/sprocket", nil, "", false, http.StatusOK},
+		{"list chart below /charts", "GET", "/charts/api/charts/sprocket", nil, "", false, http.StatusOK},
+		{"list missing chart", "GET", "/api/charts/starfish", nil, "", false, http.StatusNotFound},
+		{"delete", "DELETE", "/api/charts/frobnitz/1.2.3", nil, "", false, http.StatusOK},
+		{"delete again", "DELETE", "/api/charts/frobnitz/1.2.3", nil, "", false, http.StatusNotFound},
+		{"method", "PUT", "/api/charts", nil, "", false, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, srv.URL+tt.path, bytes.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if !tt.noAuth {
+			req.SetBasicAuth("user", "pass")
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != tt.expect {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.expect, res.StatusCode, body)
+		}
+		if tt.name == "list" {
+			var entries map[string]ChartVersions
+			if err := json.Unmarshal(body, &entries); err != nil {
+				t.Errorf("%s: %s", tt.name, err)
+			}
+			if len(entries["sprocket"]) != 1 || len(entries["frobnitz"]) != 1 {
+				t.Errorf("%s: expected sprocket and frobnitz, got %s", tt.name, body)
+			}
+		}
+	}
+
+	i, err := LoadIndexFile(filepath.Join(dir, "index.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv, err := i.Get("sprocket", "1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.URLs[0] != "http://example.com/charts/sprocket-1.2.0.tgz" || cv.Digest == "" {
+		t.Errorf("unexpected index entry %+v", cv)
+	}
+	if i.Has("frobnitz", "1.2.3") {
+		t.Error("expected frobnitz to be deleted from the index")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sprocket-1.2.0.tgz")); err != nil {
+		t.Error(err)
+	}
+	for _, f := range []string{"frobnitz-1.2.3.tgz", "frobnitz-1.2.3.tgz.prov"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be deleted", f)
+		}
+	}
+}
+
+func TestRepositoryServerAPIWithoutCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-serve-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv, err := startLocalServerForTests(&RepositoryServer{RepoPath: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	sprocket, err := ioutil.ReadFile("testdata/repository/sprocket-1.2.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(srv.URL+"/api/charts", "application/gzip", bytes.NewReader(sprocket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("expected uploads to be forbidden, got status %d", res.StatusCode)
+	}
+
+	res, err = http.Get(srv.URL + "/api/charts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected charts to be listed, got status %d", res.StatusCode)
+	}
+}
+
+func TestRepositoryServerPush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-serve-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv, err := startLocalServerForTests(&RepositoryServer{RepoPath: dir, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	// Like the default 'local' repository, the repository is served below
+	// /charts, and the API is found relative to it.
+	repoURL := srv.URL + "/charts"
+	newGetter, err := getter.All(environment.EnvSettings{}).ByScheme("http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := newGetter(getter.Options{URL: repoURL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.(getter.Pusher).Push(repoURL, "testdata/repository/sprocket-1.2.0.tgz", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sprocket-1.2.0.tgz")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/urlutil"
+)
+
+// ConflictError reports requirements on a chart that no version of the chart
+// satisfies together.
+type ConflictError struct {
+	// Name is the name of the chart.
+	Name string
+	// Chains describe the conflicting requirements, each with the chain of
+	// charts from the base chart down to the chart that has the requirement,
+	// like "mychart -> wordpress@1.2.0 -> mariadb ^2.0.0".
+	Chains []string
+
+	// culprits are the names of the charts whose versions led to the conflict.
+	culprits map[string]bool
+}
+
+func newConflictError(name string, edges []*edge) *ConflictError {
+	e := &ConflictError{Name: name, culprits: map[string]bool{name: true}}
+	// Name the repositories if the requirements do not agree on one.
+	repos := false
+	for _, c := range edges {
+		repos = repos || c.source != edges[0].source
+	}
+	for _, c := range edges {
+		chain := c.String()
+		if repos {
+			chain += " from " + c.dep.Repository
+		}
+		e.Chains = append(e.Chains, chain)
+		for _, l := range c.path[1:] {
+			e.culprits[l.name] = true
+		}
+	}
+	return e
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("no version of chart %q satisfies all requirements:\n\t%s", e.Name, strings.Join(e.Chains, "\n\t"))
+}
+
+// link is a chart in a chain of requirements.
+type link struct {
+	name    string
+	version string
+}
+
+func (l link) String() string {
+	if l.version == "" {
+		return l.name
+	}
+	return l.name + "@" + l.version
+}
+
+// edge is a requirement of a chart in the dependency graph.
+type edge struct {
+	dep *chartutil.Dependency
+	// path is the chain of charts from the base chart to the chart that has
+	// the requirement.
+	path []link
+	// source identifies where the dependency comes from. Requirements on the
+	// same chart must agree on it.
+	source string
+	// dir is the directory of a local dependency.
+	dir string
+	// repoName and repoURL are the repository of any other dependency.
+	repoName string
+	repoURL  string
+}
+
+// key identifies the chart that a version is selected for. A chart is shared
+// by name across the graph, except that each alias of a chart is a separate
+// copy of it, which the chart that declares the alias may lock to another
+// version.
+func (e *edge) key() string {
+	if e.dep.Alias == "" {
+		return e.dep.Name
+	}
+	return e.path[len(e.path)-1].String() + " as " + e.dep.Alias
+}
+
+func (e *edge) String() string {
+	chain := make([]string, 0, len(e.path)+1)
+	for _, l := range e.path {
+		chain = append(chain, l.String())
+	}
+	return strings.Join(append(chain, e.dep.Name+" "+e.dep.Version), " -> ")
+}
+
+// node is the version of a chart that is selected in the dependency graph.
+type node struct {
+	name    string
+	version string
+	source  string
+	digest  string
+	// chart is the loaded chart of a local dependency.
+	chart *chart.Chart
+	// reqs are the requirements of this version of the chart.
+	reqs []*edge
+	// requiredBy are the requirements that this version was selected for.
+	requiredBy []*edge
+}
+
+// satisfies returns whether the node satisfies the requirement e.
+func (n *node) satisfies(e *edge) bool {
+	if n.source != e.source {
+		return false
+	}
+	constraint, err := semver.NewConstraint(e.dep.Version)
+	if err != nil {
+		return false
+	}
+	v, err := semver.NewVersion(n.version)
+	return err == nil && constraint.Check(v)
+}
+
+// lockDigest returns the digest of the chart archive of n in the form of the
+// lock file, or "" if the repository index has none.
+func (n *node) lockDigest() string {
+	if n.digest == "" {
+		return ""
+	}
+	return "sha256:" + n.digest
+}
+
+// graph is the state of the resolution of a dependency graph.
+type graph struct {
+	*Resolver
+	repoNames map[string]string
+	repos     *repo.RepoFile
+	indexes   map[string]*repo.IndexFile
+	// fetched caches the requirements of the charts that were fetched, by
+	// source, name and version.
+	fetched map[string]*chartutil.Requirements
+	// selected maps the keys of edges to their selected versions.
+	selected map[string]*node
+}
+
+func newGraph(r *Resolver, repoNames map[string]string) *graph {
+	return &graph{
+		Resolver:  r,
+		repoNames: repoNames,
+		indexes:   map[string]*repo.IndexFile{},
+		fetched:   map[string]*chartutil.Requirements{},
+		selected:  map[string]*node{},
+	}
+}
+
+// solve selects a version of a chart for each of the pending requirements, and
+// for their requirements in turn.
+//
+// It prefers the newest versions, and tries older versions of a chart when
+// a conflict depends on the version that was selected.
+func (g *graph) solve(pending []*edge) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	e, rest := pending[0], pending[1:]
+	name, key := e.dep.Name, e.key()
+
+	for _, l := range e.path[1:] {
+		if l.name == name {
+			return fmt.Errorf("dependency cycle: %s", e)
+		}
+	}
+
+	if n, ok := g.selected[key]; ok {
+		if !n.satisfies(e) {
+			return newConflictError(name, append(n.requiredBy[:len(n.requiredBy):len(n.requiredBy)], e))
+		}
+		n.requiredBy = append(n.requiredBy, e)
+		err := g.solve(rest)
+		if err != nil {
+			n.requiredBy = n.requiredBy[:len(n.requiredBy)-1]
+		}
+		return err
+	}
+
+	// Only consider versions that also satisfy the pending requirements on the
+	// same chart.
+	same := []*edge{e}
+	for _, p := range rest {
+		if p.key() == key && p.source == e.source {
+			same = append(same, p)
+		}
+	}
+	candidates, err := g.candidates(e, same)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return newConflictError(name, same)
+	}
+
+	var conflict *ConflictError
+	for _, n := range candidates {
+		if n.reqs, err = g.requirementsOf(n, e); err != nil {
+			return err
+		}
+		n.requiredBy = []*edge{e}
+		g.selected[key] = n
+
+		next := make([]*edge, 0, len(rest)+len(n.reqs))
+		err = g.solve(append(append(next, rest...), n.reqs...))
+		if err == nil {
+			return nil
+		}
+		delete(g.selected, key)
+
+		c, ok := err.(*ConflictError)
+		if !ok {
+			return err
+		}
+		if conflict == nil {
+			conflict = c
+		} else {
+			for k := range c.culprits {
+				conflict.culprits[k] = true
+			}
+		}
+		if !c.culprits[name] {
+			// Another version of this chart cannot resolve the conflict.
+			break
+		}
+	}
+	return conflict
+}
+
+// candidates returns the versions of the chart of e that satisfy all of the
+// requirements same, newest first.
+func (g *graph) candidates(e *edge, same []*edge) ([]*node, error) {
+	if _, err := semver.NewConstraint(e.dep.Version); err != nil {
+		return nil, fmt.Errorf("dependency %q has an invalid version/constraint format: %s", e.dep.Name, err)
+	}
+
+	var all []*node
+	if e.dir != "" {
+		ch, err := chartutil.LoadDir(e.dir)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, &node{name: e.dep.Name, version: ch.Metadata.Version, source: e.source, chart: ch})
+	} else {
+		index, err := g.index(e.repoName)
+		if err != nil {
+			return nil, err
+		}
+		vs, ok := index.Entries[e.dep.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s chart not found in repo %s", e.dep.Name, e.dep.Repository)
+		}
+		// The versions are already sorted, newest first.
+		for _, ver := range vs {
+			if _, err := semver.NewVersion(ver.Version); err != nil || len(ver.URLs) == 0 {
+				// Not a legit entry.
+				continue
+			}
+			all = append(all, &node{name: e.dep.Name, version: ver.Version, source: e.source, digest: ver.Digest})
+		}
+	}
+
+	candidates := []*node{}
+	for _, n := range all {
+		ok := true
+		for _, s := range same {
+			ok = ok && n.satisfies(s)
+		}
+		if ok {
+			candidates = append(candidates, n)
+		}
+	}
+	return candidates, nil
+}
+
+// requirementsOf returns the requirements of the chart of n, which was
+// selected for e.
+func (g *graph) requirementsOf(n *node, e *edge) ([]*edge, error) {
+	path := append(e.path[:len(e.path):len(e.path)], link{name: n.name, version: n.version})
+
+	if n.chart != nil {
+		reqs, err := chartutil.LoadRequirements(n.chart)
+		if err == chartutil.ErrRequirementsNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return g.requirements(reqs, path, e.dir)
+	}
+
+	if g.Fetch == nil {
+		return nil, nil
+	}
+	key := n.source + "/" + n.name + "@" + n.version
+	reqs, ok := g.fetched[key]
+	if !ok {
+		ch, err := g.Fetch(n.name, n.version, e.repoURL, n.lockDigest())
+		if err != nil {
+			return nil, err
+		}
+		reqs, err = chartutil.LoadRequirements(ch)
+		if err != nil && err != chartutil.ErrRequirementsNotFound {
+			return nil, fmt.Errorf("%s: %s", link{name: n.name, version: n.version}, err)
+		}
+		g.fetched[key] = reqs
+	}
+	if reqs == nil {
+		return nil, nil
+	}
+	// A packaged chart has no directory that file:// repositories are
+	// relative to. Such dependencies are vendored in its archive.
+	return g.requirements(reqs, path, "")
+}
+
+// requirements returns the edges of the requirements reqs of the last chart on
+// path. Local repositories are relative to dir.
+//
+// Requirements without a repository, and local requirements of a packaged
+// chart, are skipped, since they can only be satisfied by the charts that are
+// vendored in the charts/ directory of the chart.
+func (g *graph) requirements(reqs *chartutil.Requirements, path []link, dir string) ([]*edge, error) {
+	edges := []*edge{}
+	for _, dep := range reqs.Dependencies {
+		e := &edge{dep: dep, path: path}
+		switch {
+		case strings.HasPrefix(dep.Repository, "file://"):
+			if dir == "" {
+				continue
+			}
+			p, err := GetLocalPath(dep.Repository, dir)
+			if err != nil {
+				return nil, err
+			}
+			if e.dir, err = filepath.Abs(p); err != nil {
+				return nil, err
+			}
+			e.source = "file://" + e.dir
+		case len(path) == 1:
+			// The repositories of the base chart were looked up by the caller.
+			e.repoName, e.repoURL = g.repoNames[dep.Name], dep.Repository
+			e.source = e.repoName
+		case dep.Repository == "":
+			continue
+		default:
+			re, err := g.repository(dep.Repository)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", e, err)
+			}
+			e.repoName, e.repoURL = re.Name, re.URL
+			e.source = e.repoName
+		}
+		edges = append(edges, e)
+	}
+	return edges, nil
+}
+
+// repository finds the configured repository of a requirement, given by its
+// URL or as "@name" or "alias:name".
+func (g *graph) repository(repository string) (*repo.Entry, error) {
+	if g.repos == nil {
+		rf, err := repo.LoadRepositoriesFile(g.helmhome.RepositoryFile())
+		if err != nil {
+			return nil, err
+		}
+		g.repos = rf
+	}
+	for _, re := range g.repos.Repositories {
+		if strings.TrimPrefix(repository, "@") == re.Name && strings.HasPrefix(repository, "@") ||
+			strings.TrimPrefix(repository, "alias:") == re.Name && strings.HasPrefix(repository, "alias:") ||
+			urlutil.Equal(re.URL, repository) {
+			return re, nil
+		}
+	}
+	return nil, fmt.Errorf("no repository definition for %s. Try 'helm repo add'", repository)
+}
+
+// index loads the cached index of a repository.
+func (g *graph) index(repoName string) (*repo.IndexFile, error) {
+	if i, ok := g.indexes[repoName]; ok {
+		return i, nil
+	}
+	i, err := repo.LoadCachedIndexFile(g.helmhome.CacheIndex(repoName))
+	if err != nil {
+		return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
+	}
+	g.indexes[repoName] = i
+	return i, nil
+}
+
+// lock returns the locked dependency that was selected for e, along with its
+// own locked dependencies.
+func (g *graph) lock(e *edge) *chartutil.Dependency {
+	n := g.selected[e.key()]
+	d := &chartutil.Dependency{
+		Name:       n.name,
+		Repository: e.repoURL,
+		Version:    n.version,
+	}
+	if e.dir != "" {
+		d.Repository = e.dep.Repository
+	}
+	d.Digest = n.lockDigest()
+	for _, c := range n.reqs {
+		d.Dependencies = append(d.Dependencies, g.lock(c))
+	}
+	return d
+}
@@ -28,6 +28,7 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/provenance"
 	"k8s.io/helm/pkg/repo"
 )
@@ -36,6 +37,13 @@ import (
 type Resolver struct {
 	chartpath string
 	helmhome  helmpath.Home
+
+	// Fetch loads the chart of a dependency from a repository, so that the
+	// requirements of the dependency are resolved too. The digest of the chart
+	// archive is given in the form of the lock file, or empty if the index of
+	// the repository has none. If Fetch is nil, only the requirements of the
+	// chart and of its local dependencies are resolved.
+	Fetch func(name, version, repoURL, digest string) (*chart.Chart, error)
 }
 
 // New creates a new resolver for a given chart and a given helm home.
@@ -47,23 +55,20 @@ func New(chartpath string, helmhome helmpath.Home) *Resolver {
 }
 
 // Resolve resolves dependencies and returns a lock file with the resolution.
+//
+// The requirements of the dependencies are resolved transitively, so that each
+// chart in the dependency graph is locked to one version that satisfies every
+// requirement on it. If there is no such version, the error is a
+// *ConflictError that lists the conflicting chains of requirements.
 func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]string, d string) (*chartutil.RequirementsLock, error) {
 
-	// Now we clone the dependencies, locking as we go.
-	locked := make([]*chartutil.Dependency, len(reqs.Dependencies))
+	// First check that every direct dependency can be resolved on its own.
 	missing := []string{}
-	for i, d := range reqs.Dependencies {
+	for _, d := range reqs.Dependencies {
 		if strings.HasPrefix(d.Repository, "file://") {
-
 			if _, err := GetLocalPath(d.Repository, r.chartpath); err != nil {
 				return nil, err
 			}
-
-			locked[i] = &chartutil.Dependency{
-				Name:       d.Name,
-				Repository: d.Repository,
-				Version:    d.Version,
-			}
 			continue
 		}
 		constraint, err := semver.NewConstraint(d.Version)
@@ -71,7 +76,7 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 			return nil, fmt.Errorf("dependency %q has an invalid version/constraint format: %s", d.Name, err)
 		}
 
-		repoIndex, err := repo.LoadIndexFile(r.helmhome.CacheIndex(repoNames[d.Name]))
+		repoIndex, err := repo.LoadCachedIndexFile(r.helmhome.CacheIndex(repoNames[d.Name]))
 		if err != nil {
 			return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
 		}
@@ -81,12 +86,7 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 			return nil, fmt.Errorf("%s chart not found in repo %s", d.Name, d.Repository)
 		}
 
-		locked[i] = &chartutil.Dependency{
-			Name:       d.Name,
-			Repository: d.Repository,
-		}
 		found := false
-		// The version are already sorted and hence the first one to satisfy the constraint is used
 		for _, ver := range vs {
 			v, err := semver.NewVersion(ver.Version)
 			if err != nil || len(ver.URLs) == 0 {
@@ -95,7 +95,6 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 			}
 			if constraint.Check(v) {
 				found = true
-				locked[i].Version = v.Original()
 				break
 			}
 		}
@@ -107,6 +106,21 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 	if len(missing) > 0 {
 		return nil, fmt.Errorf("Can't get a valid version for repositories %s. Try changing the version constraint in requirements.yaml", strings.Join(missing, ", "))
 	}
+
+	// Then resolve the whole graph, locking as we go.
+	g := newGraph(r, repoNames)
+	direct, err := g.requirements(reqs, []link{{name: filepath.Base(r.chartpath)}}, r.chartpath)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.solve(direct); err != nil {
+		return nil, err
+	}
+
+	locked := make([]*chartutil.Dependency, len(direct))
+	for i, e := range direct {
+		locked[i] = g.lock(e)
+	}
 	return &chartutil.RequirementsLock{
 		Generated:    time.Now(),
 		Digest:       d,
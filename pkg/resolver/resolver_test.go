@@ -16,9 +16,16 @@ limitations under the License.
 package resolver
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/any"
+
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 func TestResolve(t *testing.T) {
@@ -145,6 +152,152 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+// fetchTesting fetches the charts of the testing repository, which have these
+// requirements.
+func fetchTesting(name, version, repoURL, digest string) (*chart.Chart, error) {
+	reqs := map[string]string{
+		"app@1.0.0": `dependencies:
+- name: common
+  version: ^1.0.0
+  repository: "@testing"
+- name: db
+  version: ^2.0.0
+  repository: http://example.com/testing/
+- name: vendored
+  version: 1.0.0
+  repository: file://../vendored
+`,
+		"db@2.1.0":    "dependencies:\n- {name: common, version: ~1.1.0, repository: 'alias:testing'}\n",
+		"db@2.0.0":    "dependencies:\n- {name: common, version: ^1.0.0, repository: '@testing'}\n",
+		"web@1.0.0":   "dependencies:\n- {name: common, version: ^2.0.0, repository: '@testing'}\n",
+		"cycle@1.0.0": "dependencies:\n- {name: cycle, version: 1.0.0, repository: '@testing'}\n",
+		"lost@1.0.0":  "dependencies:\n- {name: common, version: 1.0.0, repository: 'http://example.com/lost'}\n",
+	}
+	if repoURL != "http://example.com/testing" {
+		return nil, fmt.Errorf("unexpected repository %s", repoURL)
+	}
+	if d := locked(name, version).Digest; digest != d {
+		return nil, fmt.Errorf("expected digest %s for %s-%s, got %s", d, name, version, digest)
+	}
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version}}
+	if r, ok := reqs[name+"@"+version]; ok {
+		ch.Files = []*any.Any{{TypeUrl: "requirements.yaml", Value: []byte(r)}}
+	}
+	return ch, nil
+}
+
+// locked returns the expected lock of a chart of the testing repository.
+func locked(name, version string, deps ...*chartutil.Dependency) *chartutil.Dependency {
+	return &chartutil.Dependency{
+		Name:         name,
+		Version:      version,
+		Repository:   "http://example.com/testing",
+		Digest:       fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(name+"-"+version))),
+		Dependencies: deps,
+	}
+}
+
+func TestResolveTransitive(t *testing.T) {
+	tests := []struct {
+		name   string
+		deps   []*chartutil.Dependency
+		expect []*chartutil.Dependency
+		// conflict is the expected conflict, or the expected error if it is
+		// not a conflict.
+		conflict []string
+		err      string
+	}{
+		{
+			name: "shared dependency",
+			deps: []*chartutil.Dependency{
+				{Name: "app", Repository: "http://example.com/testing", Version: "^1.0.0"},
+			},
+			expect: []*chartutil.Dependency{
+				locked("app", "1.0.0", locked("common", "1.2.0"), locked("db", "2.0.0", locked("common", "1.2.0"))),
+			},
+		},
+		{
+			name: "older version that satisfies every requirement",
+			deps: []*chartutil.Dependency{
+				{Name: "db", Repository: "http://example.com/testing", Version: "2.1.0"},
+				{Name: "common", Repository: "http://example.com/testing", Version: "^1.0.0"},
+			},
+			expect: []*chartutil.Dependency{
+				locked("db", "2.1.0", locked("common", "1.1.3")),
+				locked("common", "1.1.3"),
+			},
+		},
+		{
+			name: "aliases",
+			deps: []*chartutil.Dependency{
+				{Name: "common", Alias: "legacy", Repository: "http://example.com/testing", Version: "^1.0.0"},
+				{Name: "common", Alias: "current", Repository: "http://example.com/testing", Version: "^2.0.0"},
+			},
+			expect: []*chartutil.Dependency{
+				locked("common", "1.2.0"),
+				locked("common", "2.0.0"),
+			},
+		},
+		{
+			name: "conflict",
+			deps: []*chartutil.Dependency{
+				{Name: "app", Repository: "http://example.com/testing", Version: "^1.0.0"},
+				{Name: "web", Repository: "http://example.com/testing", Version: "^1.0.0"},
+			},
+			conflict: []string{
+				"chartpath -> app@1.0.0 -> common ^1.0.0",
+				"chartpath -> web@1.0.0 -> common ^2.0.0",
+			},
+		},
+		{
+			name: "cycle",
+			deps: []*chartutil.Dependency{
+				{Name: "cycle", Repository: "http://example.com/testing", Version: "1.0.0"},
+			},
+			err: "dependency cycle: chartpath -> cycle@1.0.0 -> cycle 1.0.0",
+		},
+		{
+			name: "unknown repository",
+			deps: []*chartutil.Dependency{
+				{Name: "lost", Repository: "http://example.com/testing", Version: "1.0.0"},
+			},
+			err: "no repository definition for http://example.com/lost",
+		},
+	}
+
+	repoNames := map[string]string{"app": "testing", "common": "testing", "cycle": "testing", "db": "testing", "lost": "testing", "web": "testing"}
+	r := New("testdata/chartpath", "testdata/helmhome")
+	r.Fetch = fetchTesting
+	for _, tt := range tests {
+		l, err := r.Resolve(&chartutil.Requirements{Dependencies: tt.deps}, repoNames, "")
+		switch {
+		case tt.conflict != nil:
+			c, ok := err.(*ConflictError)
+			if !ok {
+				t.Errorf("%s: expected a conflict, got %v", tt.name, err)
+			} else if c.Name != "common" || !reflect.DeepEqual(c.Chains, tt.conflict) {
+				t.Errorf("%s: expected a conflict on common with chains %q, got %q with %q", tt.name, tt.conflict, c.Name, c.Chains)
+			}
+		case tt.err != "":
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+			}
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case !reflect.DeepEqual(l.Dependencies, tt.expect):
+			t.Errorf("%s: expected lock %s, got %s", tt.name, formatLock(tt.expect), formatLock(l.Dependencies))
+		}
+	}
+}
+
+func formatLock(deps []*chartutil.Dependency) string {
+	s := []string{}
+	for _, d := range deps {
+		s = append(s, fmt.Sprintf("%s@%s%s", d.Name, d.Version, formatLock(d.Dependencies)))
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
+
 func TestHashReq(t *testing.T) {
 	expect := "sha256:45b06fcc4496c705bf3d634f8a2ff84e6a6f0bdcaf010614b8886572d1e52b99"
 	req := &chartutil.Requirements{
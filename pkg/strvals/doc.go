@@ -26,6 +26,10 @@ The above is equivalent to the YAML document
 	topname:
 	  subname: value
 
+Values are converted to booleans and integers where possible. The
+ParseIntoString, ParseIntoFile and ParseIntoJSON functions parse values as
+strings, as names of files to read, and as JSON documents instead.
+
 This package provides a parser and utilities for converting the strvals format
 to other formats.
 */
@@ -17,11 +17,14 @@ package strvals
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/ghodss/yaml"
 )
@@ -29,6 +32,10 @@ import (
 // ErrNotList indicates that a non-list was treated as a list.
 var ErrNotList = errors.New("not a list")
 
+// MaxIndex is the largest list index accepted in a key, so that a typo cannot
+// allocate a huge list.
+const MaxIndex = 65536
+
 // ToYAML takes a string of arguments and converts to a YAML document.
 func ToYAML(s string) (string, error) {
 	m, err := Parse(s)
@@ -45,30 +52,72 @@ func ToYAML(s string) (string, error) {
 func Parse(s string) (map[string]interface{}, error) {
 	vals := map[string]interface{}{}
 	scanner := bytes.NewBufferString(s)
-	t := newParser(scanner, vals)
+	t := newParser(scanner, vals, typedVal)
 	err := t.parse()
 	return vals, err
 }
 
+// ParseString parses a set line, keeping every value as a string.
+func ParseString(s string) (map[string]interface{}, error) {
+	vals := map[string]interface{}{}
+	err := ParseIntoString(s, vals)
+	return vals, err
+}
+
 //ParseInto parses a strvals line and merges the result into dest.
 //
 // If the strval string has a key that exists in dest, it overwrites the
 // dest version.
 func ParseInto(s string, dest map[string]interface{}) error {
 	scanner := bytes.NewBufferString(s)
-	t := newParser(scanner, dest)
+	t := newParser(scanner, dest, typedVal)
+	return t.parse()
+}
+
+// ParseIntoString parses a strvals line and merges the result into dest,
+// keeping every value as a string.
+//
+// Unlike ParseInto, values like 1.10 or 0123 are not converted to numbers.
+func ParseIntoString(s string, dest map[string]interface{}) error {
+	scanner := bytes.NewBufferString(s)
+	t := newParser(scanner, dest, stringVal)
+	return t.parse()
+}
+
+// ParseIntoFile parses a strvals line and merges the result into dest. Every
+// value is the name of a file, and is replaced by the contents of the file.
+func ParseIntoFile(s string, dest map[string]interface{}) error {
+	scanner := bytes.NewBufferString(s)
+	t := newParser(scanner, dest, fileVal)
 	return t.parse()
 }
 
+// ParseIntoJSON parses a strvals line and merges the result into dest. Every
+// value is a JSON document, which may contain commas:
+//
+//	name1={"a": [1, 2]},name2="text"
+func ParseIntoJSON(s string, dest map[string]interface{}) error {
+	scanner := bytes.NewBufferString(s)
+	t := newParser(scanner, dest, nil)
+	t.json = true
+	return t.parse()
+}
+
+// runesToVal converts the runes of a value to the value to set.
+type runesToVal func([]rune) (interface{}, error)
+
 // parser is a simple parser that takes a strvals line and parses it into a
 // map representation.
 type parser struct {
-	sc   *bytes.Buffer
-	data map[string]interface{}
+	sc     *bytes.Buffer
+	data   map[string]interface{}
+	reader runesToVal
+	// json is true if values are JSON documents. The reader is not used then.
+	json bool
 }
 
-func newParser(sc *bytes.Buffer, data map[string]interface{}) *parser {
-	return &parser{sc: sc, data: data}
+func newParser(sc *bytes.Buffer, data map[string]interface{}, reader runesToVal) *parser {
+	return &parser{sc: sc, data: data, reader: reader}
 }
 
 func (t *parser) parse() error {
@@ -110,10 +159,11 @@ func (t *parser) key(data map[string]interface{}) error {
 				return fmt.Errorf("error parsing index: %s", err)
 			}
 			kk := string(k)
-			// Find or create target list
-			list := []interface{}{}
-			if _, ok := data[kk]; ok {
-				list = data[kk].([]interface{})
+			// Find or create target list. A value of another type is
+			// replaced, like it would be by a plain value.
+			list, ok := data[kk].([]interface{})
+			if !ok {
+				list = []interface{}{}
 			}
 
 			// Now we need to get the value after the ].
@@ -122,32 +172,23 @@ func (t *parser) key(data map[string]interface{}) error {
 			return err
 		case last == '=':
 			//End of key. Consume =, Get value.
-			// FIXME: Get value list first
-			vl, e := t.valList()
-			switch e {
-			case nil:
-				set(data, string(k), vl)
-				return nil
-			case io.EOF:
-				set(data, string(k), "")
-				return e
-			case ErrNotList:
-				v, e := t.val()
-				set(data, string(k), typedVal(v))
-				return e
-			default:
+			v, e := t.value()
+			if e != nil && e != io.EOF {
 				return e
 			}
+			set(data, string(k), v)
+			return e
 
 		case last == ',':
 			// No value given. Set the value to empty string. Return error.
 			set(data, string(k), "")
 			return fmt.Errorf("key %q has no value (cannot end with ,)", string(k))
 		case last == '.':
-			// First, create or find the target map.
-			inner := map[string]interface{}{}
-			if _, ok := data[string(k)]; ok {
-				inner = data[string(k)].(map[string]interface{})
+			// First, create or find the target map. A value of another type
+			// is replaced, like it would be by a plain value.
+			inner, ok := data[string(k)].(map[string]interface{})
+			if !ok {
+				inner = map[string]interface{}{}
 			}
 
 			// Recurse
@@ -187,9 +228,20 @@ func (t *parser) keyIndex() (int, error) {
 		return 0, err
 	}
 	// v should be the index
-	return strconv.Atoi(string(v))
-
+	i, err := strconv.Atoi(string(v))
+	switch {
+	case err != nil:
+		return 0, err
+	case i < 0:
+		return 0, fmt.Errorf("negative index %d", i)
+	case i > MaxIndex:
+		return 0, fmt.Errorf("index %d is larger than the maximum of %d", i, MaxIndex)
+	}
+	return i, nil
 }
+
+// listItem sets the element i of list from what follows the ']' of its
+// index. The list grows if i is past its end.
 func (t *parser) listItem(list []interface{}, i int) ([]interface{}, error) {
 	stop := runeSet([]rune{'[', '.', '='})
 	switch k, last, err := runesUntil(t.sc, stop); {
@@ -198,32 +250,36 @@ func (t *parser) listItem(list []interface{}, i int) ([]interface{}, error) {
 	case err != nil:
 		return list, err
 	case last == '=':
-		vl, e := t.valList()
-		switch e {
-		case nil:
-			return setIndex(list, i, vl), nil
-		case io.EOF:
-			return setIndex(list, i, ""), err
-		case ErrNotList:
-			v, e := t.val()
-			return setIndex(list, i, typedVal(v)), e
-		default:
+		v, e := t.value()
+		if e != nil && e != io.EOF {
 			return list, e
 		}
+		return setIndex(list, i, v), e
 	case last == '[':
 		// now we have a nested list. Read the index and handle.
-		i, err := t.keyIndex()
+		j, err := t.keyIndex()
 		if err != nil {
 			return list, fmt.Errorf("error parsing index: %s", err)
 		}
+		// Find or create the nested list at i.
+		var inner []interface{}
+		if len(list) > i {
+			inner, _ = list[i].([]interface{})
+		}
+		if inner == nil {
+			inner = []interface{}{}
+		}
 		// Now we need to get the value after the ].
-		list2, err := t.listItem(list, i)
-		return setIndex(list, i, list2), err
+		inner, err = t.listItem(inner, j)
+		return setIndex(list, i, inner), err
 	case last == '.':
 		// We have a nested object. Send to t.key
-		inner := map[string]interface{}{}
+		var inner map[string]interface{}
 		if len(list) > i {
-			inner = list[i].(map[string]interface{})
+			inner, _ = list[i].(map[string]interface{})
+		}
+		if inner == nil {
+			inner = map[string]interface{}{}
 		}
 
 		// Recurse
@@ -234,6 +290,67 @@ func (t *parser) listItem(list []interface{}, i int) ([]interface{}, error) {
 	}
 }
 
+// value reads the value after an '='. It returns io.EOF if the value ends the
+// line.
+func (t *parser) value() (interface{}, error) {
+	if t.json {
+		return t.jsonVal()
+	}
+
+	// FIXME: Get value list first
+	vl, e := t.valList()
+	switch e {
+	case nil:
+		return vl, nil
+	case io.EOF:
+		return "", e
+	case ErrNotList:
+		rs, e := t.val()
+		if e != nil && e != io.EOF {
+			return nil, e
+		}
+		v, err := t.reader(rs)
+		if err != nil {
+			return nil, err
+		}
+		return v, e
+	default:
+		return nil, e
+	}
+}
+
+// jsonVal decodes a JSON document, and consumes the ',' that may follow it,
+// along with surrounding blanks.
+func (t *parser) jsonVal() (interface{}, error) {
+	r := strings.NewReader(t.sc.String())
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			return "", err
+		}
+		return nil, fmt.Errorf("invalid JSON value: %s", err)
+	}
+
+	// The decoder reads ahead, so the rest of the line is what it buffered
+	// plus what it did not read yet.
+	rest, err := ioutil.ReadAll(io.MultiReader(dec.Buffered(), r))
+	if err != nil {
+		return nil, err
+	}
+	rest = bytes.TrimLeftFunc(rest, unicode.IsSpace)
+	t.sc.Reset()
+	switch {
+	case len(rest) == 0:
+		return v, io.EOF
+	case rest[0] != ',':
+		return nil, fmt.Errorf("unexpected data after JSON value: %q", rest)
+	}
+	t.sc.Write(bytes.TrimLeftFunc(rest[1:], unicode.IsSpace))
+	return v, nil
+}
+
 func (t *parser) val() ([]rune, error) {
 	stop := runeSet([]rune{','})
 	v, _, err := runesUntil(t.sc, stop)
@@ -265,10 +382,18 @@ func (t *parser) valList() ([]interface{}, error) {
 			if r, _, e := t.sc.ReadRune(); e == nil && r != ',' {
 				t.sc.UnreadRune()
 			}
-			list = append(list, typedVal(v))
+			vv, err := t.reader(v)
+			if err != nil {
+				return list, err
+			}
+			list = append(list, vv)
 			return list, nil
 		case last == ',':
-			list = append(list, typedVal(v))
+			vv, err := t.reader(v)
+			if err != nil {
+				return list, err
+			}
+			list = append(list, vv)
 		}
 	}
 }
@@ -298,19 +423,31 @@ func inMap(k rune, m map[rune]bool) bool {
 	return ok
 }
 
-func typedVal(v []rune) interface{} {
+func typedVal(v []rune) (interface{}, error) {
 	val := string(v)
 	if strings.EqualFold(val, "true") {
-		return true
+		return true, nil
 	}
 
 	if strings.EqualFold(val, "false") {
-		return false
+		return false, nil
 	}
 
 	if iv, err := strconv.ParseInt(val, 10, 64); err == nil {
-		return iv
+		return iv, nil
 	}
 
-	return val
+	return val, nil
+}
+
+func stringVal(v []rune) (interface{}, error) {
+	return string(v), nil
+}
+
+func fileVal(v []rune) (interface{}, error) {
+	b, err := ioutil.ReadFile(string(v))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
 }
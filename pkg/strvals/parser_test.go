@@ -16,6 +16,9 @@ limitations under the License.
 package strvals
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ghodss/yaml"
@@ -251,6 +254,87 @@ func TestParseSet(t *testing.T) {
 			str:    "nested[1][1]=1",
 			expect: map[string]interface{}{"nested": []interface{}{nil, []interface{}{nil, 1}}},
 		},
+		{
+			str:    "nested[0][1]=1,nested[0][0]=0,nested[1][0]=2",
+			expect: map[string]interface{}{"nested": []interface{}{[]interface{}{0, 1}, []interface{}{2}}},
+		},
+		{
+			str:    "nested[0][1][2]=deep",
+			expect: map[string]interface{}{"nested": []interface{}{[]interface{}{nil, []interface{}{nil, nil, "deep"}}}},
+		},
+		{
+			str: "nested[1][0].name=a,nested[1][0].value=b",
+			expect: map[string]interface{}{
+				"nested": []interface{}{nil, []interface{}{map[string]interface{}{"name": "a", "value": "b"}}},
+			},
+		},
+		{
+			str: "ingress.hosts[0].name=example.com,ingress.hosts[0].paths[1]=/api",
+			expect: map[string]interface{}{
+				"ingress": map[string]interface{}{
+					"hosts": []interface{}{
+						map[string]interface{}{"name": "example.com", "paths": []interface{}{nil, "/api"}},
+					},
+				},
+			},
+		},
+		{
+			str:    "list[2]=c,list[1].name=b",
+			expect: map[string]interface{}{"list": []interface{}{nil, map[string]interface{}{"name": "b"}, "c"}},
+		},
+		{
+			str:    "list[0]={a,b}",
+			expect: map[string]interface{}{"list": []interface{}{[]interface{}{"a", "b"}}},
+		},
+		{
+			str:    "name=value,name[0]=first",
+			expect: map[string]interface{}{"name": []interface{}{"first"}},
+		},
+		{
+			str:    "name=value,name.inner=first",
+			expect: map[string]interface{}{"name": map[string]interface{}{"inner": "first"}},
+		},
+		{
+			str: "list[-1]=foo",
+			err: true,
+		},
+		{
+			str: "list[65537]=foo",
+			err: true,
+		},
+		{
+			str: "list[a]=foo",
+			err: true,
+		},
+		{
+			str: "list[0",
+			err: true,
+		},
+		// Escaping
+		{
+			str:    `kubernetes\.io/role=master`,
+			expect: map[string]interface{}{"kubernetes.io/role": "master"},
+		},
+		{
+			str:    `nodeSelector.kubernetes\.io/role=master`,
+			expect: map[string]interface{}{"nodeSelector": map[string]interface{}{"kubernetes.io/role": "master"}},
+		},
+		{
+			str:    `name\[0\]=value`,
+			expect: map[string]interface{}{"name[0]": "value"},
+		},
+		{
+			str:    `a\=b=c,d\,e=f`,
+			expect: map[string]interface{}{"a=b": "c", "d,e": "f"},
+		},
+		{
+			str: `list[0].annotations.example\.com/name=value`,
+			expect: map[string]interface{}{
+				"list": []interface{}{
+					map[string]interface{}{"annotations": map[string]interface{}{"example.com/name": "value"}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -280,6 +364,205 @@ func TestParseSet(t *testing.T) {
 	}
 }
 
+func TestParseSetString(t *testing.T) {
+	tests := []struct {
+		str    string
+		expect map[string]interface{}
+		err    bool
+	}{
+		{
+			str:    "version=0123,tag=1.10",
+			expect: map[string]interface{}{"version": "0123", "tag": "1.10"},
+		},
+		{
+			str:    "enabled=true,count=1",
+			expect: map[string]interface{}{"enabled": "true", "count": "1"},
+		},
+		{
+			str:    "list={1,true,0.10}",
+			expect: map[string]interface{}{"list": []interface{}{"1", "true", "0.10"}},
+		},
+		{
+			str:    "outer.inner=1,list[0]=007",
+			expect: map[string]interface{}{"outer": map[string]interface{}{"inner": "1"}, "list": []interface{}{"007"}},
+		},
+		{
+			str: "name",
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseString(tt.str)
+		if err != nil {
+			if tt.err {
+				continue
+			}
+			t.Fatalf("%s: %s", tt.str, err)
+		}
+		if tt.err {
+			t.Errorf("%s: Expected error. Got nil", tt.str)
+		}
+		assertEqualValues(t, tt.str, tt.expect, got)
+	}
+}
+
+func TestParseIntoFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-strvals-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert := filepath.Join(dir, "tls.crt")
+	if err := ioutil.WriteFile(cert, []byte("-----BEGIN CERTIFICATE-----\n0123\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]interface{}{}
+	if err := ParseIntoFile("tls.cert="+cert, got); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"tls": map[string]interface{}{"cert": "-----BEGIN CERTIFICATE-----\n0123\n"},
+	}
+	assertEqualValues(t, "tls.cert", expect, got)
+
+	if err := ParseIntoFile("tls.key="+filepath.Join(dir, "missing"), got); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestParseIntoJSON(t *testing.T) {
+	tests := []struct {
+		str    string
+		expect map[string]interface{}
+		err    bool
+	}{
+		{
+			str: `resources={"limits": {"cpu": "100m", "memory": 128}}`,
+			expect: map[string]interface{}{
+				"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "100m", "memory": 128},
+				},
+			},
+		},
+		{
+			str:    `list=[1, "two", {"three": 3}],name="a,b"`,
+			expect: map[string]interface{}{"list": []interface{}{1, "two", map[string]interface{}{"three": 3}}, "name": "a,b"},
+		},
+		{
+			str:    `outer.inner=null, list[1]=true`,
+			expect: map[string]interface{}{"outer": map[string]interface{}{"inner": nil}, "list": []interface{}{nil, true}},
+		},
+		{
+			str:    `version="0123"`,
+			expect: map[string]interface{}{"version": "0123"},
+		},
+		{
+			str: `name=unquoted`,
+			err: true,
+		},
+		{
+			str: `name={"a": 1} trailing`,
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got := map[string]interface{}{}
+		err := ParseIntoJSON(tt.str, got)
+		if err != nil {
+			if tt.err {
+				continue
+			}
+			t.Fatalf("%s: %s", tt.str, err)
+		}
+		if tt.err {
+			t.Errorf("%s: Expected error. Got nil", tt.str)
+		}
+		assertEqualValues(t, tt.str, tt.expect, got)
+	}
+}
+
+// assertEqualValues compares values through their YAML serialization.
+func assertEqualValues(t *testing.T, name string, expect, got map[string]interface{}) {
+	y1, err := yaml.Marshal(expect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y2, err := yaml.Marshal(got)
+	if err != nil {
+		t.Fatalf("Error serializing parsed value: %s", err)
+	}
+	if string(y1) != string(y2) {
+		t.Errorf("%s: Expected:\n%s\nGot:\n%s", name, y1, y2)
+	}
+}
+
+func TestParseIntoLists(t *testing.T) {
+	tests := []struct {
+		name   string
+		dest   map[string]interface{}
+		input  string
+		expect map[string]interface{}
+	}{
+		{
+			name: "override an element of an existing list",
+			dest: map[string]interface{}{
+				"env": []interface{}{
+					map[string]interface{}{"name": "A", "value": "1"},
+					map[string]interface{}{"name": "B", "value": "2"},
+					map[string]interface{}{"name": "C", "value": "3"},
+				},
+			},
+			input: "env[2].value=4",
+			expect: map[string]interface{}{
+				"env": []interface{}{
+					map[string]interface{}{"name": "A", "value": "1"},
+					map[string]interface{}{"name": "B", "value": "2"},
+					map[string]interface{}{"name": "C", "value": 4},
+				},
+			},
+		},
+		{
+			name:   "grow an existing list",
+			dest:   map[string]interface{}{"list": []interface{}{"a"}},
+			input:  "list[2]=c",
+			expect: map[string]interface{}{"list": []interface{}{"a", nil, "c"}},
+		},
+		{
+			name: "override an element of a nested list",
+			dest: map[string]interface{}{
+				"matrix": []interface{}{
+					[]interface{}{1, 2},
+					[]interface{}{3, 4},
+				},
+			},
+			input: "matrix[1][0]=5",
+			expect: map[string]interface{}{
+				"matrix": []interface{}{
+					[]interface{}{1, 2},
+					[]interface{}{5, 4},
+				},
+			},
+		},
+		{
+			name:   "replace a scalar with a list",
+			dest:   map[string]interface{}{"list": "scalar"},
+			input:  "list[0]=a",
+			expect: map[string]interface{}{"list": []interface{}{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := ParseInto(tt.input, tt.dest); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		assertEqualValues(t, tt.name, tt.expect, tt.dest)
+	}
+}
+
 func TestParseInto(t *testing.T) {
 	got := map[string]interface{}{
 		"outer": map[string]interface{}{
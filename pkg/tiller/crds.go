@@ -0,0 +1,207 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	util "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// crdsDir is the chart directory holding custom resource definitions. Files in
+// it are installed as they are, without being rendered.
+const crdsDir = "crds/"
+
+var docSep = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
+
+// crdHead is the part of a custom resource definition describing the API
+// versions it serves.
+type crdHead struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Group    string `json:"group"`
+		Version  string `json:"version"`
+		Versions []struct {
+			Name string `json:"name"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+// crdFiles returns the files in the crds directory of ch and of its
+// dependencies, named like the rendered templates of the same chart.
+func crdFiles(ch *chart.Chart, parent string) map[string]string {
+	files := map[string]string{}
+	name := path.Join(parent, ch.Metadata.Name)
+	for _, f := range ch.Files {
+		if strings.HasPrefix(f.TypeUrl, crdsDir) {
+			files[path.Join(name, f.TypeUrl)] = string(f.Value)
+		}
+	}
+	for _, dep := range ch.Dependencies {
+		for k, v := range crdFiles(dep, path.Join(name, "charts")) {
+			files[k] = v
+		}
+	}
+	return files
+}
+
+// withCRDVersions returns a copy of vs that also contains the API versions
+// served by the custom resource definitions in files. Resources of those
+// versions can be installed along with their definition.
+func withCRDVersions(vs chartutil.VersionSet, files map[string]string) chartutil.VersionSet {
+	out := chartutil.VersionSet{}
+	for v := range vs {
+		out[v] = struct{}{}
+	}
+	for _, content := range files {
+		for _, m := range util.SplitManifests(content) {
+			var crd crdHead
+			if err := yaml.Unmarshal([]byte(m), &crd); err != nil || crd.Kind != kube.CRDKind {
+				continue
+			}
+			if crd.Spec.Version != "" {
+				out[crd.Spec.Group+"/"+crd.Spec.Version] = struct{}{}
+			}
+			for _, v := range crd.Spec.Versions {
+				out[crd.Spec.Group+"/"+v.Name] = struct{}{}
+			}
+		}
+	}
+	return out
+}
+
+// splitManifest splits a release manifest into its documents, keeping their
+// order and their "# Source:" comments.
+func splitManifest(doc string) []manifest {
+	docs := []manifest{}
+	for _, d := range docSep.Split(strings.TrimSpace(doc), -1) {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		var head util.SimpleHead
+		yaml.Unmarshal([]byte(d), &head)
+		docs = append(docs, manifest{content: d, head: &head})
+	}
+	return docs
+}
+
+// joinManifest joins documents back into a release manifest.
+func joinManifest(docs []manifest) string {
+	b := bytes.NewBuffer(nil)
+	for _, d := range docs {
+		b.WriteString("\n---\n" + d.content)
+	}
+	return b.String()
+}
+
+// splitCRDs separates the custom resource definitions of a release manifest
+// from the other resources.
+func splitCRDs(doc string) (crds, rest []manifest) {
+	return splitCRDManifests(splitManifest(doc))
+}
+
+// splitCRDManifests separates custom resource definitions from the other
+// manifests, keeping their order.
+func splitCRDManifests(manifests []manifest) (crds, rest []manifest) {
+	for _, m := range manifests {
+		if m.head.Kind == kube.CRDKind {
+			crds = append(crds, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	return crds, rest
+}
+
+// servedOnly drops from a manifest every document whose API version is not
+// served by the cluster yet. Those are resources of the kinds defined by the
+// custom resource definitions of the release, which cannot be built before the
+// definitions are installed.
+func servedOnly(doc string, apis chartutil.VersionSet) string {
+	docs := []manifest{}
+	for _, d := range splitManifest(doc) {
+		if d.head.Version == "" || apis.Has(d.head.Version) {
+			docs = append(docs, d)
+		}
+	}
+	return joinManifest(docs)
+}
+
+// previousVersions returns, for every document of target, the document of
+// current that describes the same resource by kind and name. Documents that
+// are new in target are returned as they are.
+func previousVersions(current, target []manifest) []manifest {
+	byName := map[string]manifest{}
+	for _, c := range current {
+		byName[resourceName(c)] = c
+	}
+	previous := make([]manifest, len(target))
+	for i, t := range target {
+		if c, ok := byName[resourceName(t)]; ok {
+			previous[i] = c
+		} else {
+			previous[i] = t
+		}
+	}
+	return previous
+}
+
+func resourceName(m manifest) string {
+	if m.head.Metadata == nil {
+		return m.head.Kind
+	}
+	return m.head.Kind + "/" + m.head.Metadata.Name
+}
+
+// applyCRDs creates or updates the custom resource definitions of target and
+// waits until they are established, so that the other resources of target can
+// use them.
+//
+// Definitions are never deleted here: those that are only in current are kept,
+// and existing definitions that are new to the release are left as they are.
+func applyCRDs(namespace string, current, target []manifest, timeout int64, kubeClient environment.KubeClient) error {
+	if len(target) == 0 {
+		return nil
+	}
+	c := bytes.NewBufferString(joinManifest(previousVersions(current, target)))
+	t := bytes.NewBufferString(joinManifest(target))
+	if err := kubeClient.Update(namespace, c, t, false, false, timeout, false); err != nil {
+		return err
+	}
+	return kubeClient.WaitForCRDs(bytes.NewBufferString(joinManifest(target)), timeout)
+}
+
+func summarizeKeptCRDs(manifests []manifest) string {
+	message := "These custom resource definitions were kept, as deleting them deletes all of their resources:\n"
+	for _, m := range manifests {
+		name := ""
+		if m.head.Metadata != nil {
+			name = m.head.Metadata.Name
+		}
+		message = message + "[" + m.head.Kind + "] " + name + "\n"
+	}
+	return message
+}
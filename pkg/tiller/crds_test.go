@@ -0,0 +1,269 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var crdManifest = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1
+  names:
+    kind: CronTab
+    plural: crontabs
+  scope: Namespaced`
+
+var crManifest = `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: my-crontab
+spec:
+  cronSpec: "* * * * */5"`
+
+var configMapManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  name: value`
+
+// recordingKubeClient records the manifests it is asked to apply.
+type recordingKubeClient struct {
+	environment.PrintingKubeClient
+	calls []string
+}
+
+func (r *recordingKubeClient) record(call string, in io.Reader) {
+	b, _ := ioutil.ReadAll(in)
+	r.calls = append(r.calls, call+":"+string(b))
+}
+
+func (r *recordingKubeClient) Create(ns string, in io.Reader, timeout int64, shouldWait bool) error {
+	r.record("create", in)
+	return nil
+}
+
+func (r *recordingKubeClient) Update(ns string, current, target io.Reader, force, recreate bool, timeout int64, shouldWait bool) error {
+	b, _ := ioutil.ReadAll(current)
+	r.record("update:"+string(b)+"=>", target)
+	return nil
+}
+
+func (r *recordingKubeClient) Delete(ns string, in io.Reader) error {
+	r.record("delete", in)
+	return nil
+}
+
+func (r *recordingKubeClient) WaitForCRDs(in io.Reader, timeout int64) error {
+	r.record("wait", in)
+	return nil
+}
+
+func crdChartStub() *chart.Chart {
+	ch := chartStub()
+	ch.Files = []*any.Any{{TypeUrl: "crds/crontab.yaml", Value: []byte(crdManifest)}}
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/crontab", Data: []byte(crManifest)})
+	return ch
+}
+
+func TestCRDFiles(t *testing.T) {
+	ch := crdChartStub()
+	ch.Files = append(ch.Files, &any.Any{TypeUrl: "README.md", Value: []byte("read me")})
+	ch.Dependencies = []*chart.Chart{{
+		Metadata: &chart.Metadata{Name: "sub"},
+		Files:    []*any.Any{{TypeUrl: "crds/sub.yaml", Value: []byte(crdManifest)}},
+	}}
+
+	files := crdFiles(ch, "")
+	if len(files) != 2 {
+		t.Errorf("expected 2 files, got %v", files)
+	}
+	for _, name := range []string{"hello/crds/crontab.yaml", "hello/charts/sub/crds/sub.yaml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected %s in %v", name, files)
+		}
+	}
+}
+
+func TestWithCRDVersions(t *testing.T) {
+	vs := chartutil.NewVersionSet("v1")
+	got := withCRDVersions(vs, map[string]string{"a": crdManifest, "b": crManifest})
+
+	if !got.Has("v1") || !got.Has("stable.example.com/v1") {
+		t.Errorf("unexpected versions: %v", got)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 versions, got %v", got)
+	}
+	if vs.Has("stable.example.com/v1") {
+		t.Error("expected the original version set to be left untouched")
+	}
+}
+
+func TestSplitCRDs(t *testing.T) {
+	manifest := "\n---\n# Source: hello/templates/crontab\n" + crManifest +
+		"\n---\n# Source: hello/crds/crontab.yaml\n" + crdManifest
+
+	crds, rest := splitCRDs(manifest)
+	if len(crds) != 1 || crds[0].head.Metadata.Name != "crontabs.stable.example.com" {
+		t.Errorf("unexpected CRDs: %v", crds)
+	}
+	if len(rest) != 1 || rest[0].head.Kind != "CronTab" {
+		t.Errorf("unexpected resources: %v", rest)
+	}
+
+	served := servedOnly(manifest, chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1"))
+	if strings.Contains(served, "CronTab\n") || !strings.Contains(served, "# Source: hello/crds/crontab.yaml") {
+		t.Errorf("expected only the CRD to be served, got %s", served)
+	}
+}
+
+func TestRenderResources_CRDs(t *testing.T) {
+	rs := rsFixture()
+	vs := chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1")
+
+	_, manifest, _, err := rs.renderResources(crdChartStub(), chartutil.Values{}, vs, false)
+	if err != nil {
+		t.Fatalf("Failed render: %s", err)
+	}
+	crd := strings.Index(manifest.String(), "# Source: hello/crds/crontab.yaml")
+	cr := strings.Index(manifest.String(), "# Source: hello/templates/crontab")
+	if crd < 0 || cr < 0 || crd > cr {
+		t.Errorf("expected the CRD before its resources, got %s", manifest)
+	}
+}
+
+func TestLocalReleaseModule_CreateCRDs(t *testing.T) {
+	kc := &recordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	env := MockEnvironment()
+	env.KubeClient = kc
+	rel := releaseStub()
+	rel.Manifest = "\n---\n# Source: hello/crds/crontab.yaml\n" + crdManifest +
+		"\n---\n# Source: hello/templates/crontab\n" + crManifest
+
+	m := &LocalReleaseModule{}
+	if err := m.Create(rel, &services.InstallReleaseRequest{}, env); err != nil {
+		t.Fatalf("Failed create: %s", err)
+	}
+
+	if len(kc.calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d: %v", len(kc.calls), kc.calls)
+	}
+	for i, prefix := range []string{"update:", "wait:", "create:"} {
+		if !strings.HasPrefix(kc.calls[i], prefix) {
+			t.Errorf("expected call %d to be %s, got %s", i, prefix, kc.calls[i])
+		}
+	}
+	if !strings.Contains(kc.calls[1], "CustomResourceDefinition") || strings.Contains(kc.calls[1], "kind: CronTab") {
+		t.Errorf("expected only CRDs to be waited for, got %s", kc.calls[1])
+	}
+	if !strings.Contains(kc.calls[2], "kind: CronTab") || strings.Contains(kc.calls[2], "CustomResourceDefinition") {
+		t.Errorf("expected the custom resource to be created after the CRD, got %s", kc.calls[2])
+	}
+}
+
+func TestDeleteRelease_CRDs(t *testing.T) {
+	rel := releaseStub()
+	rel.Manifest = "\n---\n# Source: hello/crds/crontab.yaml\n" + crdManifest +
+		"\n---\n# Source: hello/templates/crontab\n" + crManifest
+	vs := chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1")
+
+	for _, deleteCRDs := range []bool{false, true} {
+		kc := &recordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+		kept, errs := DeleteRelease(rel, vs, kc, deleteCRDs)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		calls := strings.Join(kc.calls, "\n")
+		deleted := strings.Contains(calls, "CustomResourceDefinition")
+		if deleted != deleteCRDs {
+			t.Errorf("deleteCRDs %t: expected CRD deletion %t, got %v", deleteCRDs, deleteCRDs, kc.calls)
+		}
+		if !strings.Contains(calls, "kind: CronTab") {
+			t.Errorf("expected the custom resource to be deleted, got %v", kc.calls)
+		}
+		if keptCRD := strings.Contains(kept, "[CustomResourceDefinition] crontabs.stable.example.com"); keptCRD == deleteCRDs {
+			t.Errorf("deleteCRDs %t: unexpected kept summary %q", deleteCRDs, kept)
+		}
+	}
+}
+
+// failingKubeClient fails to delete any manifest of the given kind.
+type failingKubeClient struct {
+	recordingKubeClient
+	kind string
+}
+
+func (f *failingKubeClient) Delete(ns string, in io.Reader) error {
+	b, _ := ioutil.ReadAll(in)
+	if strings.Contains(string(b), "kind: "+f.kind+"\n") {
+		return fmt.Errorf("no matches for kind %q", f.kind)
+	}
+	return f.recordingKubeClient.Delete(ns, bytes.NewReader(b))
+}
+
+func TestDeleteRelease_KeepsGoing(t *testing.T) {
+	rel := releaseStub()
+	rel.Manifest = "\n---\n# Source: hello/templates/crontab\n" + crManifest +
+		"\n---\n# Source: hello/templates/configmap\n" + configMapManifest
+	vs := chartutil.NewVersionSet("v1", "stable.example.com/v1")
+
+	kc := &failingKubeClient{
+		recordingKubeClient: recordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}},
+		kind:                "CronTab",
+	}
+	_, errs := DeleteRelease(rel, vs, kc, false)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "CronTab") {
+		t.Errorf("expected a single CronTab error, got %v", errs)
+	}
+	if len(kc.calls) != 1 || !strings.Contains(kc.calls[0], "kind: ConfigMap") {
+		t.Errorf("expected the remaining resources to be deleted, got %v", kc.calls)
+	}
+}
+
+func TestUpdateRelease_KeepsCRDs(t *testing.T) {
+	kc := &recordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	current := releaseStub()
+	current.Manifest = "\n---\n" + crdManifest + "\n---\n" + crManifest
+	target := releaseStub()
+	target.Manifest = "\n---\n" + manifestWithHook
+
+	if err := UpdateRelease(current, target, false, false, 0, false, kc); err != nil {
+		t.Fatal(err)
+	}
+	if len(kc.calls) != 1 || strings.Contains(kc.calls[0], "CustomResourceDefinition") {
+		t.Errorf("expected CRDs to be left out of the update, got %v", kc.calls)
+	}
+}
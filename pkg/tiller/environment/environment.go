@@ -147,6 +147,43 @@ type KubeClient interface {
 	// WaitAndGetCompletedPodPhase waits up to a timeout until a pod enters a completed phase
 	// and returns said phase (PodSucceeded or PodFailed qualify)
 	WaitAndGetCompletedPodPhase(namespace string, reader io.Reader, timeout time.Duration) (api.PodPhase, error)
+
+	// Adopt stamps the release annotations of the resources in reader onto the
+	// existing objects with the same kind and name.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	Adopt(namespace string, reader io.Reader, dryRun bool) ([]*kube.AdoptResult, error)
+
+	// Ownership classifies the existing object with the same kind and name of
+	// every resource in reader.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	Ownership(namespace string, reader io.Reader) ([]*kube.OwnershipResult, error)
+
+	// Validate checks every resource in reader against the schema of the
+	// cluster and reports all errors at once.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	Validate(reader io.Reader) error
+
+	// WaitForCRDs waits until every custom resource definition in reader is
+	// established, and makes sure that resources of the kinds they define can
+	// be built afterwards.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	WaitForCRDs(reader io.Reader, timeout int64) error
+
+	// Lookup returns the live object with the given apiVersion, kind,
+	// namespace and name, or an empty map if it does not exist.
+	Lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error)
 }
 
 // PrintingKubeClient implements KubeClient, but simply prints the reader to
@@ -203,6 +240,34 @@ func (p *PrintingKubeClient) WaitAndGetCompletedPodPhase(namespace string, reade
 	return api.PodUnknown, err
 }
 
+// Adopt implements KubeClient Adopt.
+func (p *PrintingKubeClient) Adopt(ns string, r io.Reader, dryRun bool) ([]*kube.AdoptResult, error) {
+	_, err := io.Copy(p.Out, r)
+	return []*kube.AdoptResult{}, err
+}
+
+// Ownership implements KubeClient Ownership.
+//
+// It reports no existing objects.
+func (p *PrintingKubeClient) Ownership(ns string, r io.Reader) ([]*kube.OwnershipResult, error) {
+	return []*kube.OwnershipResult{}, nil
+}
+
+// Validate implements KubeClient Validate.
+func (p *PrintingKubeClient) Validate(r io.Reader) error {
+	return nil
+}
+
+// WaitForCRDs implements KubeClient WaitForCRDs.
+func (p *PrintingKubeClient) WaitForCRDs(r io.Reader, timeout int64) error {
+	return nil
+}
+
+// Lookup implements KubeClient Lookup.
+func (p *PrintingKubeClient) Lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
 // Environment provides the context for executing a client request.
 //
 // All services in a context are concurrency safe.
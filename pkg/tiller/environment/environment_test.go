@@ -65,6 +65,23 @@ func (k *mockKubeClient) WaitAndGetCompletedPodPhase(namespace string, reader io
 	return api.PodUnknown, nil
 }
 
+func (k *mockKubeClient) Adopt(ns string, r io.Reader, dryRun bool) ([]*kube.AdoptResult, error) {
+	return []*kube.AdoptResult{}, nil
+}
+
+func (k *mockKubeClient) Ownership(ns string, r io.Reader) ([]*kube.OwnershipResult, error) {
+	return []*kube.OwnershipResult{}, nil
+}
+func (k *mockKubeClient) Validate(r io.Reader) error {
+	return nil
+}
+func (k *mockKubeClient) WaitForCRDs(r io.Reader, timeout int64) error {
+	return nil
+}
+func (k *mockKubeClient) Lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
 func (k *mockKubeClient) WaitAndGetCompletedPodStatus(namespace string, reader io.Reader, timeout time.Duration) (api.PodPhase, error) {
 	return "", nil
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,6 +28,7 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	util "k8s.io/helm/pkg/releaseutil"
 )
@@ -71,10 +73,19 @@ type manifestFile struct {
 //
 // Files that do not parse into the expected format are simply placed into a map and
 // returned.
-func sortManifests(files map[string]string, apis chartutil.VersionSet, sort SortOrder) ([]*release.Hook, []manifest, error) {
+func sortManifests(files map[string]string, apis chartutil.VersionSet, order SortOrder) ([]*release.Hook, []manifest, error) {
 	result := &result{}
 
-	for filePath, c := range files {
+	// Visit files in a stable order, so that manifests of the same kind are
+	// always sorted the same way.
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		c := files[filePath]
 
 		// Skip partials. We could return these as a separate map, but there doesn't
 		// seem to be any need for that at this time.
@@ -98,7 +109,40 @@ func sortManifests(files map[string]string, apis chartutil.VersionSet, sort Sort
 		}
 	}
 
-	return result.hooks, sortByKind(result.generic, sort), nil
+	return result.hooks, sortByKind(result.generic, order), nil
+}
+
+// Manifest is a rendered resource, named after the template it comes from.
+type Manifest struct {
+	Name    string
+	Content string
+}
+
+// SortTemplates sorts the rendered templates of a chart the way Tiller does
+// before installing them: hooks are separated from the other manifests, which
+// are sorted in install order. The custom resource definitions of the chart
+// are added to files.
+//
+// Only resources of the API versions in apis, or served by the custom
+// resource definitions of the chart, are accepted.
+func SortTemplates(ch *chart.Chart, files map[string]string, apis chartutil.VersionSet) ([]*release.Hook, []Manifest, error) {
+	hooks, manifests, err := sortTemplates(ch, files, apis)
+	out := make([]Manifest, len(manifests))
+	for i, m := range manifests {
+		out[i] = Manifest{Name: m.name, Content: m.content}
+	}
+	return hooks, out, err
+}
+
+// sortTemplates adds the custom resource definitions of ch to files, and sorts
+// them in install order.
+func sortTemplates(ch *chart.Chart, files map[string]string, apis chartutil.VersionSet) ([]*release.Hook, []manifest, error) {
+	// Custom resource definitions are not templates, so they are added as they
+	// are. The API versions they serve are available to the other manifests.
+	for k, v := range crdFiles(ch, "") {
+		files[k] = v
+	}
+	return sortManifests(files, withCRDVersions(apis, files), InstallOrder)
 }
 
 // sort takes a manifestFile object which may contain multiple resource definition
@@ -27,6 +27,7 @@ type SortOrder []string
 //
 // Those occurring earlier in the list get installed before those occurring later in the list.
 var InstallOrder SortOrder = []string{
+	"CustomResourceDefinition",
 	"Namespace",
 	"ResourceQuota",
 	"LimitRange",
@@ -77,6 +78,7 @@ var UninstallOrder SortOrder = []string{
 	"LimitRange",
 	"ResourceQuota",
 	"Namespace",
+	"CustomResourceDefinition",
 }
 
 // sortByKind does an in-place sort of manifests by Kind.
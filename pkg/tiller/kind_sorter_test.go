@@ -45,6 +45,11 @@ func TestKindSorter(t *testing.T) {
 			content: "",
 			head:    &util.SimpleHead{Kind: "CronJob"},
 		},
+		{
+			name:    "0",
+			content: "",
+			head:    &util.SimpleHead{Kind: "CustomResourceDefinition"},
+		},
 		{
 			name:    "n",
 			content: "",
@@ -147,8 +152,8 @@ func TestKindSorter(t *testing.T) {
 		order       SortOrder
 		expected    string
 	}{
-		{"install", InstallOrder, "abcdefghijklmnopqrstuv!"},
-		{"uninstall", UninstallOrder, "vmutsrqponlkjihgfedcba!"},
+		{"install", InstallOrder, "0abcdefghijklmnopqrstuv!"},
+		{"uninstall", UninstallOrder, "vmutsrqponlkjihgfedcba0!"},
 	} {
 		var buf bytes.Buffer
 		t.Run(test.description, func(t *testing.T) {
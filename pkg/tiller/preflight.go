@@ -0,0 +1,74 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// preflight checks that every resource of a release can be applied before any
+// of them is touched.
+//
+// A resource conflicts if its live object is owned by another release or by
+// another chart path of the release, or is not managed by Helm at all. If allowOwned is false, live objects that belong
+// to the release itself conflict too, as they cannot be created again, except
+// for custom resource definitions, which are kept when a release is deleted.
+// All conflicts are reported in a single error.
+func (s *ReleaseServer) preflight(r *release.Release, allowOwned bool) error {
+	if len(strings.TrimSpace(r.Manifest)) == 0 {
+		return nil
+	}
+
+	// Resources of kinds that are not served yet cannot exist.
+	vs, err := GetVersionSet(s.clientset.Discovery())
+	if err != nil {
+		return fmt.Errorf("Could not get apiVersions from Kubernetes: %s", err)
+	}
+	b := bytes.NewBufferString(servedOnly(r.Manifest, vs))
+
+	s.Log("checking ownership of resources for %s", r.Name)
+	results, err := s.env.KubeClient.Ownership(r.Namespace, b)
+	if err != nil && err != kube.ErrNoObjectsVisited {
+		return fmt.Errorf("pre-flight check failed: %s", err)
+	}
+
+	conflicts := []string{}
+	for _, res := range results {
+		switch res.Ownership {
+		case kube.Absent:
+			continue
+		case kube.OwnedByRelease:
+			if allowOwned || res.Kind == kube.CRDKind {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s %q already exists", res.Kind, res.Name))
+		case kube.Unmanaged:
+			conflicts = append(conflicts, fmt.Sprintf("%s %q already exists and is not managed by Helm (see 'helm adopt')", res.Kind, res.Name))
+		default:
+			conflicts = append(conflicts, res.String())
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("pre-flight check failed, %d resource(s) conflict with release %s:\n\t%s", len(conflicts), r.Name, strings.Join(conflicts, "\n\t"))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+type ownershipKubeClient struct {
+	environment.PrintingKubeClient
+	results []*kube.OwnershipResult
+}
+
+func (o *ownershipKubeClient) Ownership(ns string, r io.Reader) ([]*kube.OwnershipResult, error) {
+	return o.results, nil
+}
+
+func newOwnershipKubeClient(results ...*kube.OwnershipResult) *ownershipKubeClient {
+	return &ownershipKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		results:            results,
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	absent := &kube.OwnershipResult{Kind: "ConfigMap", Name: "absent", Ownership: kube.Absent}
+	owned := &kube.OwnershipResult{Kind: "ConfigMap", Name: "owned", Ownership: kube.OwnedByRelease, Owner: "angry-panda"}
+	other := &kube.OwnershipResult{Kind: "Secret", Name: "other", Ownership: kube.OwnedByOtherRelease, Owner: "sad-panda"}
+	unmanaged := &kube.OwnershipResult{Kind: "Service", Name: "unmanaged", Ownership: kube.Unmanaged}
+	otherPath := &kube.OwnershipResult{Kind: "Service", Name: "twice", Ownership: kube.OwnedByOtherPath, Owner: "angry-panda", Path: "parent/sub"}
+
+	tests := []struct {
+		name       string
+		results    []*kube.OwnershipResult
+		allowOwned bool
+		expect     []string
+	}{
+		{"absent", []*kube.OwnershipResult{absent}, false, nil},
+		{"owned on upgrade", []*kube.OwnershipResult{absent, owned}, true, nil},
+		{"owned on install", []*kube.OwnershipResult{owned}, false, []string{`ConfigMap "owned" already exists`}},
+		{"all conflicts", []*kube.OwnershipResult{absent, other, unmanaged, otherPath}, true, []string{
+			"3 resource(s) conflict",
+			`Secret "other" is owned by release "sad-panda"`,
+			`Service "unmanaged" already exists and is not managed by Helm`,
+			`Service "twice" is already rendered from chart path "parent/sub" of release "angry-panda"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		rs := rsFixture()
+		rs.env.KubeClient = newOwnershipKubeClient(tt.results...)
+		rel := releaseStub()
+		rel.Manifest = "hello: world"
+
+		err := rs.preflight(rel, tt.allowOwned)
+		if len(tt.expect) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+		for _, e := range tt.expect {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("%s: expected %q in error, got %s", tt.name, e, err)
+			}
+		}
+	}
+}
+
+func TestInstallRelease_PreflightConflict(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = newOwnershipKubeClient(
+		&kube.OwnershipResult{Kind: "ConfigMap", Name: "test-cm", Ownership: kube.OwnedByOtherRelease, Owner: "sad-panda"},
+	)
+
+	req := &services.InstallReleaseRequest{
+		Name:  "angry-panda",
+		Chart: chartStub(),
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install to fail on an ownership conflict")
+	}
+	if !strings.Contains(err.Error(), `owned by release "sad-panda"`) {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := rs.env.Releases.Get("angry-panda", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+}
+
+func TestUpdateRelease_PreflightConflict(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.env.KubeClient = newOwnershipKubeClient(
+		&kube.OwnershipResult{Kind: "ConfigMap", Name: "test-cm", Ownership: kube.OwnedByRelease, Owner: rel.Name},
+		&kube.OwnershipResult{Kind: "Service", Name: "test-svc", Ownership: kube.Unmanaged},
+	)
+
+	req := &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: rel.GetChart(),
+	}
+	if _, err := rs.UpdateRelease(c, req); err == nil {
+		t.Fatal("Expected upgrade to fail on an unmanaged resource")
+	} else if !strings.Contains(err.Error(), `Service "test-svc"`) || strings.Contains(err.Error(), "test-cm") {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Error("Expected no new revision to be recorded")
+	}
+}
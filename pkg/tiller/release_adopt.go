@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// AdoptRelease renders a chart and brings the existing resources it describes
+// under the management of a new release.
+//
+// Live objects are annotated with the release annotations, so that later
+// upgrades and deletions of the release manage them. Hooks are neither run nor
+// adopted.
+//
+// If resources of the manifest do not exist, the release is recorded as FAILED
+// with a description that names them, since only an upgrade creates them.
+func (s *ReleaseServer) AdoptRelease(c ctx.Context, req *services.AdoptReleaseRequest) (*services.AdoptReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+
+	s.Log("preparing adoption for %s", req.Name)
+	rel, err := s.prepareRelease(&services.InstallReleaseRequest{
+		Name:      req.Name,
+		Namespace: req.Namespace,
+		Chart:     req.Chart,
+		Values:    req.Values,
+	})
+	res := &services.AdoptReleaseResponse{Release: rel}
+	if err != nil {
+		s.Log("failed adopt prepare step: %s", err)
+		return res, err
+	}
+
+	s.Log("adopting resources for %s", req.Name)
+	b := bytes.NewBufferString(rel.Manifest)
+	adopted, err := s.env.KubeClient.Adopt(rel.Namespace, b, req.DryRun)
+	missing := []string{}
+	for _, a := range adopted {
+		if !a.Found {
+			missing = append(missing, fmt.Sprintf("%s %q", a.Kind, a.Name))
+		}
+		res.Resources = append(res.Resources, &services.AdoptedResource{
+			Kind:      a.Kind,
+			Name:      a.Name,
+			Namespace: a.Namespace,
+			Found:     a.Found,
+			Diff:      a.Diff,
+		})
+	}
+	if err != nil {
+		s.Log("failed adopt step: %s", err)
+		return res, err
+	}
+
+	if req.DryRun {
+		s.Log("dry run for %s", rel.Name)
+		rel.Info.Description = "Dry run complete"
+		return res, nil
+	}
+
+	if len(missing) > 0 {
+		s.Log("adoption of %s is missing resources: %s", rel.Name, strings.Join(missing, ", "))
+		rel.Info.Status.Code = release.Status_FAILED
+		rel.Info.Description = fmt.Sprintf("Adoption incomplete, missing resources: %s", strings.Join(missing, ", "))
+	} else {
+		rel.Info.Status.Code = release.Status_DEPLOYED
+		rel.Info.Description = "Adoption complete"
+	}
+	s.recordRelease(rel, false)
+	return res, nil
+}
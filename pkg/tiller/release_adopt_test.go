@@ -0,0 +1,165 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+type adoptingKubeClient struct {
+	environment.PrintingKubeClient
+	err     error
+	missing bool
+}
+
+func (a *adoptingKubeClient) Adopt(ns string, r io.Reader, dryRun bool) ([]*kube.AdoptResult, error) {
+	res := []*kube.AdoptResult{{Kind: "ConfigMap", Name: "test-cm", Namespace: ns, Found: true}}
+	if a.missing {
+		res = append(res, &kube.AdoptResult{Kind: "Secret", Name: "test-secret", Namespace: ns})
+	}
+	return res, a.err
+}
+
+func TestAdoptRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &adoptingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+
+	req := &services.AdoptReleaseRequest{
+		Name:      "legacy",
+		Namespace: "spaced",
+		Chart:     chartStub(),
+	}
+	res, err := rs.AdoptRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed adopt: %s", err)
+	}
+	if len(res.Resources) != 1 || !res.Resources[0].Found {
+		t.Errorf("unexpected resources: %v", res.Resources)
+	}
+
+	rel, err := rs.env.Releases.Get("legacy", 1)
+	if err != nil {
+		t.Fatalf("Expected release legacy to be recorded: %s", err)
+	}
+	if rel.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected DEPLOYED, got %s", rel.Info.Status.Code)
+	}
+	if !strings.Contains(rel.Manifest, "hello: world") {
+		t.Errorf("unexpected manifest: %s", rel.Manifest)
+	}
+	if rel.Info.Description != "Adoption complete" {
+		t.Errorf("unexpected description: %s", rel.Info.Description)
+	}
+}
+
+func TestAdoptRelease_Missing(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &adoptingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		missing:            true,
+	}
+
+	req := &services.AdoptReleaseRequest{
+		Name:  "legacy",
+		Chart: chartStub(),
+	}
+	res, err := rs.AdoptRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed adopt: %s", err)
+	}
+	if len(res.Resources) != 2 || res.Resources[1].Found {
+		t.Errorf("unexpected resources: %v", res.Resources)
+	}
+
+	rel, err := rs.env.Releases.Get("legacy", 1)
+	if err != nil {
+		t.Fatalf("Expected release legacy to be recorded: %s", err)
+	}
+	if rel.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected FAILED, got %s", rel.Info.Status.Code)
+	}
+	if expect := `Adoption incomplete, missing resources: Secret "test-secret"`; rel.Info.Description != expect {
+		t.Errorf("Expected description %q, got %q", expect, rel.Info.Description)
+	}
+}
+
+func TestAdoptRelease_DryRun(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.AdoptReleaseRequest{
+		Name:   "legacy",
+		Chart:  chartStub(),
+		DryRun: true,
+	}
+	res, err := rs.AdoptRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed adopt: %s", err)
+	}
+	if res.Release.Info.Description != "Dry run complete" {
+		t.Errorf("unexpected description: %s", res.Release.Info.Description)
+	}
+	if _, err := rs.env.Releases.Get("legacy", 1); err == nil {
+		t.Error("Expected no release to be recorded on dry run")
+	}
+}
+
+func TestAdoptRelease_Conflict(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &adoptingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		err:                errors.New("owned by another release"),
+	}
+
+	req := &services.AdoptReleaseRequest{
+		Name:  "legacy",
+		Chart: chartStub(),
+	}
+	if _, err := rs.AdoptRelease(c, req); err == nil {
+		t.Fatal("Expected adopt to fail")
+	}
+	if _, err := rs.env.Releases.Get("legacy", 1); err == nil {
+		t.Error("Expected no release to be recorded on failure")
+	}
+}
+
+func TestAdoptRelease_ExistingName(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(namedReleaseStub("legacy", release.Status_DEPLOYED))
+
+	req := &services.AdoptReleaseRequest{
+		Name:  "legacy",
+		Chart: chartStub(),
+	}
+	if _, err := rs.AdoptRelease(c, req); err == nil {
+		t.Fatal("Expected adopt of an existing release name to fail")
+	}
+}
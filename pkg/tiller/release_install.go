@@ -59,6 +59,9 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 	if req.Chart == nil {
 		return nil, errMissingChart
 	}
+	if chartutil.IsLibraryChart(req.Chart) {
+		return nil, errLibraryChart
+	}
 
 	name, err := s.uniqName(req.Name, req.ReuseName)
 	if err != nil {
@@ -84,7 +87,7 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		return nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
+	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions, req.DryRun)
 	if err != nil {
 		// Return a release with partial data so that client can show debugging
 		// information.
@@ -127,7 +130,7 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		rel.Info.Status.Notes = notesTxt
 	}
 
-	err = validateManifest(s.env.KubeClient, req.Namespace, manifestDoc.Bytes())
+	err = validateManifest(s.env.KubeClient, req.Namespace, manifestDoc.Bytes(), hooks, caps.APIVersions)
 	return rel, err
 }
 
@@ -135,6 +138,14 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
 	res := &services.InstallReleaseResponse{Release: r}
 
+	h, err := s.env.Releases.History(req.Name)
+	replace := req.ReuseName && err == nil && len(h) >= 1
+
+	// Refuse the install up front if any resource cannot be created.
+	if err := s.preflight(r, replace); err != nil {
+		return res, err
+	}
+
 	if req.DryRun {
 		s.Log("dry run for %s", r.Name)
 		res.Release.Info.Description = "Dry run complete"
@@ -150,9 +161,9 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		s.Log("install hooks disabled for %s", req.Name)
 	}
 
-	switch h, err := s.env.Releases.History(req.Name); {
+	switch {
 	// if this is a replace operation, append to the release history
-	case req.ReuseName && err == nil && len(h) >= 1:
+	case replace:
 		s.Log("name reuse for %s requested, replacing release", req.Name)
 		// get latest release revision
 		relutil.Reverse(h, relutil.SortByRevision)
@@ -18,6 +18,7 @@ package tiller
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -280,6 +282,106 @@ func TestInstallRelease_WrongTillerVersion(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_LibraryChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello", Type: "library"},
+			Templates: []*chart.Template{
+				{Name: "templates/_helpers.tpl", Data: []byte(`{{define "hello"}}world{{end}}`)},
+			},
+		},
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err != errLibraryChart {
+		t.Errorf("Expected %q, got %v", errLibraryChart, err)
+	}
+}
+
+func TestInstallRelease_SchemaViolation(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	ch := chartStub()
+	ch.Schema = []byte(`{"type": "object", "properties": {"replicaCount": {"type": "integer"}}, "additionalProperties": false}`)
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart:     ch,
+		Values:    &chart.Config{Raw: "replicaCont: 3\n"},
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install to fail on values not matching the schema")
+	}
+	if !strings.Contains(err.Error(), "replicaCont") {
+		t.Errorf("Expected %q to report replicaCont", err)
+	}
+	if _, err := rs.env.Releases.Get(req.Name, 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+}
+
+// lookupKubeClient finds a single Secret in the cluster.
+type lookupKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (l *lookupKubeClient) Lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	if apiVersion == "v1" && kind == "Secret" && namespace == "spaced" && name == "db" {
+		return map[string]interface{}{"data": map[string]interface{}{"password": "c3RhYmxl"}}, nil
+	}
+	if apiVersion == "v1" && kind == "ConfigMap" && namespace == "other-team" && name == "endpoints" {
+		return map[string]interface{}{"data": map[string]interface{}{"api": "api.other-team"}}, nil
+	}
+	return map[string]interface{}{}, nil
+}
+
+func TestInstallRelease_Lookup(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &lookupKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+
+	ch := chartStub()
+	ch.Templates = append(ch.Templates, &chart.Template{
+		Name: "templates/secret",
+		Data: []byte(`password: {{ with lookup "v1" "Secret" .Release.Namespace "db" }}{{ .data.password }}{{ else }}generated{{ end }}`),
+	}, &chart.Template{
+		Name: "templates/configmap",
+		Data: []byte(`api: {{ (lookup "v1" "ConfigMap" "other-team" "endpoints").data.api }}`),
+	})
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Namespace: "spaced", Chart: ch})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "password: c3RhYmxl") {
+		t.Errorf("Expected the live password in the manifest, got %s", res.Release.Manifest)
+	}
+	if !strings.Contains(res.Release.Manifest, "api: api.other-team") {
+		t.Errorf("Expected the ConfigMap of another namespace in the manifest, got %s", res.Release.Manifest)
+	}
+
+	res, err = rs.InstallRelease(c, &services.InstallReleaseRequest{Namespace: "spaced", Chart: ch, DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "password: generated") {
+		t.Errorf("Expected lookup to find nothing in a dry run, got %s", res.Release.Manifest)
+	}
+
+	rs.DisableLookup = true
+	res, err = rs.InstallRelease(c, &services.InstallReleaseRequest{Namespace: "spaced", Chart: ch, Name: "disabled"})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "password: generated") {
+		t.Errorf("Expected lookup to find nothing when disabled, got %s", res.Release.Manifest)
+	}
+}
+
 func TestInstallRelease_WithChartAndDependencyNotes(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -422,6 +524,31 @@ func TestInstallRelease_FailedHooks(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_ValidationFailed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := newValidationFailingKubeClient()
+	rs.env.KubeClient = kc
+
+	req := &services.InstallReleaseRequest{
+		Name:   "angry-panda",
+		Chart:  chartStub(),
+		DryRun: true,
+	}
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Fatal("Expected validation to fail the dry run")
+	}
+
+	for _, source := range []string{"# Source: hello/templates/hello", "# Source: hello/templates/hooks"} {
+		if !strings.Contains(kc.validated, source) {
+			t.Errorf("Expected %q to be validated, got %s", source, kc.validated)
+		}
+	}
+	if _, err := rs.env.Releases.Get("angry-panda", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+}
+
 func TestInstallRelease_ReuseName(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
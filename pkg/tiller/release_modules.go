@@ -51,22 +51,17 @@ type LocalReleaseModule struct {
 
 // Create creates a release via kubeclient from provided environment
 func (m *LocalReleaseModule) Create(r *release.Release, req *services.InstallReleaseRequest, env *environment.Environment) error {
-	b := bytes.NewBufferString(r.Manifest)
-	return env.KubeClient.Create(r.Namespace, b, req.Timeout, req.Wait)
+	return CreateRelease(r, req.Timeout, req.Wait, env.KubeClient)
 }
 
 // Update performs an update from current to target release
 func (m *LocalReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
-	c := bytes.NewBufferString(current.Manifest)
-	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return UpdateRelease(current, target, req.Force, req.Recreate, req.Timeout, req.Wait, env.KubeClient)
 }
 
 // Rollback performs a rollback from current to target release
 func (m *LocalReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
-	c := bytes.NewBufferString(current.Manifest)
-	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return UpdateRelease(current, target, req.Force, req.Recreate, req.Timeout, req.Wait, env.KubeClient)
 }
 
 // Status returns kubectl-like formatted status of release objects
@@ -80,7 +75,7 @@ func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.Uninstal
 	if err != nil {
 		return rel.Manifest, []error{fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)}
 	}
-	return DeleteRelease(rel, vs, env.KubeClient)
+	return DeleteRelease(rel, vs, env.KubeClient, req.DeleteCrds)
 }
 
 // RemoteReleaseModule is a ReleaseModule which calls Rudder service to operate on a release
@@ -129,7 +124,7 @@ func (m *RemoteReleaseModule) Status(r *release.Release, req *services.GetReleas
 
 // Delete calls rudder.DeleteRelease
 func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []error) {
-	deleteRequest := &rudderAPI.DeleteReleaseRequest{Release: r}
+	deleteRequest := &rudderAPI.DeleteReleaseRequest{Release: r, DeleteCrds: req.DeleteCrds}
 	resp, err := rudder.DeleteRelease(deleteRequest)
 	if err != nil {
 		return resp.Release.Manifest, []error{err}
@@ -137,10 +132,43 @@ func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.Uninstall
 	return resp.Release.Manifest, []error{}
 }
 
+// CreateRelease is a helper that allows Rudder to create a release without exposing most of Tiller inner functions
+//
+// Custom resource definitions are created first. The other resources are
+// created once the definitions are established.
+func CreateRelease(rel *release.Release, timeout int64, wait bool, kubeClient environment.KubeClient) error {
+	crds, rest := splitCRDs(rel.Manifest)
+	if err := applyCRDs(rel.Namespace, nil, crds, timeout, kubeClient); err != nil {
+		return err
+	}
+	if len(crds) > 0 && len(rest) == 0 {
+		return nil
+	}
+	b := bytes.NewBufferString(joinManifest(rest))
+	return kubeClient.Create(rel.Namespace, b, timeout, wait)
+}
+
+// UpdateRelease is a helper that allows Rudder to update a release from current to target without exposing most of Tiller inner functions
+//
+// Custom resource definitions are applied first, and those that were dropped
+// from the target are kept.
+func UpdateRelease(current, target *release.Release, force, recreate bool, timeout int64, wait bool, kubeClient environment.KubeClient) error {
+	currentCRDs, currentRest := splitCRDs(current.Manifest)
+	targetCRDs, targetRest := splitCRDs(target.Manifest)
+	if err := applyCRDs(target.Namespace, currentCRDs, targetCRDs, timeout, kubeClient); err != nil {
+		return err
+	}
+	c := bytes.NewBufferString(joinManifest(currentRest))
+	t := bytes.NewBufferString(joinManifest(targetRest))
+	return kubeClient.Update(target.Namespace, c, t, force, recreate, timeout, wait)
+}
+
 // DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions
-func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, errs []error) {
+//
+// Custom resource definitions are kept unless deleteCRDs is set.
+func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient, deleteCRDs bool) (kept string, errs []error) {
 	manifests := relutil.SplitManifests(rel.Manifest)
-	_, files, err := sortManifests(manifests, vs, UninstallOrder)
+	_, files, err := sortManifests(manifests, withCRDVersions(vs, manifests), UninstallOrder)
 	if err != nil {
 		// We could instead just delete everything in no particular order.
 		// FIXME: One way to delete at this point would be to try a label-based
@@ -153,10 +181,27 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 	if len(filesToKeep) > 0 {
 		kept = summarizeKeptManifests(filesToKeep)
 	}
+	if !deleteCRDs {
+		var crds []manifest
+		crds, filesToDelete = splitCRDManifests(filesToDelete)
+		if len(crds) > 0 {
+			kept = kept + summarizeKeptCRDs(crds)
+		}
+	}
 
+	// Hand the resources to the client one kind at a time, in uninstall
+	// order, so that resources of the same kind are deleted concurrently and
+	// a kind that can no longer be built does not block the others.
 	errs = []error{}
-	for _, file := range filesToDelete {
-		b := bytes.NewBufferString(strings.TrimSpace(file.content))
+	for _, group := range groupManifestsByKind(filesToDelete) {
+		b := bytes.NewBuffer(nil)
+		for _, file := range group {
+			content := strings.TrimSpace(file.content)
+			if len(content) == 0 {
+				continue
+			}
+			b.WriteString("\n---\n" + content)
+		}
 		if b.Len() == 0 {
 			continue
 		}
@@ -171,3 +216,22 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 	}
 	return kept, errs
 }
+
+// groupManifestsByKind splits sorted manifests into runs of the same kind.
+func groupManifestsByKind(files []manifest) [][]manifest {
+	var groups [][]manifest
+	for i, file := range files {
+		if i == 0 || !sameKind(files[i-1], file) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], file)
+	}
+	return groups
+}
+
+func sameKind(a, b manifest) bool {
+	if a.head == nil || b.head == nil {
+		return a.head == b.head
+	}
+	return a.head.Version == b.head.Version && a.head.Kind == b.head.Kind
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -59,6 +60,8 @@ var (
 	errMissingRelease = errors.New("no release provided")
 	// errInvalidRevision indicates that an invalid release revision number was provided.
 	errInvalidRevision = errors.New("invalid release revision")
+	// errLibraryChart indicates that a library chart was provided to be installed.
+	errLibraryChart = errors.New("library charts are not installable")
 )
 
 // ListDefaultLimit is the default limit for number of items returned in a list.
@@ -81,6 +84,11 @@ type ReleaseServer struct {
 	env       *environment.Environment
 	clientset internalclientset.Interface
 	Log       func(string, ...interface{})
+
+	// DisableLookup keeps the 'lookup' template function from reading live
+	// objects, so that it always returns an empty map. Objects are read with
+	// Tiller's credentials otherwise.
+	DisableLookup bool
 }
 
 // NewReleaseServer creates a new release server.
@@ -205,6 +213,23 @@ func (s *ReleaseServer) engine(ch *chart.Chart) environment.Engine {
 	return renderer
 }
 
+// lookupEngine returns a copy of the Go template engine whose 'lookup' function
+// reads live objects with the kube client, in any namespace that Tiller's
+// service account may read. Other engines are returned as is.
+//
+// The engines of the EngineYard are shared by all requests, so they are not
+// modified. If DisableLookup is set, or without a kube client, 'lookup'
+// returns an empty map, like in dry runs.
+func (s *ReleaseServer) lookupEngine(renderer environment.Engine) environment.Engine {
+	e, ok := renderer.(*engine.Engine)
+	if !ok || s.DisableLookup || s.env.KubeClient == nil {
+		return renderer
+	}
+	c := *e
+	c.Lookup = s.env.KubeClient.Lookup
+	return &c
+}
+
 // capabilities builds a Capabilities from discovery information.
 func capabilities(disc discovery.DiscoveryInterface) (*chartutil.Capabilities, error) {
 	sv, err := disc.ServerVersion()
@@ -241,7 +266,7 @@ func GetVersionSet(client discovery.ServerGroupsInterface) (chartutil.VersionSet
 	return chartutil.NewVersionSet(versions...), nil
 }
 
-func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet) ([]*release.Hook, *bytes.Buffer, string, error) {
+func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet, dryRun bool) ([]*release.Hook, *bytes.Buffer, string, error) {
 	// Guard to make sure Tiller is at the right version to handle this chart.
 	sver := version.GetVersion()
 	if ch.Metadata.TillerVersion != "" &&
@@ -251,6 +276,9 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 
 	s.Log("rendering %s chart using values", ch.GetMetadata().Name)
 	renderer := s.engine(ch)
+	if !dryRun {
+		renderer = s.lookupEngine(renderer)
+	}
 	files, err := renderer.Render(ch, values)
 	if err != nil {
 		return nil, nil, "", err
@@ -276,7 +304,7 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 	// Sort hooks, manifests, and partials. Only hooks and manifests are returned,
 	// as partials are not used after renderer.Render. Empty manifests are also
 	// removed here.
-	hooks, manifests, err := sortManifests(files, vs, InstallOrder)
+	hooks, manifests, err := sortTemplates(ch, files, vs)
 	if err != nil {
 		// By catching parse errors here, we can prevent bogus releases from going
 		// to Kubernetes.
@@ -354,8 +382,24 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 	return nil
 }
 
-func validateManifest(c environment.KubeClient, ns string, manifest []byte) error {
-	r := bytes.NewReader(manifest)
+// validateManifest checks the manifest and the hooks of a release against the
+// schema of the cluster, then makes sure that the manifest can be decoded.
+//
+// Resources whose API version is not in apis yet, because it is served by a
+// custom resource definition of the release, are skipped.
+func validateManifest(c environment.KubeClient, ns string, manifest []byte, hooks []*release.Hook, apis chartutil.VersionSet) error {
+	served := servedOnly(string(manifest), apis)
+
+	b := bytes.NewBufferString(served)
+	for _, h := range hooks {
+		b.WriteString("\n---\n# Source: " + h.Path + "\n")
+		b.WriteString(h.Manifest)
+	}
+	if err := c.Validate(bytes.NewBufferString(servedOnly(b.String(), apis))); err != nil {
+		return err
+	}
+
+	r := bytes.NewBufferString(served)
 	_, err := c.BuildUnstructured(ns, r)
 	return err
 }
@@ -19,6 +19,7 @@ package tiller
 import (
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"regexp"
 	"testing"
@@ -323,6 +324,26 @@ func (h *hookFailingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout
 	return errors.New("Failed watch")
 }
 
+func newValidationFailingKubeClient() *validationFailingKubeClient {
+	return &validationFailingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+	}
+}
+
+type validationFailingKubeClient struct {
+	environment.PrintingKubeClient
+	validated string
+}
+
+func (v *validationFailingKubeClient) Validate(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	v.validated = string(b)
+	return errors.New("manifest validation failed")
+}
+
 type mockListServer struct {
 	val *services.ListReleasesResponse
 }
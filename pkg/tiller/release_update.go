@@ -67,6 +67,9 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 	if req.Chart == nil {
 		return nil, nil, errMissingChart
 	}
+	if chartutil.IsLibraryChart(req.Chart) {
+		return nil, nil, errLibraryChart
+	}
 
 	// finds the non-deleted release with the given name
 	currentRelease, err := s.env.Releases.Last(req.Name)
@@ -101,7 +104,7 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		return nil, nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
+	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions, req.DryRun)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -126,13 +129,18 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 	if len(notesTxt) > 0 {
 		updatedRelease.Info.Status.Notes = notesTxt
 	}
-	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
+	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes(), hooks, caps.APIVersions)
 	return currentRelease, updatedRelease, err
 }
 
 func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	res := &services.UpdateReleaseResponse{Release: updatedRelease}
 
+	// Refuse the upgrade up front if any resource belongs to someone else.
+	if err := s.preflight(updatedRelease, true); err != nil {
+		return res, err
+	}
+
 	if req.DryRun {
 		s.Log("dry run for %s", updatedRelease.Name)
 		res.Release.Info.Description = "Dry run complete"
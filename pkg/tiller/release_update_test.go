@@ -239,6 +239,25 @@ func TestUpdateReleaseFailure(t *testing.T) {
 	}
 }
 
+func TestUpdateRelease_ValidationFailed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.env.KubeClient = newValidationFailingKubeClient()
+
+	req := &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: rel.GetChart(),
+	}
+	if _, err := rs.UpdateRelease(c, req); err == nil {
+		t.Fatal("Expected validation to fail the upgrade")
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Error("Expected no new revision to be recorded")
+	}
+}
+
 func TestUpdateReleaseNoHooks(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -54,6 +54,19 @@ func TestClientConfig(t *testing.T) {
 	}
 }
 
+func TestClientConfigWithoutCertificate(t *testing.T) {
+	cfg, err := ClientConfig(Options{CaCertFile: testfile(t, testCaCertFile)})
+	if err != nil {
+		t.Fatalf("error building tls client config: %v", err)
+	}
+	if got := len(cfg.Certificates); got != 0 {
+		t.Fatalf("expecting no client certificates, got %d", got)
+	}
+	if cfg.RootCAs == nil {
+		t.Fatalf("mismatch tls RootCAs, expecting non-nil")
+	}
+}
+
 func TestServerConfig(t *testing.T) {
 	opts := Options{
 		CaCertFile: testfile(t, testCaCertFile),